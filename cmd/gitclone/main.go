@@ -6,12 +6,14 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/NicabarNimble/go-gittools/internal/buildinfo"
 	"github.com/NicabarNimble/go-gittools/internal/gitutils"
 )
 
 var (
-	customName string
-	token     string
+	customName        string
+	token             string
+	syncDefaultBranch bool
 	// cloneFunc allows for mocking in tests
 	cloneFunc = gitutils.CloneRepository
 )
@@ -41,6 +43,8 @@ Example usage:
 	rootCmd.Flags().StringVar(&customName, "name", "", "Custom name for the target repository")
 	// Token flag is now optional as we'll try to get it automatically
 	rootCmd.Flags().StringVar(&token, "token", "", "GitHub token for authentication (optional)")
+	rootCmd.Flags().BoolVar(&syncDefaultBranch, "sync-default-branch", false, "Set the target's default branch to match the source")
+	buildinfo.AddTo(rootCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
@@ -50,11 +54,12 @@ Example usage:
 
 func cloneRepository(sourceURL string) error {
 	opts := gitutils.CloneOptions{
-		SourceURL:  sourceURL,
-		WorkingDir: "",
-		Verbose:    true,
-		Token:      token,
-		CustomName: customName,
+		SourceURL:         sourceURL,
+		WorkingDir:        "",
+		Verbose:           true,
+		Token:             token,
+		CustomName:        customName,
+		SyncDefaultBranch: syncDefaultBranch,
 	}
 
 	// CloneRepository will handle exit codes directly for repository exists case
@@ -5,14 +5,20 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/NicabarNimble/go-gittools/internal/buildinfo"
 	gerrors "github.com/NicabarNimble/go-gittools/internal/errors"
 	"github.com/NicabarNimble/go-gittools/internal/git"
 	"github.com/NicabarNimble/go-gittools/internal/github"
+	"github.com/NicabarNimble/go-gittools/internal/gitlab"
+	"github.com/NicabarNimble/go-gittools/internal/hosting"
 	"github.com/NicabarNimble/go-gittools/internal/progress"
+	pubevents "github.com/NicabarNimble/go-gittools/internal/publish"
 	"github.com/NicabarNimble/go-gittools/internal/token"
 	"github.com/NicabarNimble/go-gittools/internal/urlutils"
 )
@@ -26,7 +32,15 @@ type config struct {
 	prTitle       string
 	prDescription string
 	targetBranch  string
+	draft         bool
+	reviewers     []string
+	labels        []string
 	createFork    bool
+	rollback      bool
+	waitForMerge  bool
+	waitTimeout   time.Duration
+	version       bool
+	versionJSON   bool
 }
 
 func parseFlags() *config {
@@ -35,19 +49,38 @@ func parseFlags() *config {
 	flag.StringVar(&cfg.private, "private", "", "Private repository path")
 	flag.StringVar(&cfg.publicFork, "public", "", "Public fork repository URL")
 	flag.StringVar(&cfg.branch, "branch", "main", "Branch to publish")
-	flag.StringVar(&cfg.token, "token", "", "GitHub token for authentication")
+	flag.StringVar(&cfg.token, "token", "", "GitHub or GitLab token for authentication")
 
 	// PR-related flags
-	flag.BoolVar(&cfg.createPR, "pr", false, "Create a pull request after publishing")
+	flag.BoolVar(&cfg.createPR, "pr", false, "Create a pull request (merge request on GitLab) after publishing")
 	flag.StringVar(&cfg.prTitle, "pr-title", "", "Title for the pull request")
 	flag.StringVar(&cfg.prDescription, "pr-desc", "", "Description for the pull request")
 	flag.StringVar(&cfg.targetBranch, "target-branch", "main", "Target branch for the pull request")
+	flag.BoolVar(&cfg.draft, "draft", false, "Open the pull request as a draft")
+	flag.Func("reviewer", "Request a review from a user, or org/team on GitHub (repeatable, comma-separated)", func(v string) error {
+		cfg.reviewers = append(cfg.reviewers, splitList(v)...)
+		return nil
+	})
+	flag.Func("label", "Add a label to the pull request (repeatable, comma-separated)", func(v string) error {
+		cfg.labels = append(cfg.labels, splitList(v)...)
+		return nil
+	})
 
 	// Fork-related flag
 	flag.BoolVar(&cfg.createFork, "create-fork", false, "Create a fork if it doesn't exist")
+	flag.BoolVar(&cfg.waitForMerge, "wait-for-merge", false, "Wait until the pull request is merged or closed; exits 0 when merged, 3 when closed and 4 on timeout")
+	flag.DurationVar(&cfg.waitTimeout, "wait-timeout", 30*time.Minute, "How long --wait-for-merge waits before giving up")
+	flag.BoolVar(&cfg.rollback, "rollback", false, "Delete a fork created by this run if a later step fails (GitHub tokens need the delete_repo scope)")
+
+	flag.BoolVar(&cfg.version, "version", false, "Print version information and exit")
+	flag.BoolVar(&cfg.versionJSON, "json", false, "With -version, print version information as JSON")
 
 	flag.Parse()
 
+	if cfg.version {
+		return cfg
+	}
+
 	// In test mode, panic instead of exiting
 	isTest := flag.Lookup("test.v") != nil
 
@@ -61,6 +94,16 @@ func parseFlags() *config {
 		os.Exit(1)
 	}
 
+	if cfg.waitForMerge && !cfg.createPR {
+		msg := "Error: wait-for-merge requires pr"
+		if isTest {
+			panic(msg)
+		}
+		fmt.Println(msg)
+		flag.Usage()
+		os.Exit(1)
+	}
+
 	if cfg.createPR && cfg.prTitle == "" {
 		msg := "Error: pr-title is required when creating a pull request"
 		if isTest {
@@ -74,8 +117,31 @@ func parseFlags() *config {
 	return cfg
 }
 
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func main() {
+	// "go-gitpublish version [-json]" mirrors the version subcommand of the other tools
+	if len(os.Args) > 1 && os.Args[1] == "version" {
+		os.Exit(runVersion(os.Args[2:]))
+	}
+
 	cfg := parseFlags()
+	if cfg.version {
+		if err := buildinfo.Write(os.Stdout, "go-gitpublish", cfg.versionJSON); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	// Initialize progress tracker
 	tracker := &progress.DefaultTracker{}
@@ -83,10 +149,53 @@ func main() {
 	// Perform publish operation
 	if err := publishRepository(cfg, tracker); err != nil {
 		fmt.Printf("Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
+	}
+}
+
+// Exit codes reporting how --wait-for-merge ended, besides 0 for merged
+// and 1 for any other error
+const (
+	exitPRClosed    = 3
+	exitWaitTimeout = 4
+)
+
+var (
+	// errPRClosed is returned when a pull request is closed without
+	// merging while waiting for it
+	errPRClosed = errors.New("pull request was closed without merging")
+
+	// errWaitTimeout is returned when a pull request is still open once
+	// the wait timeout expires
+	errWaitTimeout = errors.New("timed out waiting for the pull request to merge")
+)
+
+// exitCode maps a publish error to the process exit code
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, errPRClosed):
+		return exitPRClosed
+	case errors.Is(err, errWaitTimeout):
+		return exitWaitTimeout
+	default:
+		return 1
 	}
 }
 
+// runVersion handles the version subcommand and returns the exit code
+func runVersion(args []string) int {
+	fs := flag.NewFlagSet("version", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "Print version information as JSON")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if err := buildinfo.Write(os.Stdout, "go-gitpublish", *asJSON); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
 // parseGitHubURL extracts owner and repo from a GitHub URL
 func parseGitHubURL(rawURL string) (owner, repo string, err error) {
 	// Only accept HTTPS URLs
@@ -111,91 +220,401 @@ func parseGitHubURL(rawURL string) (owner, repo string, err error) {
 	return pathParts[0], repo, nil
 }
 
+// parseEnterpriseURL extracts the owner/repo path from a repository URL on
+// a GitHub Enterprise Server host, which parseGitHubURL does not accept
+func parseEnterpriseURL(rawURL string) (string, error) {
+	parsedURL, err := url.Parse(strings.TrimSuffix(rawURL, ".git"))
+	if err != nil || parsedURL.Scheme != "https" {
+		return "", fmt.Errorf("invalid GitHub Enterprise URL: %s", rawURL)
+	}
+	pathParts := strings.Split(strings.Trim(parsedURL.Path, "/"), "/")
+	if len(pathParts) != 2 || pathParts[0] == "" || pathParts[1] == "" {
+		return "", fmt.Errorf("URL must include owner and repository")
+	}
+	return pathParts[0] + "/" + pathParts[1], nil
+}
+
+// parseGitLabURL extracts the project path (group/project) from a GitLab URL
+func parseGitLabURL(rawURL string) (string, error) {
+	parsedURL, err := urlutils.ParseGitLabURL(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid GitLab URL: %w", err)
+	}
+	return strings.Trim(parsedURL.Path, "/"), nil
+}
+
+// cloneRepository is a variable so it can be mocked in tests
+var cloneRepository = git.CloneRepository
+
+// branchUpToDate is a variable so it can be mocked in tests
+var branchUpToDate = git.BranchUpToDate
+
+// stderr receives warnings. It is a variable so it can be mocked in tests.
+var stderr io.Writer = os.Stderr
+
 func publishRepository(cfg *config, tracker progress.Tracker) error {
 	ctx := context.Background()
+
+	provider, err := newProvider(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return publish(ctx, provider, cfg, tracker, pubevents.Printer(os.Stdout))
+}
+
+// newProvider creates a validated hosting provider for the host of the
+// public fork URL. Hosts that are neither GitHub nor GitLab are probed as
+// GitHub Enterprise Server instances.
+func newProvider(ctx context.Context, cfg *config) (hosting.Provider, error) {
+	kind, err := urlutils.DetectProvider(cfg.publicFork)
+	if errors.Is(err, urlutils.ErrInvalidHost) {
+		return newGitHubProvider(ctx, cfg)
+	}
+	if err != nil {
+		return nil, gerrors.New("publish", fmt.Errorf("unsupported public fork URL: %w", err))
+	}
+	if kind == token.ProviderGitLab {
+		return newGitLabProvider(ctx, cfg)
+	}
+	return newGitHubProvider(ctx, cfg)
+}
+
+// newGitHubProvider validates the token and creates a GitHub provider
+func newGitHubProvider(ctx context.Context, cfg *config) (hosting.Provider, error) {
 	// Create and validate token
 	t, err := token.NewToken(cfg.token, time.Time{}, "repo workflow admin:repo")
 	if err != nil {
 		if errors.Is(err, token.ErrTokenInvalid) {
-			return gerrors.New("publish", fmt.Errorf("invalid GitHub token format"))
+			return nil, gerrors.New("publish", fmt.Errorf("invalid GitHub token format"))
 		}
-		return gerrors.New("publish", fmt.Errorf("failed to create token: %w", err))
+		return nil, gerrors.New("publish", fmt.Errorf("failed to create token: %w", err))
 	}
 
-	// Pre-validate token with GitHub API
-	validator := github.NewTokenValidator()
-	if err := validator.Validate(ctx, t); err != nil {
-		if strings.Contains(err.Error(), "missing required scopes") {
-			return gerrors.New("publish", fmt.Errorf("GitHub token is missing required scopes (repo, workflow, admin:repo). Please check token permissions"))
+	// Target the API serving the public fork so GitHub Enterprise forks
+	// don't validate the token against github.com
+	baseURL, err := github.DetectAPIBaseURL(ctx, cfg.publicFork)
+	if err != nil {
+		return nil, gerrors.New("publish", fmt.Errorf("failed to detect GitHub API: %w", err))
+	}
+
+	// Create GitHub client, which validates the token with the API
+	ghClient, err := github.NewClient(ctx, t, github.WithBaseURL(baseURL))
+	if err != nil {
+		if errors.Is(err, token.ErrScopeMissing) {
+			return nil, gerrors.New("publish", fmt.Errorf("GitHub token is missing required scopes (repo, workflow, admin:repo). Please check token permissions"))
 		}
-		return gerrors.New("publish", fmt.Errorf("GitHub token validation failed: %w", err))
+		return nil, gerrors.New("publish", fmt.Errorf("failed to create GitHub client: %w", err))
 	}
+	return hosting.NewGitHub(ghClient), nil
+}
 
-	// Create GitHub client with validated token
-	ghClient, err := github.NewClient(ctx, t)
+// newGitLabProvider validates the token and creates a GitLab provider for
+// the instance hosting the public fork
+func newGitLabProvider(ctx context.Context, cfg *config) (hosting.Provider, error) {
+	publicURL, err := urlutils.ParseGitLabURL(cfg.publicFork)
 	if err != nil {
-		return gerrors.New("publish", fmt.Errorf("failed to create GitHub client: %w", err))
+		return nil, gerrors.New("publish", fmt.Errorf("invalid GitLab URL: %w", err))
 	}
 
-	// Create fork if requested
-	if cfg.createFork {
-		targetOwner, targetRepo, err := parseGitHubURL(cfg.private)
-		if err != nil {
-			return gerrors.New("publish", fmt.Errorf("failed to parse target repository URL: %w", err))
+	t, err := token.NewToken(cfg.token, time.Time{}, "api")
+	if err != nil {
+		if errors.Is(err, token.ErrTokenInvalid) {
+			return nil, gerrors.New("publish", fmt.Errorf("invalid GitLab token format"))
+		}
+		return nil, gerrors.New("publish", fmt.Errorf("failed to create token: %w", err))
+	}
+
+	apiURL := fmt.Sprintf("https://%s/api/v4", publicURL.Host)
+	glClient, err := gitlab.NewClient(ctx, t, gitlab.WithBaseURL(apiURL))
+	if err != nil {
+		return nil, gerrors.New("publish", fmt.Errorf("failed to create GitLab client: %w", err))
+	}
+	return hosting.NewGitLab(glClient), nil
+}
+
+// parseRepoPath returns the owner/repo (GitHub) or group/project (GitLab)
+// path of a repository URL. Unknown hosts are taken to be GitHub Enterprise
+// Server instances, as newProvider only accepts them after probing.
+func parseRepoPath(rawURL string) (string, error) {
+	kind, err := urlutils.DetectProvider(rawURL)
+	if errors.Is(err, urlutils.ErrInvalidHost) {
+		return parseEnterpriseURL(rawURL)
+	}
+	if err != nil {
+		return "", err
+	}
+	if kind == token.ProviderGitLab {
+		return parseGitLabURL(rawURL)
+	}
+	owner, repo, err := parseGitHubURL(rawURL)
+	if err != nil {
+		return "", err
+	}
+	return owner + "/" + repo, nil
+}
+
+// publish optionally forks the private repository, pushes it to the public
+// fork and opens a pull or merge request, independent of the provider. Each
+// completed step is reported to events, which may be nil.
+func publish(ctx context.Context, provider hosting.Provider, cfg *config, tracker progress.Tracker, events pubevents.Handler) error {
+	emit := func(e pubevents.Event) {
+		if events != nil {
+			events(e)
 		}
-		fmt.Printf("Creating fork of %s/%s...\n", targetOwner, targetRepo)
-		if err := ghClient.CreateFork(ctx, fmt.Sprintf("%s/%s", targetOwner, targetRepo)); err != nil {
-			return gerrors.New("publish", fmt.Errorf("failed to create fork: %w", err))
+	}
+
+	if cfg.waitForMerge {
+		if _, ok := provider.(hosting.ChangeRequestWatcher); !ok {
+			return gerrors.New("publish", fmt.Errorf("waiting for merge is not supported by this provider"))
 		}
 	}
 
-	// Clone private repository to temporary location
-	tempDir, err := os.MkdirTemp("", "gitpublish-*")
+	// Create fork if requested
+	state, err := createFork(ctx, provider, cfg, emit)
 	if err != nil {
-		return gerrors.New("publish", fmt.Errorf("failed to create temp directory: %w", err))
+		return err
 	}
-	defer os.RemoveAll(tempDir)
 
 	// Clone and push repository
 	cloneOpts := git.CloneOptions{
-		SourceURL:  cfg.private,
-		TargetURL:  cfg.publicFork,
-		Token:      cfg.token,
-		Progress:   tracker,
-	}
-	if err := git.CloneRepository(cloneOpts); err != nil {
-		return gerrors.New("publish", fmt.Errorf("failed to push to public fork: %w", err))
+		SourceURL: cfg.private,
+		TargetURL: cfg.publicFork,
+		Token:     cfg.token,
+		Progress:  tracker,
 	}
 
-	fmt.Printf("Successfully published %s to %s\n", cfg.private, cfg.publicFork)
+	// Nothing to push if the fork's branch already matches. A failed check,
+	// e.g. for a fork that does not exist yet, publishes as usual. The pull
+	// request is still looked up or opened, since an earlier run may have
+	// pushed the branch and then failed to open it.
+	if upToDate, err := branchUpToDate(cloneOpts, cfg.branch); err == nil && upToDate {
+		emit(pubevents.Event{Type: pubevents.UpToDate, Source: cfg.private, Target: cfg.publicFork})
+		if !cfg.createPR {
+			return nil
+		}
+	} else {
+		if size := repositorySize(ctx, provider, cfg.private); size > 0 {
+			cloneOpts.CheckDiskSpace = true
+			cloneOpts.EstimatedSize = size
+		}
+		if err := cloneRepository(cloneOpts); err != nil {
+			err = gerrors.New("publish", fmt.Errorf("failed to push to public fork: %w", err))
+			return rollback(ctx, provider, state, emit, err)
+		}
+
+		emit(pubevents.Event{Type: pubevents.Pushed, Source: cfg.private, Target: cfg.publicFork})
+	}
 
 	// Create pull request if requested
 	if cfg.createPR {
-		sourceOwner, _, err := parseGitHubURL(cfg.publicFork)
+		url, reused, err := createChangeRequest(ctx, provider, cfg)
 		if err != nil {
-			return gerrors.New("publish", fmt.Errorf("failed to parse source repository URL: %w", err))
+			return rollback(ctx, provider, state, emit, gerrors.New("publish", err))
 		}
+		eventType := pubevents.PRCreated
+		if reused {
+			eventType = pubevents.PRReused
+		}
+		emit(pubevents.Event{
+			Type:   eventType,
+			Source: cfg.publicFork,
+			Target: cfg.private,
+			Title:  cfg.prTitle,
+			URL:    url,
+		})
+
+		if cfg.waitForMerge {
+			if err := waitForMerge(ctx, provider, url, cfg.waitTimeout); err != nil {
+				return gerrors.New("publish", err)
+			}
+			emit(pubevents.Event{Type: pubevents.PRMerged, Source: cfg.publicFork, Target: cfg.private, URL: url})
+		}
+	}
 
-		targetOwner, targetRepo, err := parseGitHubURL(cfg.private)
+	return nil
+}
+
+// mergePollInterval is how often waitForMerge checks the pull request. It
+// is a variable so it can be shortened in tests.
+var mergePollInterval = 15 * time.Second
+
+// waitForMerge polls the pull or merge request at url until it is merged,
+// returning errPRClosed if it is closed instead and errWaitTimeout if it
+// is still open after timeout
+func waitForMerge(ctx context.Context, provider hosting.Provider, url string, timeout time.Duration) error {
+	watcher, ok := provider.(hosting.ChangeRequestWatcher)
+	if !ok {
+		return fmt.Errorf("waiting for merge is not supported by this provider")
+	}
+	if url == "" {
+		return fmt.Errorf("cannot wait for merge: the pull request URL is unknown")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(mergePollInterval)
+	defer ticker.Stop()
+	for {
+		state, err := watcher.ChangeRequestState(ctx, url)
 		if err != nil {
-			return gerrors.New("publish", fmt.Errorf("failed to parse target repository URL: %w", err))
+			if ctx.Err() != nil {
+				return fmt.Errorf("%w after %s: %s", errWaitTimeout, timeout, url)
+			}
+			return fmt.Errorf("failed to check pull request state: %w", err)
+		}
+		switch state {
+		case hosting.ChangeRequestMerged:
+			return nil
+		case hosting.ChangeRequestClosed:
+			return fmt.Errorf("%w: %s", errPRClosed, url)
 		}
 
-		prOpts := github.PROptions{
-			Owner: targetOwner,
-			Repo:  targetRepo,
-			Base:  cfg.targetBranch,
-			Head:  fmt.Sprintf("%s:%s", sourceOwner, cfg.branch),
-			Title: cfg.prTitle,
-			Body:  cfg.prDescription,
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w after %s: %s", errWaitTimeout, timeout, url)
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishState records what a publish run created, so a failed run can be
+// rolled back without touching anything that already existed
+type publishState struct {
+	forkPath    string
+	forkCreated bool // the fork did not exist before this run
+}
+
+// createFork forks the private repository if requested. With rollback
+// enabled the fork's path is looked up first, so the returned state only
+// marks the fork as created when this run made it.
+func createFork(ctx context.Context, provider hosting.Provider, cfg *config, emit func(pubevents.Event)) (publishState, error) {
+	var state publishState
+	if !cfg.createFork {
+		return state, nil
+	}
+
+	privatePath, err := parseRepoPath(cfg.private)
+	if err != nil {
+		return state, gerrors.New("publish", fmt.Errorf("failed to parse target repository URL: %w", err))
+	}
+
+	existed := true
+	if cfg.rollback {
+		deleter, ok := provider.(hosting.RepositoryDeleter)
+		if !ok {
+			return state, gerrors.New("publish", fmt.Errorf("rollback is not supported by this provider"))
+		}
+		// The fork is created in the token owner's namespace, which need
+		// not be where the public fork URL points
+		forkPath, err := deleter.ForkPath(ctx, privatePath)
+		if err != nil {
+			return state, gerrors.New("publish", fmt.Errorf("failed to determine fork path: %w", err))
+		}
+		existed, err = deleter.RepositoryExists(ctx, forkPath)
+		if err != nil {
+			return state, gerrors.New("publish", fmt.Errorf("failed to check for existing fork: %w", err))
 		}
+		state.forkPath = forkPath
+	}
+
+	if err := provider.CreateFork(ctx, privatePath); err != nil {
+		return state, gerrors.New("publish", fmt.Errorf("failed to create fork: %w", err))
+	}
+	state.forkCreated = !existed
+	emit(pubevents.Event{Type: pubevents.ForkCreated, Source: privatePath})
+	return state, nil
+}
 
-		fmt.Printf("Creating pull request from %s to %s/%s...\n", prOpts.Head, targetOwner, targetRepo)
-		if err := ghClient.CreatePullRequest(ctx, prOpts); err != nil {
-			return gerrors.New("publish", fmt.Errorf("failed to create pull request: %w", err))
+// rollback deletes the fork if this run created it and returns err, joined
+// with the deletion failure if the fork could not be removed
+func rollback(ctx context.Context, provider hosting.Provider, state publishState, emit func(pubevents.Event), err error) error {
+	if !state.forkCreated {
+		return err
+	}
+	deleter, ok := provider.(hosting.RepositoryDeleter)
+	if !ok {
+		return err
+	}
+	if delErr := deleter.DeleteRepository(ctx, state.forkPath); delErr != nil {
+		return errors.Join(err, gerrors.New("rollback", fmt.Errorf("failed to delete fork %s: %w", state.forkPath, delErr)))
+	}
+	emit(pubevents.Event{Type: pubevents.ForkDeleted, Source: state.forkPath})
+	return err
+}
+
+// repositorySize returns the size of the repository at repoURL in bytes when
+// the provider can report it, or zero so the disk space check is skipped
+func repositorySize(ctx context.Context, provider hosting.Provider, repoURL string) int64 {
+	sizer, ok := provider.(hosting.SizeReporter)
+	if !ok {
+		return 0
+	}
+	repoPath, err := parseRepoPath(repoURL)
+	if err != nil {
+		return 0
+	}
+	size, err := sizer.RepositorySize(ctx, repoPath)
+	if err != nil {
+		return 0
+	}
+	return size
+}
+
+// createChangeRequest opens a pull request (merge request on GitLab) from
+// the public fork's branch into the private repository's target branch and
+// returns its web URL. If the provider finds an open request for the same
+// branches, that one is returned with reused set instead of opening a
+// duplicate. Reviewers and labels are applied to the request either way;
+// failing to apply them only prints a warning, since the request exists.
+func createChangeRequest(ctx context.Context, provider hosting.Provider, cfg *config) (url string, reused bool, err error) {
+	sourcePath, err := parseRepoPath(cfg.publicFork)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to parse source repository URL: %w", err)
+	}
+	targetPath, err := parseRepoPath(cfg.private)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to parse target repository URL: %w", err)
+	}
+
+	opts := hosting.ChangeRequestOptions{
+		SourceRepo:   sourcePath,
+		SourceBranch: cfg.branch,
+		TargetRepo:   targetPath,
+		TargetBranch: cfg.targetBranch,
+		Title:        cfg.prTitle,
+		Body:         cfg.prDescription,
+		Draft:        cfg.draft,
+		Reviewers:    cfg.reviewers,
+		Labels:       cfg.labels,
+	}
+	if finder, ok := provider.(hosting.ChangeRequestFinder); ok {
+		url, err := finder.FindChangeRequest(ctx, opts)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to look up open pull requests: %w", err)
+		}
+		if url != "" {
+			annotateChangeRequest(ctx, provider, url, opts)
+			return url, true, nil
 		}
-		fmt.Printf("Successfully created pull request: %s\n", cfg.prTitle)
 	}
 
-	return nil
+	url, err = provider.CreatePullOrMergeRequest(ctx, opts)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to create pull request: %w", err)
+	}
+	annotateChangeRequest(ctx, provider, url, opts)
+	return url, false, nil
+}
+
+// annotateChangeRequest applies opts' reviewers and labels to the request
+// at url when the provider supports it, warning on failure
+func annotateChangeRequest(ctx context.Context, provider hosting.Provider, url string, opts hosting.ChangeRequestOptions) {
+	annotator, ok := provider.(hosting.ChangeRequestAnnotator)
+	if !ok || url == "" || (len(opts.Reviewers) == 0 && len(opts.Labels) == 0) {
+		return
+	}
+	if err := annotator.AnnotateChangeRequest(ctx, url, opts); err != nil {
+		fmt.Fprintf(stderr, "Warning: failed to add reviewers or labels to %s: %v\n", url, err)
+	}
 }
@@ -1,18 +1,42 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	gerrors "github.com/NicabarNimble/go-gittools/internal/errors"
 	"github.com/NicabarNimble/go-gittools/internal/github"
+	"github.com/NicabarNimble/go-gittools/internal/gitlab"
+	"github.com/NicabarNimble/go-gittools/internal/hosting"
 	"github.com/NicabarNimble/go-gittools/internal/progress"
+	pubevents "github.com/NicabarNimble/go-gittools/internal/publish"
 	"github.com/NicabarNimble/go-gittools/internal/git"
+	"github.com/NicabarNimble/go-gittools/internal/token"
 	"github.com/stretchr/testify/assert"
 )
 
+func TestMain(m *testing.M) {
+	// Publish tests use real-looking URLs, so keep the up-to-date check
+	// off the network unless a test restores it
+	branchUpToDate = func(opts git.CloneOptions, branch string) (bool, error) {
+		return false, nil
+	}
+	os.Exit(m.Run())
+}
+
 func TestParseFlags(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -80,6 +104,47 @@ func TestParseFlags(t *testing.T) {
 				assert.True(t, cfg.createFork)
 			},
 		},
+		{
+			name: "Draft PR",
+			args: []string{
+				"-private", "https://github.com/user/private-repo",
+				"-public", "https://github.com/user/public-fork",
+				"-pr",
+				"-pr-title", "New Feature",
+				"-draft",
+			},
+			expectError: false,
+			validate: func(t *testing.T, cfg *config) {
+				assert.True(t, cfg.createPR)
+				assert.True(t, cfg.draft)
+			},
+		},
+		{
+			name: "Reviewers and labels",
+			args: []string{
+				"-private", "https://github.com/user/private-repo",
+				"-public", "https://github.com/user/public-fork",
+				"-pr",
+				"-pr-title", "New Feature",
+				"-reviewer", "alice, org/maintainers",
+				"-reviewer", "bob",
+				"-label", "release",
+			},
+			expectError: false,
+			validate: func(t *testing.T, cfg *config) {
+				assert.Equal(t, []string{"alice", "org/maintainers", "bob"}, cfg.reviewers)
+				assert.Equal(t, []string{"release"}, cfg.labels)
+			},
+		},
+		{
+			name: "Wait for merge without PR",
+			args: []string{
+				"-private", "https://github.com/user/private-repo",
+				"-public", "https://github.com/user/public-fork",
+				"-wait-for-merge",
+			},
+			expectError: true,
+		},
 		{
 			name: "PR without title",
 			args: []string{
@@ -402,3 +467,670 @@ func (m *mockGitHubClient) GetWorkflowLogs(ctx context.Context, owner, repo stri
 func (m *mockGitHubClient) ListWorkflowRuns(ctx context.Context, owner, repo, workflowID string) ([]github.WorkflowRun, error) {
 	return nil, nil
 }
+
+func TestCreateMergeRequest(t *testing.T) {
+	var mrPayload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/user":
+			w.Header().Set("X-Gitlab-Scopes", "api")
+			w.Write([]byte(`{"id": 1, "username": "user"}`))
+		case "/projects/group%2Fprivate-repo":
+			w.Write([]byte(`{"id": 99, "path_with_namespace": "group/private-repo"}`))
+		case "/projects/user%2Fpublic-fork/merge_requests":
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&mrPayload))
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 500, "iid": 5, "title": "New Feature", "state": "opened", "web_url": "https://gitlab.com/group/private-repo/-/merge_requests/5"}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.EscapedPath())
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	tok, err := token.NewToken("glpat-test", time.Time{}, "api")
+	assert.NoError(t, err)
+	client, err := gitlab.NewClient(ctx, tok, gitlab.WithBaseURL(server.URL))
+	assert.NoError(t, err)
+
+	cfg := &config{
+		private:       "https://gitlab.com/group/private-repo",
+		publicFork:    "https://gitlab.com/user/public-fork",
+		branch:        "feature",
+		targetBranch:  "main",
+		createPR:      true,
+		prTitle:       "New Feature",
+		prDescription: "Adds a feature",
+	}
+
+	url, reused, err := createChangeRequest(ctx, hosting.NewGitLab(client), cfg)
+	assert.NoError(t, err)
+	assert.False(t, reused)
+	assert.Equal(t, "https://gitlab.com/group/private-repo/-/merge_requests/5", url)
+	assert.Equal(t, "feature", mrPayload["source_branch"])
+	assert.Equal(t, "main", mrPayload["target_branch"])
+	assert.Equal(t, "New Feature", mrPayload["title"])
+	assert.Equal(t, "Adds a feature", mrPayload["description"])
+	assert.Equal(t, float64(99), mrPayload["target_project_id"])
+}
+
+func TestCreateChangeRequestReusesOpenPullRequest(t *testing.T) {
+	var created int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/user":
+			w.Header().Set("X-OAuth-Scopes", "repo, workflow, admin:repo")
+			w.Write([]byte(`{"login": "user"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/org/private-repo/pulls":
+			assert.Equal(t, "user:feature", r.URL.Query().Get("head"))
+			assert.Equal(t, "main", r.URL.Query().Get("base"))
+			w.Write([]byte(`[{"number": 8, "title": "New Feature", "html_url": "https://github.com/org/private-repo/pull/8"}]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/org/private-repo/pulls":
+			created++
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"number": 9}`))
+		default:
+			t.Errorf("unexpected %s request to %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	tok, err := token.NewToken("ghp_test", time.Time{}, "repo")
+	assert.NoError(t, err)
+	client, err := github.NewClient(ctx, tok, github.WithBaseURL(server.URL))
+	assert.NoError(t, err)
+
+	cfg := &config{
+		private:      "https://github.com/org/private-repo",
+		publicFork:   "https://github.com/user/public-fork",
+		branch:       "feature",
+		targetBranch: "main",
+		createPR:     true,
+		prTitle:      "New Feature",
+	}
+
+	url, reused, err := createChangeRequest(ctx, hosting.NewGitHub(client), cfg)
+	assert.NoError(t, err)
+	assert.True(t, reused)
+	assert.Equal(t, "https://github.com/org/private-repo/pull/8", url)
+	assert.Zero(t, created, "no pull request should be created when one is open")
+}
+
+func TestCreateChangeRequestAppliesReviewersAndLabels(t *testing.T) {
+	payloads := make(map[string]map[string][]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/user":
+			w.Header().Set("X-OAuth-Scopes", "repo, workflow, admin:repo")
+			w.Write([]byte(`{"login": "user"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/org/private-repo/pulls":
+			w.Write([]byte(`[]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/org/private-repo/pulls":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"number": 9, "html_url": "https://github.com/org/private-repo/pull/9"}`))
+		case r.Method == http.MethodPost:
+			var payload map[string][]string
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			payloads[r.URL.Path] = payload
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected %s request to %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	tok, err := token.NewToken("ghp_test", time.Time{}, "repo")
+	assert.NoError(t, err)
+	client, err := github.NewClient(ctx, tok, github.WithBaseURL(server.URL))
+	assert.NoError(t, err)
+
+	cfg := &config{
+		private:      "https://github.com/org/private-repo",
+		publicFork:   "https://github.com/user/public-fork",
+		branch:       "feature",
+		targetBranch: "main",
+		createPR:     true,
+		prTitle:      "New Feature",
+		reviewers:    []string{"alice", "org/maintainers"},
+		labels:       []string{"release"},
+	}
+
+	url, _, err := createChangeRequest(ctx, hosting.NewGitHub(client), cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://github.com/org/private-repo/pull/9", url)
+	assert.Equal(t, map[string]map[string][]string{
+		"/repos/org/private-repo/pulls/9/requested_reviewers": {"reviewers": {"alice"}, "team_reviewers": {"maintainers"}},
+		"/repos/org/private-repo/issues/9/labels":             {"labels": {"release"}},
+	}, payloads)
+}
+
+func TestCreateChangeRequestAnnotationFailureWarns(t *testing.T) {
+	var warned bytes.Buffer
+	defer func(orig io.Writer) { stderr = orig }(stderr)
+	stderr = &warned
+
+	var labelled []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/user":
+			w.Header().Set("X-OAuth-Scopes", "repo, workflow, admin:repo")
+			w.Write([]byte(`{"login": "user"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/org/private-repo/pulls":
+			// An open pull request from an earlier run is reused
+			w.Write([]byte(`[{"number": 8, "html_url": "https://github.com/org/private-repo/pull/8"}]`))
+		case r.URL.Path == "/repos/org/private-repo/pulls/8/requested_reviewers":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"message": "Review cannot be requested from pull request author."}`))
+		case r.URL.Path == "/repos/org/private-repo/issues/8/labels":
+			var payload map[string][]string
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			labelled = payload["labels"]
+			w.Write([]byte(`[]`))
+		default:
+			t.Errorf("unexpected %s request to %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	tok, err := token.NewToken("ghp_test", time.Time{}, "repo")
+	assert.NoError(t, err)
+	client, err := github.NewClient(ctx, tok, github.WithBaseURL(server.URL))
+	assert.NoError(t, err)
+
+	cfg := &config{
+		private:      "https://github.com/org/private-repo",
+		publicFork:   "https://github.com/user/public-fork",
+		branch:       "feature",
+		targetBranch: "main",
+		createPR:     true,
+		prTitle:      "New Feature",
+		reviewers:    []string{"user"},
+		labels:       []string{"release"},
+	}
+
+	url, reused, err := createChangeRequest(ctx, hosting.NewGitHub(client), cfg)
+	assert.NoError(t, err)
+	assert.True(t, reused)
+	assert.Equal(t, "https://github.com/org/private-repo/pull/8", url)
+	assert.Equal(t, []string{"release"}, labelled, "labels should still be added to the reused pull request")
+	assert.Contains(t, warned.String(), "Warning: failed to add reviewers or labels to https://github.com/org/private-repo/pull/8")
+	assert.Contains(t, warned.String(), "Review cannot be requested from pull request author.")
+}
+
+func TestWaitForMerge(t *testing.T) {
+	originalInterval := mergePollInterval
+	defer func() { mergePollInterval = originalInterval }()
+	mergePollInterval = 10 * time.Millisecond
+
+	// states lists the state reported by each successive poll; the last
+	// one repeats
+	newServer := func(states ...string) *httptest.Server {
+		var polls int
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/user":
+				w.Header().Set("X-OAuth-Scopes", "repo, workflow, admin:repo")
+				w.Write([]byte(`{"login": "user"}`))
+			case "/repos/org/private-repo/pulls/9":
+				state := states[min(polls, len(states)-1)]
+				polls++
+				merged := state == "merged"
+				if merged {
+					state = "closed"
+				}
+				fmt.Fprintf(w, `{"number": 9, "state": %q, "merged": %t}`, state, merged)
+			default:
+				t.Errorf("unexpected %s request to %s", r.Method, r.URL.Path)
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	}
+	newProvider := func(t *testing.T, server *httptest.Server) hosting.Provider {
+		tok, err := token.NewToken("ghp_test", time.Time{}, "repo")
+		assert.NoError(t, err)
+		client, err := github.NewClient(context.Background(), tok, github.WithBaseURL(server.URL))
+		assert.NoError(t, err)
+		return hosting.NewGitHub(client)
+	}
+	const prURL = "https://github.com/org/private-repo/pull/9"
+
+	t.Run("open then merged", func(t *testing.T) {
+		server := newServer("open", "open", "merged")
+		defer server.Close()
+
+		err := waitForMerge(context.Background(), newProvider(t, server), prURL, time.Minute)
+		assert.NoError(t, err)
+	})
+
+	t.Run("closed without merging", func(t *testing.T) {
+		server := newServer("open", "closed")
+		defer server.Close()
+
+		err := waitForMerge(context.Background(), newProvider(t, server), prURL, time.Minute)
+		assert.ErrorIs(t, err, errPRClosed)
+		assert.Equal(t, exitPRClosed, exitCode(gerrors.New("publish", err)))
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		server := newServer("open")
+		defer server.Close()
+
+		err := waitForMerge(context.Background(), newProvider(t, server), prURL, 50*time.Millisecond)
+		assert.ErrorIs(t, err, errWaitTimeout)
+		assert.Equal(t, exitWaitTimeout, exitCode(err))
+	})
+
+	assert.Equal(t, 1, exitCode(fmt.Errorf("push rejected")))
+}
+
+// fakeProvider records the hosting operations requested by publish
+type fakeProvider struct {
+	forked   []string
+	requests []hosting.ChangeRequestOptions
+	prURL    string
+	prError  error
+}
+
+func (f *fakeProvider) CreateRepository(ctx context.Context, opts hosting.RepoOptions) error {
+	return nil
+}
+
+func (f *fakeProvider) CreateFork(ctx context.Context, repoPath string) error {
+	f.forked = append(f.forked, repoPath)
+	return nil
+}
+
+func (f *fakeProvider) CreatePullOrMergeRequest(ctx context.Context, opts hosting.ChangeRequestOptions) (string, error) {
+	f.requests = append(f.requests, opts)
+	return f.prURL, f.prError
+}
+
+func (f *fakeProvider) GetDefaultBranch(ctx context.Context, repoPath string) (string, error) {
+	return "main", nil
+}
+
+func TestPublishWithProvider(t *testing.T) {
+	originalClone := cloneRepository
+	defer func() { cloneRepository = originalClone }()
+
+	var cloned []git.CloneOptions
+	cloneRepository = func(opts git.CloneOptions) error {
+		cloned = append(cloned, opts)
+		return nil
+	}
+
+	provider := &fakeProvider{}
+	cfg := &config{
+		private:       "https://github.com/org/private-repo",
+		publicFork:    "https://github.com/user/public-fork",
+		branch:        "feature",
+		token:         "test-token",
+		createFork:    true,
+		createPR:      true,
+		prTitle:       "New Feature",
+		prDescription: "Adds a feature",
+		targetBranch:  "main",
+	}
+
+	err := publish(context.Background(), provider, cfg, &progress.DefaultTracker{}, nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"org/private-repo"}, provider.forked)
+	if assert.Len(t, cloned, 1) {
+		assert.Equal(t, cfg.private, cloned[0].SourceURL)
+		assert.Equal(t, cfg.publicFork, cloned[0].TargetURL)
+	}
+	assert.Equal(t, []hosting.ChangeRequestOptions{{
+		SourceRepo:   "user/public-fork",
+		SourceBranch: "feature",
+		TargetRepo:   "org/private-repo",
+		TargetBranch: "main",
+		Title:        "New Feature",
+		Body:         "Adds a feature",
+	}}, provider.requests)
+
+	// Provider errors surface as publish errors
+	provider.prError = fmt.Errorf("validation failed")
+	err = publish(context.Background(), provider, cfg, &progress.DefaultTracker{}, nil)
+	assert.ErrorContains(t, err, "failed to create pull request")
+}
+
+func TestPublishEvents(t *testing.T) {
+	originalClone := cloneRepository
+	defer func() { cloneRepository = originalClone }()
+	cloneRepository = func(opts git.CloneOptions) error { return nil }
+
+	provider := &fakeProvider{prURL: "https://github.com/org/private-repo/pull/12"}
+	cfg := &config{
+		private:      "https://github.com/org/private-repo",
+		publicFork:   "https://github.com/user/public-fork",
+		branch:       "feature",
+		token:        "test-token",
+		createFork:   true,
+		createPR:     true,
+		prTitle:      "New Feature",
+		targetBranch: "main",
+	}
+
+	var events []pubevents.Event
+	err := publish(context.Background(), provider, cfg, &progress.DefaultTracker{}, func(e pubevents.Event) {
+		events = append(events, e)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []pubevents.Event{
+		{Type: pubevents.ForkCreated, Source: "org/private-repo"},
+		{Type: pubevents.Pushed, Source: cfg.private, Target: cfg.publicFork},
+		{
+			Type:   pubevents.PRCreated,
+			Source: cfg.publicFork,
+			Target: cfg.private,
+			Title:  "New Feature",
+			URL:    "https://github.com/org/private-repo/pull/12",
+		},
+	}, events)
+
+	// A failed push stops the pipeline before any further events
+	cloneRepository = func(opts git.CloneOptions) error { return fmt.Errorf("push rejected") }
+	events = nil
+	err = publish(context.Background(), provider, cfg, &progress.DefaultTracker{}, func(e pubevents.Event) {
+		events = append(events, e)
+	})
+	assert.Error(t, err)
+	assert.Equal(t, []pubevents.Event{{Type: pubevents.ForkCreated, Source: "org/private-repo"}}, events)
+}
+
+func TestPublishDraftPullRequest(t *testing.T) {
+	originalClone := cloneRepository
+	defer func() { cloneRepository = originalClone }()
+	cloneRepository = func(opts git.CloneOptions) error { return nil }
+
+	provider := &fakeProvider{}
+	cfg := &config{
+		private:      "https://github.com/org/private-repo",
+		publicFork:   "https://github.com/user/public-fork",
+		branch:       "feature",
+		token:        "test-token",
+		createPR:     true,
+		prTitle:      "New Feature",
+		targetBranch: "main",
+		draft:        true,
+	}
+
+	err := publish(context.Background(), provider, cfg, &progress.DefaultTracker{}, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, provider.requests, 1) {
+		assert.True(t, provider.requests[0].Draft)
+	}
+}
+
+func TestPublishNothingToPublish(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	originalClone, originalUpToDate := cloneRepository, branchUpToDate
+	defer func() { cloneRepository, branchUpToDate = originalClone, originalUpToDate }()
+	branchUpToDate = git.BranchUpToDate
+
+	var pushed int
+	cloneRepository = func(opts git.CloneOptions) error {
+		pushed++
+		return nil
+	}
+
+	// Identical source and fork repositories
+	base := t.TempDir()
+	work := filepath.Join(base, "work")
+	source := filepath.Join(base, "source.git")
+	fork := filepath.Join(base, "fork.git")
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main", work},
+		{"-C", work, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "initial"},
+		{"clone", "-q", "--bare", work, source},
+		{"clone", "-q", "--bare", work, fork},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+
+	provider := &fakeProvider{}
+	cfg := &config{
+		private:      "file://" + source,
+		publicFork:   "file://" + fork,
+		branch:       "main",
+		targetBranch: "main",
+	}
+
+	var events []pubevents.Event
+	err := publish(context.Background(), provider, cfg, &progress.DefaultTracker{}, func(e pubevents.Event) {
+		events = append(events, e)
+	})
+	assert.NoError(t, err)
+	assert.Zero(t, pushed, "nothing should be pushed")
+	assert.Empty(t, provider.requests, "no pull request should be opened")
+	assert.Equal(t, []pubevents.Event{{Type: pubevents.UpToDate, Source: cfg.private, Target: cfg.publicFork}}, events)
+}
+
+func TestPublishUpToDateStillOpensPullRequest(t *testing.T) {
+	originalClone, originalUpToDate := cloneRepository, branchUpToDate
+	defer func() { cloneRepository, branchUpToDate = originalClone, originalUpToDate }()
+
+	// A previous run pushed the branch but failed to open the pull request
+	branchUpToDate = func(opts git.CloneOptions, branch string) (bool, error) {
+		return true, nil
+	}
+	var pushed int
+	cloneRepository = func(opts git.CloneOptions) error {
+		pushed++
+		return nil
+	}
+
+	provider := &fakeProvider{prURL: "https://github.com/org/private-repo/pull/7"}
+	cfg := &config{
+		private:      "https://github.com/org/private-repo",
+		publicFork:   "https://github.com/user/public-fork",
+		branch:       "feature",
+		createPR:     true,
+		prTitle:      "New Feature",
+		targetBranch: "main",
+	}
+
+	var events []pubevents.Event
+	err := publish(context.Background(), provider, cfg, &progress.DefaultTracker{}, func(e pubevents.Event) {
+		events = append(events, e)
+	})
+	assert.NoError(t, err)
+	assert.Zero(t, pushed, "nothing should be pushed")
+	assert.Len(t, provider.requests, 1, "the pull request should be opened")
+	assert.Equal(t, []pubevents.Event{
+		{Type: pubevents.UpToDate, Source: cfg.private, Target: cfg.publicFork},
+		{Type: pubevents.PRCreated, Source: cfg.publicFork, Target: cfg.private, Title: "New Feature", URL: provider.prURL},
+	}, events)
+}
+
+// deletingProvider is a fakeProvider that can also check for and delete
+// repositories. Forks land in the "user" namespace under the name of the
+// forked repository.
+type deletingProvider struct {
+	fakeProvider
+	existing map[string]bool
+	deleted  []string
+}
+
+func (d *deletingProvider) ForkPath(ctx context.Context, repoPath string) (string, error) {
+	return "user/" + path.Base(repoPath), nil
+}
+
+func (d *deletingProvider) RepositoryExists(ctx context.Context, repoPath string) (bool, error) {
+	return d.existing[repoPath], nil
+}
+
+func (d *deletingProvider) DeleteRepository(ctx context.Context, repoPath string) error {
+	d.deleted = append(d.deleted, repoPath)
+	return nil
+}
+
+func TestPublishRollback(t *testing.T) {
+	originalClone := cloneRepository
+	defer func() { cloneRepository = originalClone }()
+	cloneRepository = func(opts git.CloneOptions) error { return nil }
+
+	newConfig := func(rollback bool) *config {
+		return &config{
+			private:      "https://github.com/org/private-repo",
+			publicFork:   "https://github.com/user/public-fork",
+			branch:       "feature",
+			token:        "test-token",
+			createFork:   true,
+			createPR:     true,
+			prTitle:      "New Feature",
+			targetBranch: "main",
+			rollback:     rollback,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		rollback    bool
+		forkExisted bool
+		wantDeleted []string
+	}{
+		{name: "fresh fork is deleted", rollback: true, wantDeleted: []string{"user/private-repo"}},
+		{name: "existing fork is kept", rollback: true, forkExisted: true},
+		{name: "rollback disabled", rollback: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &deletingProvider{
+				fakeProvider: fakeProvider{prError: fmt.Errorf("validation failed")},
+				existing:     map[string]bool{"user/private-repo": tt.forkExisted},
+			}
+
+			var events []pubevents.Event
+			err := publish(context.Background(), provider, newConfig(tt.rollback), &progress.DefaultTracker{}, func(e pubevents.Event) {
+				events = append(events, e)
+			})
+			assert.ErrorContains(t, err, "failed to create pull request")
+			assert.Equal(t, []string{"org/private-repo"}, provider.forked)
+			assert.Equal(t, tt.wantDeleted, provider.deleted)
+
+			last := events[len(events)-1]
+			if tt.wantDeleted != nil {
+				assert.Equal(t, pubevents.Event{Type: pubevents.ForkDeleted, Source: "user/private-repo"}, last)
+			} else {
+				assert.NotEqual(t, pubevents.ForkDeleted, last.Type)
+			}
+		})
+	}
+
+	// Push failures delete the fork this run created, not the repository
+	// the public fork URL points at
+	cloneRepository = func(opts git.CloneOptions) error { return fmt.Errorf("push rejected") }
+	provider := &deletingProvider{existing: map[string]bool{"user/public-fork": true}}
+	err := publish(context.Background(), provider, newConfig(true), &progress.DefaultTracker{}, nil)
+	assert.ErrorContains(t, err, "failed to push to public fork")
+	assert.Equal(t, []string{"user/private-repo"}, provider.deleted)
+
+	// Without -create-fork nothing was created, so nothing is deleted
+	cfg := newConfig(true)
+	cfg.createFork = false
+	provider = &deletingProvider{}
+	err = publish(context.Background(), provider, cfg, &progress.DefaultTracker{}, nil)
+	assert.ErrorContains(t, err, "failed to push to public fork")
+	assert.Empty(t, provider.deleted)
+
+	// Providers that cannot delete repositories refuse to start
+	err = publish(context.Background(), &fakeProvider{}, newConfig(true), &progress.DefaultTracker{}, nil)
+	assert.ErrorContains(t, err, "rollback is not supported")
+}
+
+// sizedProvider is a fakeProvider that also reports repository sizes
+type sizedProvider struct {
+	fakeProvider
+	size int64
+}
+
+func (s *sizedProvider) RepositorySize(ctx context.Context, repoPath string) (int64, error) {
+	return s.size, nil
+}
+
+func TestPublishChecksDiskSpace(t *testing.T) {
+	originalClone := cloneRepository
+	defer func() { cloneRepository = originalClone }()
+
+	var cloned []git.CloneOptions
+	cloneRepository = func(opts git.CloneOptions) error {
+		cloned = append(cloned, opts)
+		return nil
+	}
+
+	cfg := &config{
+		private:    "https://github.com/org/private-repo",
+		publicFork: "https://github.com/user/public-fork",
+		token:      "test-token",
+	}
+
+	err := publish(context.Background(), &sizedProvider{size: 4096}, cfg, &progress.DefaultTracker{}, nil)
+	assert.NoError(t, err)
+	err = publish(context.Background(), &fakeProvider{}, cfg, &progress.DefaultTracker{}, nil)
+	assert.NoError(t, err)
+
+	if assert.Len(t, cloned, 2) {
+		assert.True(t, cloned[0].CheckDiskSpace)
+		assert.Equal(t, int64(4096), cloned[0].EstimatedSize)
+		assert.False(t, cloned[1].CheckDiskSpace, "providers without sizes skip the check")
+	}
+}
+
+func TestNewProviderProbesSelfHostedGitHub(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/meta", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"installed_version": "3.12.0"}`))
+	})
+	mux.HandleFunc("/api/v3/user", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-OAuth-Scopes", "repo, workflow, admin:repo")
+		w.Write([]byte(`{"login": "user"}`))
+	})
+	server := httptest.NewTLSServer(mux)
+	defer server.Close()
+
+	// The probe and the token check use the default transport, which must
+	// trust the test server
+	originalTransport := http.DefaultTransport
+	http.DefaultTransport = server.Client().Transport
+	defer func() { http.DefaultTransport = originalTransport }()
+
+	cfg := &config{
+		private:    server.URL + "/org/private-repo",
+		publicFork: server.URL + "/user/public-fork",
+		token:      "ghp_test123456789",
+	}
+	provider, err := newProvider(context.Background(), cfg)
+	if assert.NoError(t, err) {
+		assert.IsType(t, &hosting.GitHub{}, provider)
+	}
+
+	repoPath, err := parseRepoPath(cfg.publicFork)
+	assert.NoError(t, err)
+	assert.Equal(t, "user/public-fork", repoPath)
+
+	// Hosts that don't answer like GitHub Enterprise are still rejected
+	other := httptest.NewTLSServer(http.NotFoundHandler())
+	defer other.Close()
+	http.DefaultTransport = other.Client().Transport
+
+	cfg.publicFork = other.URL + "/user/public-fork"
+	_, err = newProvider(context.Background(), cfg)
+	assert.ErrorContains(t, err, "failed to detect GitHub API")
+}
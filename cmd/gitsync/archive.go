@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/NicabarNimble/go-gittools/internal/github"
+	"github.com/NicabarNimble/go-gittools/internal/progress"
+)
+
+// progressEntry is the status record archived for each workflow run
+type progressEntry struct {
+	RunID     string                  `json:"run_id"`
+	Status    progress.WorkflowStatus `json:"status"`
+	Branches  []string                `json:"branches"`
+	StartTime string                  `json:"start_time"`
+	EndTime   string                  `json:"end_time,omitempty"`
+	Error     string                  `json:"error,omitempty"`
+}
+
+// newProgressEntry converts a workflow run into its archived status record
+func newProgressEntry(run *github.WorkflowRun) progressEntry {
+	entry := progressEntry{
+		RunID:     strconv.FormatInt(run.ID, 10),
+		Status:    runStatus(run),
+		Branches:  []string{},
+		StartTime: run.CreatedAt.Format(time.RFC3339),
+	}
+	if run.HeadBranch != "" {
+		entry.Branches = append(entry.Branches, run.HeadBranch)
+	}
+	if run.Status == "completed" {
+		entry.EndTime = run.UpdatedAt.Format(time.RFC3339)
+	}
+	if entry.Status == progress.WorkflowFailed {
+		entry.Error = fmt.Sprintf("workflow concluded with %s", run.Conclusion)
+	}
+	return entry
+}
+
+// archiveStatus writes the run's status record to <dir>/<run-id>.json,
+// creating dir if needed
+func archiveStatus(dir string, run *github.WorkflowRun) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(newProgressEntry(run), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run status: %w", err)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%d.json", run.ID))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write run status: %w", err)
+	}
+	return nil
+}
+
+// createRunLog creates <dir>/<run-id>.log for archiving a run's logs,
+// creating dir if needed
+func createRunLog(dir string, runID int64) (*os.File, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	file, err := os.Create(filepath.Join(dir, fmt.Sprintf("%d.log", runID)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create run log: %w", err)
+	}
+	return file, nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NicabarNimble/go-gittools/internal/github"
+	"github.com/NicabarNimble/go-gittools/internal/progress"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveRun(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "runs", "nested")
+	created := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	run := &github.WorkflowRun{
+		ID:         123456,
+		Status:     "completed",
+		Conclusion: "failure",
+		HeadBranch: "main",
+		CreatedAt:  created,
+		UpdatedAt:  created.Add(15 * time.Second),
+	}
+
+	require.NoError(t, archiveStatus(dir, run))
+
+	logFile, err := createRunLog(dir, run.ID)
+	require.NoError(t, err)
+	_, err = logFile.WriteString("[2025-02-01T12:00:00Z] Starting sync operation\n")
+	require.NoError(t, err)
+	require.NoError(t, logFile.Close())
+
+	data, err := os.ReadFile(filepath.Join(dir, "123456.json"))
+	require.NoError(t, err)
+	var entry progressEntry
+	require.NoError(t, json.Unmarshal(data, &entry))
+	assert.Equal(t, progressEntry{
+		RunID:     "123456",
+		Status:    progress.WorkflowFailed,
+		Branches:  []string{"main"},
+		StartTime: "2025-02-01T12:00:00Z",
+		EndTime:   "2025-02-01T12:00:15Z",
+		Error:     "workflow concluded with failure",
+	}, entry)
+
+	logs, err := os.ReadFile(filepath.Join(dir, "123456.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "[2025-02-01T12:00:00Z] Starting sync operation\n", string(logs))
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/NicabarNimble/go-gittools/internal/github"
+	"github.com/NicabarNimble/go-gittools/internal/token"
+)
+
+// validatorCache lets clients created by the same invocation share one
+// token validation
+var validatorCache = github.NewValidatorCache(github.DefaultValidatorCacheTTL)
+
+// retryBudget bounds the retries made by all clients of one invocation
+var retryBudget = github.NewRetryBudget(10, time.Minute)
+
+// newGitHubClient loads the GitHub token for an account from the environment
+// and creates a validated client. An empty account selects GIT_TOKEN_GITHUB.
+// With --debug, API traffic is logged to stderr, and with --no-validate
+// the token is not validated.
+func newGitHubClient(ctx context.Context, account string) (*github.Client, error) {
+	storage := token.NewEnvStorage()
+	envKey := storage.FormatEnvKey(token.Key(token.ProviderGitHub, account))
+
+	opts := []github.ClientOption{
+		github.WithValidatorCache(validatorCache),
+		github.WithSkipValidation(noValidate),
+		github.WithRetryBudget(retryBudget),
+	}
+	if debug {
+		opts = append(opts, github.WithDebug(os.Stderr))
+	}
+
+	client, err := github.NewClientFromStorage(ctx, storage, account, opts...)
+	if err != nil {
+		switch {
+		case errors.Is(err, token.ErrTokenNotFound):
+			if account == "" {
+				return nil, fmt.Errorf("GitHub token not found in environment. Set %s, GITHUB_TOKEN or GH_TOKEN", envKey)
+			}
+			return nil, fmt.Errorf("GitHub token not found in environment. Set %s environment variable", envKey)
+		case errors.Is(err, token.ErrTokenExpired):
+			return nil, fmt.Errorf("GitHub token has expired. Please refresh or provide a new token")
+		case errors.Is(err, token.ErrTokenInvalid):
+			return nil, fmt.Errorf("GitHub token is invalid. Check token format in %s environment variable", envKey)
+		case errors.Is(err, token.ErrScopeMissing):
+			return nil, fmt.Errorf("GitHub token is missing required scopes (repo, workflow, admin:repo). Please check token permissions")
+		}
+		return nil, fmt.Errorf("failed to create GitHub client: %w", err)
+	}
+
+	return client, nil
+}
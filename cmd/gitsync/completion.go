@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/NicabarNimble/go-gittools/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// progressDir holds the archived progress entries used to complete run IDs
+var progressDir = filepath.Join(".gitsync", "progress")
+
+func newCompletionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "completion [bash|zsh|fish|powershell]",
+		Short: "Generate shell completion scripts",
+		Long: `Generate a completion script for the given shell.
+Load it in the current session or install it with your shell's completion mechanism.`,
+		Example: `  source <(gitsync completion bash)
+  gitsync completion zsh > "${fpath[1]}/_gitsync"
+  gitsync completion fish > ~/.config/fish/completions/gitsync.fish
+  gitsync completion powershell | Out-String | Invoke-Expression`,
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+		Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			out := cmd.OutOrStdout()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(out, true)
+			case "zsh":
+				return root.GenZshCompletion(out)
+			case "fish":
+				return root.GenFishCompletion(out, true)
+			case "powershell":
+				return root.GenPowerShellCompletionWithDesc(out)
+			}
+			return fmt.Errorf("unsupported shell: %s", args[0])
+		},
+	}
+
+	return cmd
+}
+
+// completeRunIDs offers run IDs from the local progress archive
+func completeRunIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return localRunIDs(progressDir, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// localRunIDs lists the run IDs of progress entries in dir that start
+// with prefix
+func localRunIDs(dir, prefix string) []string {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil
+	}
+
+	var ids []string
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		var entry progressEntry
+		if err := json.Unmarshal(data, &entry); err != nil || entry.RunID == "" {
+			continue
+		}
+		if strings.HasPrefix(entry.RunID, prefix) {
+			ids = append(ids, entry.RunID)
+		}
+	}
+	return ids
+}
+
+// completeRepos offers the source and target repositories from the sync config
+func completeRepos(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	path, err := config.Discover()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var repos []string
+	for _, repo := range []string{cfg.SourceRepo, cfg.TargetRepo} {
+		if repo != "" && strings.HasPrefix(repo, toComplete) {
+			repos = append(repos, repo)
+		}
+	}
+	return repos, cobra.ShellCompDirectiveNoFileComp
+}
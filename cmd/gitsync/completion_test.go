@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NicabarNimble/go-gittools/internal/progress"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompletionCommand(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		t.Run(shell, func(t *testing.T) {
+			cmd := newRootCmd()
+			out := new(bytes.Buffer)
+			cmd.SetOut(out)
+			cmd.SetArgs([]string{"completion", shell})
+
+			require.NoError(t, cmd.Execute())
+			assert.NotEmpty(t, out.String())
+			assert.Contains(t, out.String(), "gitsync")
+		})
+	}
+
+	cmd := newRootCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"completion", "tcsh"})
+	assert.Error(t, cmd.Execute())
+}
+
+func TestLocalRunIDs(t *testing.T) {
+	dir := t.TempDir()
+	for _, id := range []string{"1001", "1002", "2001"} {
+		data, err := json.Marshal(progressEntry{RunID: id, Status: progress.WorkflowCompleted})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, id+".json"), data, 0644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0644))
+
+	assert.ElementsMatch(t, []string{"1001", "1002", "2001"}, localRunIDs(dir, ""))
+	assert.ElementsMatch(t, []string{"1001", "1002"}, localRunIDs(dir, "10"))
+	assert.Empty(t, localRunIDs(filepath.Join(dir, "missing"), ""))
+}
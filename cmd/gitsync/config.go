@@ -0,0 +1,29 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/NicabarNimble/go-gittools/internal/config"
+)
+
+// configFlagUsage describes the --config flag shared by commands that read
+// the sync config
+const configFlagUsage = "Configuration file path (default: nearest .gitsync.json, then $XDG_CONFIG_HOME/gitsync/config.json)"
+
+// resolveConfigPath returns path if set, otherwise the discovered config
+// file. When nothing is found it falls back to .gitsync.json in the
+// current directory, where configure will create it.
+func resolveConfigPath(path string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+
+	found, err := config.Discover()
+	if errors.Is(err, config.ErrConfigNotFound) {
+		return config.DefaultConfigFile, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return found, nil
+}
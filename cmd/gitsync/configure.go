@@ -3,6 +3,7 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -15,11 +16,13 @@ type configureOptions struct {
 	targetRepo     string
 	schedule       string
 	branchMappings []string
+	removeBranches []string
 	errorNotify    bool
 	notifyEmail    string
 	retryAttempts  int
 	retryDelay     string
 	configFile     string
+	dryRun         bool
 }
 
 func newConfigureCmd() *cobra.Command {
@@ -32,11 +35,13 @@ func newConfigureCmd() *cobra.Command {
 Settings include source/target repositories, branch mappings, schedule, and error handling.`,
 		Example: `  gitsync configure --source owner/repo --target fork/repo
   gitsync configure --branch main:master,dev:development
+  gitsync configure --remove-branch dev
   gitsync configure --schedule "0 0 * * *"
   gitsync configure --error-notify --notify-email user@example.com
-  gitsync configure --retry-attempts 5 --retry-delay 10m`,
+  gitsync configure --retry-attempts 5 --retry-delay 10m
+  gitsync configure --branch dev:development --dry-run`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return updateConfig(opts)
+			return updateConfig(cmd.OutOrStdout(), opts)
 		},
 	}
 
@@ -44,20 +49,27 @@ Settings include source/target repositories, branch mappings, schedule, and erro
 	cmd.Flags().StringVar(&opts.targetRepo, "target", "", "Target repository (owner/repo)")
 	cmd.Flags().StringVar(&opts.schedule, "schedule", "", "Cron schedule for automated syncs")
 	cmd.Flags().StringSliceVar(&opts.branchMappings, "branch", nil, "Branch mappings (source:target)")
+	cmd.Flags().StringArrayVar(&opts.removeBranches, "remove-branch", nil, "Source branch whose mapping should be removed (repeatable)")
 	cmd.Flags().BoolVar(&opts.errorNotify, "error-notify", false, "Enable error notifications")
 	cmd.Flags().StringVar(&opts.notifyEmail, "notify-email", "", "Email address for error notifications")
 	cmd.Flags().IntVar(&opts.retryAttempts, "retry-attempts", 0, "Number of retry attempts (0-10)")
 	cmd.Flags().StringVar(&opts.retryDelay, "retry-delay", "", "Delay between retries (e.g. 5m, 1h)")
-	cmd.Flags().StringVar(&opts.configFile, "config", ".gitsync.json", "Configuration file path")
+	cmd.Flags().StringVar(&opts.configFile, "config", "", configFlagUsage)
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Print the resulting configuration as JSON without saving it")
 
 	return cmd
 }
 
-func updateConfig(opts *configureOptions) error {
+func updateConfig(out io.Writer, opts *configureOptions) error {
+	configFile, err := resolveConfigPath(opts.configFile)
+	if err != nil {
+		return err
+	}
+
 	// Load existing config if it exists
 	cfg := &config.SyncConfig{}
-	if _, err := os.Stat(opts.configFile); err == nil {
-		data, err := os.ReadFile(opts.configFile)
+	if _, err := os.Stat(configFile); err == nil {
+		data, err := os.ReadFile(configFile)
 		if err != nil {
 			return fmt.Errorf("failed to read config file: %w", err)
 		}
@@ -101,6 +113,13 @@ func updateConfig(opts *configureOptions) error {
 		}
 	}
 
+	for _, source := range opts.removeBranches {
+		if _, ok := cfg.BranchMappings[source]; !ok {
+			return fmt.Errorf("no branch mapping for %q", source)
+		}
+		delete(cfg.BranchMappings, source)
+	}
+
 	// Update error handling configuration
 	if opts.errorNotify {
 		cfg.ErrorHandling.Notify = true
@@ -118,36 +137,46 @@ func updateConfig(opts *configureOptions) error {
 		cfg.ErrorHandling.RetryDelay = opts.retryDelay
 	}
 
+	// Preview the merged configuration without touching the file
+	if opts.dryRun {
+		data, err := json.MarshalIndent(cfg, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal config: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+
 	// Create config directory if it doesn't exist
-	configDir := filepath.Dir(opts.configFile)
+	configDir := filepath.Dir(configFile)
 	if err := os.MkdirAll(configDir, 0755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
 	// Save updated config
-	if err := config.SaveConfig(cfg, opts.configFile); err != nil {
+	if err := config.SaveConfig(cfg, configFile); err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
 
-	fmt.Printf("Configuration updated successfully:\n")
-	fmt.Printf("Source repository: %s\n", cfg.SourceRepo)
-	fmt.Printf("Target repository: %s\n", cfg.TargetRepo)
+	fmt.Fprintf(out, "Configuration updated successfully:\n")
+	fmt.Fprintf(out, "Source repository: %s\n", cfg.SourceRepo)
+	fmt.Fprintf(out, "Target repository: %s\n", cfg.TargetRepo)
 	if cfg.Schedule != "" {
-		fmt.Printf("Schedule: %s\n", cfg.Schedule)
+		fmt.Fprintf(out, "Schedule: %s\n", cfg.Schedule)
 	}
 	if len(cfg.BranchMappings) > 0 {
-		fmt.Printf("Branch mappings:\n")
+		fmt.Fprintf(out, "Branch mappings:\n")
 		for source, target := range cfg.BranchMappings {
-			fmt.Printf("  %s -> %s\n", source, target)
+			fmt.Fprintf(out, "  %s -> %s\n", source, target)
 		}
 	}
-	fmt.Printf("Error handling:\n")
-	fmt.Printf("  Notifications: %v\n", cfg.ErrorHandling.Notify)
+	fmt.Fprintf(out, "Error handling:\n")
+	fmt.Fprintf(out, "  Notifications: %v\n", cfg.ErrorHandling.Notify)
 	if cfg.ErrorHandling.NotifyEmail != "" {
-		fmt.Printf("  Notify email: %s\n", cfg.ErrorHandling.NotifyEmail)
+		fmt.Fprintf(out, "  Notify email: %s\n", cfg.ErrorHandling.NotifyEmail)
 	}
-	fmt.Printf("  Retry attempts: %d\n", cfg.ErrorHandling.RetryAttempts)
-	fmt.Printf("  Retry delay: %s\n", cfg.ErrorHandling.RetryDelay)
+	fmt.Fprintf(out, "  Retry attempts: %d\n", cfg.ErrorHandling.RetryAttempts)
+	fmt.Fprintf(out, "  Retry delay: %s\n", cfg.ErrorHandling.RetryDelay)
 
 	return nil
 }
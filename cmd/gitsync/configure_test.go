@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -159,3 +160,55 @@ func TestConfigureCommandExecution(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigureDryRun(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	initial := &config.SyncConfig{
+		SourceRepo:     "owner/source",
+		TargetRepo:     "owner/target",
+		BranchMappings: map[string]string{"main": "main"},
+	}
+	data, err := json.MarshalIndent(initial, "", "  ")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(configFile, data, 0644))
+
+	cmd := newConfigureCmd()
+	out := new(bytes.Buffer)
+	cmd.SetOut(out)
+	cmd.SetArgs([]string{"--branch", "dev:development", "--dry-run", "--config", configFile})
+	require.NoError(t, cmd.Execute())
+
+	// The file on disk is untouched
+	after, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, data, after)
+
+	// The printed config reflects the merged branch mappings
+	var preview config.SyncConfig
+	require.NoError(t, json.Unmarshal(out.Bytes(), &preview))
+	assert.Equal(t, "owner/source", preview.SourceRepo)
+	assert.Equal(t, map[string]string{"main": "main", "dev": "development"}, preview.BranchMappings)
+}
+
+func TestConfigureRemoveBranch(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.json")
+
+	run := func(args ...string) error {
+		cmd := newConfigureCmd()
+		cmd.SetOut(new(bytes.Buffer))
+		cmd.SetArgs(append(args, "--config", configFile))
+		return cmd.Execute()
+	}
+
+	require.NoError(t, run("--branch", "main:master", "--branch", "dev:development"))
+	require.NoError(t, run("--remove-branch", "dev"))
+
+	cfg, err := config.LoadConfig(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"main": "master"}, cfg.BranchMappings)
+
+	// Removing a mapping that does not exist is an error and leaves the file alone
+	err = run("--remove-branch", "dev")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `no branch mapping for "dev"`)
+}
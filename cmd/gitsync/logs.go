@@ -1,26 +1,43 @@
 package main
 
 import (
+	"bufio"
 	"context"
-	"errors"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"strconv"
-	"strings"
+	"syscall"
+	"time"
 
 	"github.com/NicabarNimble/go-gittools/internal/github"
 	"github.com/NicabarNimble/go-gittools/internal/progress"
-	"github.com/NicabarNimble/go-gittools/internal/token"
 	"github.com/spf13/cobra"
 )
 
+const (
+	// followPollInterval is the initial delay between polls while following logs
+	followPollInterval = 2 * time.Second
+
+	// followMaxPollInterval caps the backoff applied while no new logs arrive
+	followMaxPollInterval = 30 * time.Second
+)
+
+// logsClient is the subset of the GitHub client used to follow logs
+type logsClient interface {
+	GetWorkflowRun(ctx context.Context, owner, repo string, runID int64) (*github.WorkflowRun, error)
+	GetWorkflowLogs(ctx context.Context, owner, repo string, runID int64) ([]byte, error)
+}
+
 type logsOptions struct {
-	repo     string
-	runID    string
-	output   string
-	follow   bool
-	tailNum  int
+	repo      string
+	runID     string
+	output    string
+	follow    bool
+	tailNum   int
+	account   string
+	outputDir string
 }
 
 func newLogsCmd() *cobra.Command {
@@ -34,19 +51,24 @@ Logs can be displayed in the terminal or saved to a file.`,
 		Example: `  gitsync logs --repo owner/repo --run-id 123456
   gitsync logs --repo owner/repo --run-id 123456 --output workflow.log
   gitsync logs --repo owner/repo --run-id 123456 --follow
-  gitsync logs --repo owner/repo --run-id 123456 --tail 100`,
+  gitsync logs --repo owner/repo --run-id 123456 --tail 100
+  gitsync logs --repo owner/repo --run-id 123456 --output-dir ./runs`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return fetchLogs(opts)
 		},
 	}
 
 	cmd.Flags().StringVar(&opts.repo, "repo", "", "Repository to fetch logs from (owner/repo)")
+	cmd.Flags().StringVar(&opts.account, "account", "", "Named GitHub account token to use (reads GIT_TOKEN_GITHUB_<ACCOUNT>)")
 	cmd.Flags().StringVar(&opts.runID, "run-id", "", "Workflow run ID")
 	cmd.Flags().StringVar(&opts.output, "output", "", "Output file (default: stdout)")
+	cmd.Flags().StringVar(&opts.outputDir, "output-dir", "", "Directory to archive <run-id>.log and <run-id>.json into")
 	cmd.Flags().BoolVar(&opts.follow, "follow", false, "Follow log output")
 	cmd.Flags().IntVar(&opts.tailNum, "tail", 0, "Number of lines to show from the end (0 for all)")
 	cmd.MarkFlagRequired("repo")
 	cmd.MarkFlagRequired("run-id")
+	cmd.RegisterFlagCompletionFunc("repo", completeRepos)
+	cmd.RegisterFlagCompletionFunc("run-id", completeRunIDs)
 
 	return cmd
 }
@@ -58,8 +80,9 @@ func fetchLogs(opts *logsOptions) error {
 		return fmt.Errorf("invalid run ID: %w", err)
 	}
 
-	// Create context
-	ctx := context.Background()
+	// Create a context that is cancelled on Ctrl-C so following can stop cleanly
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	// Initialize progress tracker if following logs
 	var tracker *progress.WorkflowTracker
@@ -67,31 +90,6 @@ func fetchLogs(opts *logsOptions) error {
 		tracker = progress.NewWorkflowTracker()
 	}
 
-	// Get and validate GitHub token
-	storage := token.NewEnvStorage()
-	t, err := storage.Retrieve(ctx, "GITHUB")
-	if err != nil {
-		if errors.Is(err, token.ErrTokenNotFound) {
-			return fmt.Errorf("GitHub token not found in environment. Set GIT_TOKEN_GITHUB environment variable")
-		}
-		if errors.Is(err, token.ErrTokenExpired) {
-			return fmt.Errorf("GitHub token has expired. Please refresh or provide a new token")
-		}
-		if errors.Is(err, token.ErrTokenInvalid) {
-			return fmt.Errorf("GitHub token is invalid. Check token format in GIT_TOKEN_GITHUB environment variable")
-		}
-		return fmt.Errorf("failed to get GitHub token: %w", err)
-	}
-
-	// Pre-validate token before creating client
-	validator := github.NewTokenValidator()
-	if err := validator.Validate(ctx, &t); err != nil {
-		if strings.Contains(err.Error(), "missing required scopes") {
-			return fmt.Errorf("GitHub token is missing required scopes (repo, workflow, admin:repo). Please check token permissions")
-		}
-		return fmt.Errorf("GitHub token validation failed: %w", err)
-	}
-
 	// Parse owner and repo
 	owner, repo, err := github.ParseRepo(opts.repo)
 	if err != nil {
@@ -99,9 +97,9 @@ func fetchLogs(opts *logsOptions) error {
 	}
 
 	// Create GitHub client
-	client, err := github.NewClient(ctx, &t)
+	client, err := newGitHubClient(ctx, opts.account)
 	if err != nil {
-		return fmt.Errorf("failed to create GitHub client: %w", err)
+		return err
 	}
 
 	// Get workflow run to check status
@@ -121,6 +119,19 @@ func fetchLogs(opts *logsOptions) error {
 		out = file
 	}
 
+	// Archive the run's status and a copy of everything written
+	if opts.outputDir != "" {
+		if err := archiveStatus(opts.outputDir, run); err != nil {
+			return err
+		}
+		logFile, err := createRunLog(opts.outputDir, run.ID)
+		if err != nil {
+			return err
+		}
+		defer logFile.Close()
+		out = io.MultiWriter(out, logFile)
+	}
+
 	// Start tracking if following
 	if opts.follow {
 		workflow := tracker.StartWorkflow("Repository Sync", run.ID, run.ID)
@@ -137,6 +148,14 @@ func fetchLogs(opts *logsOptions) error {
 		tracker.UpdateWorkflowStatus(workflow.Status)
 	}
 
+	// Stream archives written to a file so large downloads report progress
+	if opts.output != "" && !opts.follow {
+		if _, err := client.DownloadWorkflowLogs(ctx, owner, repo, runID, out, progress.NewConsoleTracker()); err != nil {
+			return fmt.Errorf("failed to get workflow logs: %w", err)
+		}
+		return nil
+	}
+
 	// Get logs
 	logs, err := client.GetWorkflowLogs(ctx, owner, repo, runID)
 	if err != nil {
@@ -150,31 +169,61 @@ func fetchLogs(opts *logsOptions) error {
 
 	// If following, continue to poll for new logs while the workflow is running
 	if opts.follow && run.Status != "completed" {
-		lastSize := len(logs)
-		for {
-			run, err := client.GetWorkflowRun(ctx, owner, repo, runID)
-			if err != nil {
-				return fmt.Errorf("failed to get workflow status: %w", err)
+		return followLogs(ctx, client, owner, repo, runID, len(logs), out, tracker, followPollInterval)
+	}
+
+	return nil
+}
+
+// followLogs polls the workflow run and writes newly appended log output
+// until the run completes. Polling backs off while no new output arrives.
+// Cancelling ctx flushes what has been written so far and returns nil.
+func followLogs(ctx context.Context, client logsClient, owner, repo string, runID int64, lastSize int, out io.Writer, tracker *progress.WorkflowTracker, interval time.Duration) error {
+	w := bufio.NewWriter(out)
+	defer w.Flush()
+
+	delay := interval
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+
+		run, err := client.GetWorkflowRun(ctx, owner, repo, runID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
 			}
+			return fmt.Errorf("failed to get workflow status: %w", err)
+		}
 
-			logs, err := client.GetWorkflowLogs(ctx, owner, repo, runID)
-			if err != nil {
-				return fmt.Errorf("failed to get workflow logs: %w", err)
+		logs, err := client.GetWorkflowLogs(ctx, owner, repo, runID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
 			}
+			return fmt.Errorf("failed to get workflow logs: %w", err)
+		}
 
-			if len(logs) > lastSize {
-				if _, err := out.Write(logs[lastSize:]); err != nil {
-					return fmt.Errorf("failed to write logs: %w", err)
-				}
-				lastSize = len(logs)
+		if len(logs) > lastSize {
+			if _, err := w.Write(logs[lastSize:]); err != nil {
+				return fmt.Errorf("failed to write logs: %w", err)
 			}
+			if err := w.Flush(); err != nil {
+				return fmt.Errorf("failed to write logs: %w", err)
+			}
+			lastSize = len(logs)
+			delay = interval
+		} else {
+			delay = min(delay*2, followMaxPollInterval)
+		}
 
-			if run.Status == "completed" {
+		if run.Status == "completed" {
+			if tracker != nil {
 				tracker.UpdateWorkflowStatus(progress.WorkflowCompleted)
-				break
 			}
+			return nil
 		}
 	}
-
-	return nil
 }
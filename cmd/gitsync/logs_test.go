@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/NicabarNimble/go-gittools/internal/config"
+	"github.com/NicabarNimble/go-gittools/internal/github"
 	"github.com/NicabarNimble/go-gittools/internal/progress"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -260,3 +262,37 @@ func TestLogsCommandWithFiltering(t *testing.T) {
 func joinLogEntries(entries []string) string {
 	return strings.Join(entries, "\n")
 }
+
+// fakeLogsClient reports a run that never completes and appends a log line
+// on every poll
+type fakeLogsClient struct {
+	logs  []byte
+	polls int
+}
+
+func (f *fakeLogsClient) GetWorkflowRun(ctx context.Context, owner, repo string, runID int64) (*github.WorkflowRun, error) {
+	return &github.WorkflowRun{ID: runID, Status: "in_progress"}, nil
+}
+
+func (f *fakeLogsClient) GetWorkflowLogs(ctx context.Context, owner, repo string, runID int64) ([]byte, error) {
+	f.polls++
+	f.logs = append(f.logs, []byte("line\n")...)
+	return f.logs, nil
+}
+
+func TestFollowLogsCancellation(t *testing.T) {
+	client := &fakeLogsClient{}
+	var out bytes.Buffer
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	err := followLogs(ctx, client, "owner", "repo", 1, 0, &out, nil, 5*time.Millisecond)
+	elapsed := time.Since(start)
+
+	assert.NoError(t, err)
+	assert.Less(t, elapsed, time.Second, "followLogs should return promptly after cancellation")
+	assert.Greater(t, client.polls, 0)
+	assert.Equal(t, strings.Repeat("line\n", client.polls), out.String())
+}
@@ -4,9 +4,16 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/NicabarNimble/go-gittools/internal/buildinfo"
 	"github.com/spf13/cobra"
 )
 
+// debug enables HTTP request logging to stderr for GitHub API calls
+var debug bool
+
+// noValidate skips validating the GitHub token when creating a client
+var noValidate bool
+
 func newRootCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "gitsync",
@@ -15,6 +22,9 @@ func newRootCmd() *cobra.Command {
 Supports initializing workflows, triggering syncs, checking status, and viewing logs.`,
 	}
 
+	cmd.PersistentFlags().BoolVar(&debug, "debug", false, "Log GitHub API requests and responses to stderr (tokens are redacted)")
+	cmd.PersistentFlags().BoolVar(&noValidate, "no-validate", false, "Skip validating the GitHub token up front; a bad token fails on first use instead")
+
 	// Add subcommands
 	cmd.AddCommand(
 		newInitCmd(),
@@ -22,7 +32,10 @@ Supports initializing workflows, triggering syncs, checking status, and viewing
 		newStatusCmd(),
 		newLogsCmd(),
 		newConfigureCmd(),
+		newScheduleCmd(),
+		newCompletionCmd(),
 	)
+	buildinfo.AddTo(cmd)
 
 	return cmd
 }
@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -25,7 +26,7 @@ func TestSubcommands(t *testing.T) {
 		commandNames[subcmd.Name()] = true
 	}
 
-	expectedCommands := []string{"init", "run", "status", "logs", "configure"}
+	expectedCommands := []string{"init", "run", "status", "logs", "configure", "schedule"}
 	for _, expected := range expectedCommands {
 		assert.True(t, commandNames[expected], "Expected command %s not found", expected)
 	}
@@ -87,3 +88,25 @@ func TestConfigureCommand(t *testing.T) {
 	cmd := newConfigureCmd()
 	assert.NotNil(t, cmd)
 }
+
+func TestShowSchedule(t *testing.T) {
+	buf := new(bytes.Buffer)
+	now := time.Date(2025, 2, 1, 10, 17, 0, 0, time.UTC)
+	opts := &scheduleOptions{schedule: "*/30 * * * *", count: 2}
+
+	err := showSchedule(buf, opts, now)
+	assert.NoError(t, err)
+	assert.Equal(t, "Schedule: */30 * * * *\n  2025-02-01T10:30:00Z\n  2025-02-01T11:00:00Z\n", buf.String())
+}
+
+func TestStatusCommandInvalidStatus(t *testing.T) {
+	cmd := newStatusCmd()
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"--repo", "owner/repo", "--run-id", "123", "--status", "invalid"})
+
+	err := cmd.Execute()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid workflow status "invalid"`)
+}
@@ -2,21 +2,23 @@ package main
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"strings"
+	"os"
 	"time"
 
+	"github.com/NicabarNimble/go-gittools/internal/config"
 	"github.com/NicabarNimble/go-gittools/internal/github"
+	"github.com/NicabarNimble/go-gittools/internal/notify"
 	"github.com/NicabarNimble/go-gittools/internal/progress"
-	"github.com/NicabarNimble/go-gittools/internal/token"
 	"github.com/spf13/cobra"
 )
 
 type runOptions struct {
-	repo    string
-	timeout time.Duration
-	wait    bool
+	repo       string
+	timeout    time.Duration
+	wait       bool
+	account    string
+	configFile string
 }
 
 func newRunCmd() *cobra.Command {
@@ -26,7 +28,9 @@ func newRunCmd() *cobra.Command {
 		Use:   "run",
 		Short: "Trigger sync workflow",
 		Long: `Trigger a GitHub Actions workflow to sync repositories.
-The command can either trigger the workflow and exit, or wait for completion.`,
+The command can either trigger the workflow and exit, or wait for completion.
+When waiting, a failed sync is reported through the notifications configured
+in error_handling.`,
 		Example: `  gitsync run --repo owner/repo
   gitsync run --repo owner/repo --wait
   gitsync run --repo owner/repo --wait --timeout 10m`,
@@ -36,9 +40,12 @@ The command can either trigger the workflow and exit, or wait for completion.`,
 	}
 
 	cmd.Flags().StringVar(&opts.repo, "repo", "", "Repository to sync (owner/repo)")
+	cmd.Flags().StringVar(&opts.account, "account", "", "Named GitHub account token to use (reads GIT_TOKEN_GITHUB_<ACCOUNT>)")
 	cmd.Flags().BoolVar(&opts.wait, "wait", false, "Wait for workflow completion")
 	cmd.Flags().DurationVar(&opts.timeout, "timeout", 30*time.Minute, "Timeout duration when waiting")
+	cmd.Flags().StringVar(&opts.configFile, "config", "", configFlagUsage)
 	cmd.MarkFlagRequired("repo")
+	cmd.RegisterFlagCompletionFunc("repo", completeRepos)
 
 	return cmd
 }
@@ -57,33 +64,24 @@ func runSync(opts *runOptions) error {
 		defer cancel()
 	}
 
-	// Initialize progress tracker
-	tracker := progress.NewWorkflowTracker()
-
-	// Get and validate GitHub token
-	storage := token.NewEnvStorage()
-	t, err := storage.Retrieve(ctx, "GITHUB")
-	if err != nil {
-		if errors.Is(err, token.ErrTokenNotFound) {
-			return fmt.Errorf("GitHub token not found in environment. Set GIT_TOKEN_GITHUB environment variable")
+	// Set up failure notifications before triggering anything
+	var notifier notify.Notifier
+	if opts.wait {
+		configFile, err := resolveConfigPath(opts.configFile)
+		if err != nil {
+			return err
 		}
-		if errors.Is(err, token.ErrTokenExpired) {
-			return fmt.Errorf("GitHub token has expired. Please refresh or provide a new token")
+		cfg, err := config.LoadConfig(configFile)
+		if err != nil {
+			return err
 		}
-		if errors.Is(err, token.ErrTokenInvalid) {
-			return fmt.Errorf("GitHub token is invalid. Check token format in GIT_TOKEN_GITHUB environment variable")
+		if notifier, err = notify.FromConfig(cfg.ErrorHandling); err != nil {
+			return fmt.Errorf("invalid notification settings: %w", err)
 		}
-		return fmt.Errorf("failed to get GitHub token: %w", err)
 	}
 
-	// Pre-validate token before creating client
-	validator := github.NewTokenValidator()
-	if err := validator.Validate(ctx, &t); err != nil {
-		if strings.Contains(err.Error(), "missing required scopes") {
-			return fmt.Errorf("GitHub token is missing required scopes (repo, workflow, admin:repo). Please check token permissions")
-		}
-		return fmt.Errorf("GitHub token validation failed: %w", err)
-	}
+	// Initialize progress tracker
+	tracker := progress.NewWorkflowTracker()
 
 	// Parse owner and repo
 	owner, repo, err := github.ParseRepo(opts.repo)
@@ -91,9 +89,10 @@ func runSync(opts *runOptions) error {
 		return fmt.Errorf("failed to parse repository: %w", err)
 	}
 
-	client, err := github.NewClient(ctx, &t)
+	// Create GitHub client
+	client, err := newGitHubClient(ctx, opts.account)
 	if err != nil {
-		return fmt.Errorf("failed to create GitHub client: %w", err)
+		return err
 	}
 
 	// Trigger workflow
@@ -102,16 +101,15 @@ func runSync(opts *runOptions) error {
 	}
 
 	// Get the latest workflow run
-	runs, err := client.ListWorkflowRuns(ctx, owner, repo, "sync.yml")
+	latestRun, err := client.LatestWorkflowRun(ctx, owner, repo, "sync.yml")
 	if err != nil {
-		return fmt.Errorf("failed to list workflow runs: %w", err)
+		return fmt.Errorf("failed to get latest workflow run: %w", err)
 	}
 
-	if len(runs) == 0 {
+	if latestRun == nil {
 		return fmt.Errorf("no workflow runs found")
 	}
 
-	latestRun := runs[0]
 	workflow := tracker.StartWorkflow("Repository Sync", latestRun.ID, latestRun.ID)
 
 	fmt.Printf("Triggered workflow run #%d\n", latestRun.ID)
@@ -125,7 +123,8 @@ func runSync(opts *runOptions) error {
 	for {
 		select {
 		case <-ctx.Done():
-			return fmt.Errorf("timeout waiting for workflow completion")
+			return notifyFailure(context.WithoutCancel(ctx), notifier, opts.repo, latestRun.ID,
+				fmt.Errorf("timeout waiting for workflow completion"))
 		default:
 			run, err := client.GetWorkflowRun(ctx, owner, repo, latestRun.ID)
 			if err != nil {
@@ -141,7 +140,8 @@ func runSync(opts *runOptions) error {
 				}
 				workflow.Status = progress.WorkflowFailed
 				tracker.UpdateWorkflowStatus(progress.WorkflowFailed)
-				return fmt.Errorf("workflow failed with conclusion: %s", run.Conclusion)
+				return notifyFailure(ctx, notifier, opts.repo, run.ID,
+					fmt.Errorf("workflow failed with conclusion: %s", run.Conclusion))
 			case "queued":
 				workflow.Status = progress.WorkflowQueued
 				tracker.UpdateWorkflowStatus(progress.WorkflowQueued)
@@ -155,3 +155,18 @@ func runSync(opts *runOptions) error {
 		}
 	}
 }
+
+// notifyFailure reports a failed sync through n, if one is configured, and
+// returns failure. Delivery errors are printed but never mask the failure.
+func notifyFailure(ctx context.Context, n notify.Notifier, repo string, runID int64, failure error) error {
+	if n == nil {
+		return failure
+	}
+
+	subject := fmt.Sprintf("gitsync: sync failed for %s", repo)
+	body := fmt.Sprintf("Workflow run #%d for %s failed: %v", runID, repo, failure)
+	if err := n.Notify(ctx, subject, body); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to send failure notification: %v\n", err)
+	}
+	return failure
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -242,3 +244,31 @@ func TestRunCommandWithProgress(t *testing.T) {
 	assert.NotEmpty(t, progress.StartTime)
 	assert.Equal(t, []string{"main"}, progress.Branches)
 }
+
+// mockNotifier records the notifications it is asked to send
+type mockNotifier struct {
+	subjects []string
+	bodies   []string
+}
+
+func (m *mockNotifier) Notify(ctx context.Context, subject, body string) error {
+	m.subjects = append(m.subjects, subject)
+	m.bodies = append(m.bodies, body)
+	return nil
+}
+
+func TestNotifyFailure(t *testing.T) {
+	notifier := &mockNotifier{}
+	failure := errors.New("workflow failed with conclusion: failure")
+
+	err := notifyFailure(context.Background(), notifier, "owner/repo", 42, failure)
+	assert.Equal(t, failure, err)
+
+	require.Len(t, notifier.subjects, 1)
+	assert.Contains(t, notifier.subjects[0], "owner/repo")
+	assert.Contains(t, notifier.bodies[0], "#42")
+	assert.Contains(t, notifier.bodies[0], "workflow failed with conclusion: failure")
+
+	// Without a notifier the failure is returned unchanged
+	assert.Equal(t, failure, notifyFailure(context.Background(), nil, "owner/repo", 42, failure))
+}
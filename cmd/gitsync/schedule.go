@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/NicabarNimble/go-gittools/internal/config"
+	"github.com/spf13/cobra"
+)
+
+type scheduleOptions struct {
+	schedule   string
+	count      int
+	configFile string
+}
+
+func newScheduleCmd() *cobra.Command {
+	opts := &scheduleOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Show upcoming sync times",
+		Long: `Show when the configured sync schedule will next run.
+Times are shown in UTC, the time zone GitHub Actions uses for schedules.`,
+		Example: `  gitsync schedule
+  gitsync schedule --count 10
+  gitsync schedule --schedule "0 */6 * * *"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return showSchedule(cmd.OutOrStdout(), opts, time.Now())
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.schedule, "schedule", "", "Cron schedule to evaluate (default: the configured schedule)")
+	cmd.Flags().IntVar(&opts.count, "count", 5, "Number of upcoming runs to show")
+	cmd.Flags().StringVar(&opts.configFile, "config", "", configFlagUsage)
+
+	return cmd
+}
+
+func showSchedule(out io.Writer, opts *scheduleOptions, now time.Time) error {
+	if opts.count <= 0 {
+		return fmt.Errorf("count must be positive")
+	}
+
+	schedule := opts.schedule
+	if schedule == "" {
+		configFile, err := resolveConfigPath(opts.configFile)
+		if err != nil {
+			return err
+		}
+		cfg, err := config.LoadConfig(configFile)
+		if err != nil {
+			return err
+		}
+		schedule = cfg.Schedule
+	}
+
+	runs, err := config.NextRuns(schedule, now.UTC(), opts.count)
+	if err != nil {
+		return fmt.Errorf("invalid schedule: %w", err)
+	}
+
+	fmt.Fprintf(out, "Schedule: %s\n", schedule)
+	for _, run := range runs {
+		fmt.Fprintf(out, "  %s\n", run.Format(time.RFC3339))
+	}
+	return nil
+}
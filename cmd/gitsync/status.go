@@ -2,23 +2,23 @@ package main
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/NicabarNimble/go-gittools/internal/github"
 	"github.com/NicabarNimble/go-gittools/internal/progress"
-	"github.com/NicabarNimble/go-gittools/internal/token"
 	"github.com/spf13/cobra"
 )
 
 type statusOptions struct {
-	repo   string
-	runID  string
-	watch  bool
-	format string
+	repo      string
+	runID     string
+	watch     bool
+	format    string
+	account   string
+	status    string
+	outputDir string
 }
 
 func newStatusCmd() *cobra.Command {
@@ -31,18 +31,25 @@ func newStatusCmd() *cobra.Command {
 Optionally watch the workflow progress in real-time.`,
 		Example: `  gitsync status --repo owner/repo --run-id 123456
   gitsync status --repo owner/repo --run-id 123456 --watch
-  gitsync status --repo owner/repo --run-id 123456 --format json`,
+  gitsync status --repo owner/repo --run-id 123456 --format json
+  gitsync status --repo owner/repo --run-id 123456 --status completed
+  gitsync status --repo owner/repo --run-id 123456 --output-dir ./runs`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return checkStatus(opts)
 		},
 	}
 
 	cmd.Flags().StringVar(&opts.repo, "repo", "", "Repository to check (owner/repo)")
+	cmd.Flags().StringVar(&opts.account, "account", "", "Named GitHub account token to use (reads GIT_TOKEN_GITHUB_<ACCOUNT>)")
 	cmd.Flags().StringVar(&opts.runID, "run-id", "", "Workflow run ID")
 	cmd.Flags().BoolVar(&opts.watch, "watch", false, "Watch workflow progress")
 	cmd.Flags().StringVar(&opts.format, "format", "text", "Output format (text or json)")
+	cmd.Flags().StringVar(&opts.status, "status", "", "Fail unless the run has this status (queued, in_progress, completed, failed)")
+	cmd.Flags().StringVar(&opts.outputDir, "output-dir", "", "Directory to archive <run-id>.json into")
 	cmd.MarkFlagRequired("repo")
 	cmd.MarkFlagRequired("run-id")
+	cmd.RegisterFlagCompletionFunc("repo", completeRepos)
+	cmd.RegisterFlagCompletionFunc("run-id", completeRunIDs)
 
 	return cmd
 }
@@ -54,6 +61,15 @@ func checkStatus(opts *statusOptions) error {
 		return fmt.Errorf("invalid run ID: %w", err)
 	}
 
+	// Validate the expected status before making any API calls
+	var wantStatus progress.WorkflowStatus
+	if opts.status != "" {
+		wantStatus, err = progress.ParseWorkflowStatus(opts.status)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Create context
 	ctx := context.Background()
 
@@ -63,31 +79,6 @@ func checkStatus(opts *statusOptions) error {
 		tracker = progress.NewWorkflowTracker()
 	}
 
-	// Get and validate GitHub token
-	storage := token.NewEnvStorage()
-	t, err := storage.Retrieve(ctx, "GITHUB")
-	if err != nil {
-		if errors.Is(err, token.ErrTokenNotFound) {
-			return fmt.Errorf("GitHub token not found in environment. Set GIT_TOKEN_GITHUB environment variable")
-		}
-		if errors.Is(err, token.ErrTokenExpired) {
-			return fmt.Errorf("GitHub token has expired. Please refresh or provide a new token")
-		}
-		if errors.Is(err, token.ErrTokenInvalid) {
-			return fmt.Errorf("GitHub token is invalid. Check token format in GIT_TOKEN_GITHUB environment variable")
-		}
-		return fmt.Errorf("failed to get GitHub token: %w", err)
-	}
-
-	// Pre-validate token before creating client
-	validator := github.NewTokenValidator()
-	if err := validator.Validate(ctx, &t); err != nil {
-		if strings.Contains(err.Error(), "missing required scopes") {
-			return fmt.Errorf("GitHub token is missing required scopes (repo, workflow, admin:repo). Please check token permissions")
-		}
-		return fmt.Errorf("GitHub token validation failed: %w", err)
-	}
-
 	// Parse owner and repo
 	owner, repo, err := github.ParseRepo(opts.repo)
 	if err != nil {
@@ -95,9 +86,9 @@ func checkStatus(opts *statusOptions) error {
 	}
 
 	// Create GitHub client
-	client, err := github.NewClient(ctx, &t)
+	client, err := newGitHubClient(ctx, opts.account)
 	if err != nil {
-		return fmt.Errorf("failed to create GitHub client: %w", err)
+		return err
 	}
 
 	// Get workflow run
@@ -106,6 +97,12 @@ func checkStatus(opts *statusOptions) error {
 		return fmt.Errorf("failed to get workflow run: %w", err)
 	}
 
+	if opts.outputDir != "" {
+		if err := archiveStatus(opts.outputDir, run); err != nil {
+			return err
+		}
+	}
+
 	if !opts.watch {
 		// Single status check
 		if opts.format == "json" {
@@ -121,6 +118,11 @@ func checkStatus(opts *statusOptions) error {
 			fmt.Printf("Created: %s\n", run.CreatedAt.Format(time.RFC3339))
 			fmt.Printf("Updated: %s\n", run.UpdatedAt.Format(time.RFC3339))
 		}
+		if wantStatus != "" {
+			if got := runStatus(run); got != wantStatus {
+				return fmt.Errorf("workflow run #%d is %s, not %s", run.ID, got, wantStatus)
+			}
+		}
 		return nil
 	}
 
@@ -133,24 +135,38 @@ func checkStatus(opts *statusOptions) error {
 			return fmt.Errorf("failed to get workflow status: %w", err)
 		}
 
-		switch run.Status {
-		case "completed":
-			if run.Conclusion == "success" {
-				workflow.Status = progress.WorkflowCompleted
-				tracker.UpdateWorkflowStatus(progress.WorkflowCompleted)
-				return nil
+		if opts.outputDir != "" {
+			if err := archiveStatus(opts.outputDir, run); err != nil {
+				return err
 			}
-			workflow.Status = progress.WorkflowFailed
-			tracker.UpdateWorkflowStatus(progress.WorkflowFailed)
+		}
+
+		status := runStatus(run)
+		workflow.Status = status
+		tracker.UpdateWorkflowStatus(status)
+
+		switch status {
+		case progress.WorkflowCompleted:
+			return nil
+		case progress.WorkflowFailed:
 			return fmt.Errorf("workflow failed with conclusion: %s", run.Conclusion)
-		case "queued":
-			workflow.Status = progress.WorkflowQueued
-			tracker.UpdateWorkflowStatus(progress.WorkflowQueued)
-		default:
-			workflow.Status = progress.WorkflowInProgress
-			tracker.UpdateWorkflowStatus(progress.WorkflowInProgress)
 		}
 
 		time.Sleep(5 * time.Second)
 	}
 }
+
+// runStatus maps a GitHub workflow run onto the tracker's status values
+func runStatus(run *github.WorkflowRun) progress.WorkflowStatus {
+	switch run.Status {
+	case "completed":
+		if run.Conclusion == "success" {
+			return progress.WorkflowCompleted
+		}
+		return progress.WorkflowFailed
+	case "queued":
+		return progress.WorkflowQueued
+	default:
+		return progress.WorkflowInProgress
+	}
+}
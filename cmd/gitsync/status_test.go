@@ -14,16 +14,6 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-// progressEntry represents a progress file entry for testing
-type progressEntry struct {
-	RunID     string                `json:"run_id"`
-	Status    progress.WorkflowStatus `json:"status"`
-	Branches  []string              `json:"branches"`
-	StartTime string                `json:"start_time"`
-	EndTime   string                `json:"end_time,omitempty"`
-	Error     string                `json:"error,omitempty"`
-}
-
 func TestStatusCommandExecution(t *testing.T) {
 	// Create a temporary directory for test files
 	tempDir, err := os.MkdirTemp("", "gitsync-status-test-*")
@@ -9,9 +9,12 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/NicabarNimble/go-gittools/internal/buildinfo"
 	"github.com/NicabarNimble/go-gittools/internal/token"
-	"github.com/NicabarNimble/go-gittools/internal/github"
-	"github.com/NicabarNimble/go-gittools/internal/gitlab"
+
+	// Provider packages register their validators with the token package
+	_ "github.com/NicabarNimble/go-gittools/internal/github"
+	_ "github.com/NicabarNimble/go-gittools/internal/gitlab"
 )
 
 var (
@@ -19,6 +22,7 @@ var (
 	expires       string
 	tokenFile     string
 	nonInteractive bool
+	accountKey    string
 	osExit        = os.Exit // For testing purposes
 )
 
@@ -53,8 +57,10 @@ Validates the token and configures storage.`,
 	setupCmd.Flags().StringVarP(&expires, "expires", "e", "", "Token expiration (e.g., 30d, 1y)")
 	setupCmd.Flags().StringVarP(&tokenFile, "token-file", "f", "", "File containing the token value")
 	setupCmd.Flags().BoolVarP(&nonInteractive, "non-interactive", "n", false, "Run in non-interactive mode")
+	setupCmd.Flags().StringVarP(&accountKey, "key", "k", "", "Account name for storing multiple tokens per provider (e.g., work, personal)")
 
 	rootCmd.AddCommand(setupCmd)
+	buildinfo.AddTo(rootCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
@@ -152,48 +158,38 @@ func setupToken(cmd *cobra.Command, args []string) {
 	}
 
 	// Validate token with provider's API
-	var tokenInfo string
-	switch detectedProvider {
-	case token.ProviderGitHub:
-		validator := github.NewTokenValidator()
-		if err := validator.Validate(ctx, newToken); err != nil {
-			var scopeErr *token.ScopeError
-			if errors.As(err, &scopeErr) {
-				fmt.Println("\nRequired GitHub token scopes:")
-				for scope, present := range scopeErr.Status {
-					status := "✓"
-					if !present {
-						status = "✗"
-					}
-					fmt.Printf("%s %s\n", status, scope)
+	validator, err := token.ValidatorFor(detectedProvider)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		osExit(1)
+	}
+	if err := validator.Validate(ctx, newToken); err != nil {
+		var scopeErr *token.ScopeError
+		if detectedProvider == token.ProviderGitLab && strings.Contains(err.Error(), "missing required scopes") {
+			fmt.Printf("Error: GitLab token is missing required scopes (api). Please check token permissions\n")
+		} else if errors.As(err, &scopeErr) {
+			fmt.Printf("\nRequired %s token scopes:\n", detectedProvider)
+			for scope, present := range scopeErr.Status {
+				status := "✓"
+				if !present {
+					status = "✗"
 				}
-				fmt.Printf("\nError: Token is missing required scopes. Please add the missing scopes marked with ✗\n")
-			} else if errors.Is(err, token.ErrTokenExpired) {
-				fmt.Printf("Error: GitHub token has expired. Please provide a new token\n")
-			} else {
-				fmt.Printf("Error validating GitHub token: %v\n", err)
-			}
-			osExit(1)
-		}
-		tokenInfo = fmt.Sprintf("Scopes: %s", newToken.Scope)
-	case token.ProviderGitLab:
-		validator := gitlab.NewTokenValidator()
-		if err := validator.Validate(ctx, newToken); err != nil {
-			if strings.Contains(err.Error(), "missing required scopes") {
-				fmt.Printf("Error: GitLab token is missing required scopes (api). Please check token permissions\n")
-			} else if errors.Is(err, token.ErrTokenExpired) {
-				fmt.Printf("Error: GitLab token has expired. Please provide a new token\n")
-			} else {
-				fmt.Printf("Error validating GitLab token: %v\n", err)
+				fmt.Printf("%s %s\n", status, scope)
 			}
-			osExit(1)
+			fmt.Printf("\nError: Token is missing required scopes. Please add the missing scopes marked with ✗\n")
+		} else if errors.Is(err, token.ErrTokenExpired) {
+			fmt.Printf("Error: %s token has expired. Please provide a new token\n", detectedProvider)
+		} else {
+			fmt.Printf("Error validating %s token: %v\n", detectedProvider, err)
 		}
-		tokenInfo = fmt.Sprintf("Scopes: %s", newToken.Scope)
+		osExit(1)
 	}
+	tokenInfo := fmt.Sprintf("Scopes: %s", newToken.Scope)
 
 	// Store validated token in environment
 	envStorage := token.NewEnvStorage()
-	if err := envStorage.Store(ctx, string(detectedProvider), *newToken); err != nil {
+	storageKey := token.Key(detectedProvider, accountKey)
+	if err := envStorage.Store(ctx, storageKey, *newToken); err != nil {
 		if errors.Is(err, token.ErrStorageUnavailable) {
 			fmt.Printf("Error: Unable to access token storage. Please check environment permissions\n")
 		} else {
@@ -205,6 +201,9 @@ func setupToken(cmd *cobra.Command, args []string) {
 	fmt.Printf("\nSuccessfully configured %s token!\n", detectedProvider)
 	fmt.Println("\nToken details:")
 	fmt.Printf("Provider: %s\n", detectedProvider)
+	if accountKey != "" {
+		fmt.Printf("Account: %s\n", accountKey)
+	}
 	fmt.Println(tokenInfo)
 	if !newToken.ExpiresAt.IsZero() {
 		fmt.Printf("Expires: %s\n", newToken.ExpiresAt.Format("January 2, 2006 at 3:04 PM MST"))
@@ -216,7 +215,7 @@ func setupToken(cmd *cobra.Command, args []string) {
 		fmt.Println("Expires: Never")
 	}
 
-	fmt.Printf("\nEnvironment variable set: GIT_TOKEN_%s\n", detectedProvider)
+	fmt.Printf("\nEnvironment variable set: %s\n", envStorage.FormatEnvKey(storageKey))
 }
 
 // loadFromEnv loads token configuration from environment variables
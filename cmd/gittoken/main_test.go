@@ -1,10 +1,16 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/NicabarNimble/go-gittools/internal/gitlab"
+	"github.com/NicabarNimble/go-gittools/internal/token"
 	"github.com/spf13/cobra"
 )
 
@@ -179,3 +185,48 @@ func TestSetupCommand(t *testing.T) {
 		})
 	}
 }
+
+// scopeMissingValidator rejects every token for missing scopes
+type scopeMissingValidator struct{}
+
+func (scopeMissingValidator) Validate(ctx context.Context, t *token.Token) error {
+	return fmt.Errorf("%w: api", token.ErrScopeMissing)
+}
+
+func TestSetupGitLabMissingScope(t *testing.T) {
+	originalOsExit := osExit
+	defer func() { osExit = originalOsExit }()
+	var exitCode int
+	osExit = func(code int) {
+		exitCode = code
+		panic(fmt.Sprintf("os.Exit(%d)", code))
+	}
+
+	token.RegisterValidator(token.ProviderGitLab, func() token.Validator { return scopeMissingValidator{} })
+	defer token.RegisterValidator(token.ProviderGitLab, func() token.Validator { return gitlab.NewTokenValidator() })
+
+	originalStdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+
+	value = "glpat-test123456789"
+	expires = "30d"
+	func() {
+		defer func() { recover() }()
+		setupToken(&cobra.Command{}, nil)
+	}()
+	w.Close()
+	os.Stdout = originalStdout
+	out, _ := io.ReadAll(r)
+
+	if exitCode != 1 {
+		t.Errorf("setupToken() exit code = %d, want 1", exitCode)
+	}
+	want := "Error: GitLab token is missing required scopes (api). Please check token permissions"
+	if !strings.Contains(string(out), want) {
+		t.Errorf("output = %q, want it to contain %q", out, want)
+	}
+}
@@ -0,0 +1,115 @@
+// Package buildinfo holds version information for the go-gittools binaries.
+// The variables are set at build time, e.g.:
+//
+//	go build -ldflags "-X github.com/NicabarNimble/go-gittools/internal/buildinfo.Version=v1.2.0 \
+//	  -X github.com/NicabarNimble/go-gittools/internal/buildinfo.Commit=abc1234 \
+//	  -X github.com/NicabarNimble/go-gittools/internal/buildinfo.Date=2025-02-01T12:00:00Z"
+package buildinfo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/spf13/cobra"
+)
+
+// Populated via -ldflags; empty values fall back to the defaults below
+var (
+	Version string
+	Commit  string
+	Date    string
+)
+
+const unknown = "unknown"
+
+// Info describes the running binary
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Date      string `json:"date"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get returns the build information. Values not set through -ldflags are
+// taken from the VCS metadata embedded by the Go toolchain when available.
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		Date:      Date,
+		GoVersion: runtime.Version(),
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		if info.Version == "" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+			info.Version = bi.Main.Version
+		}
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = s.Value
+				}
+			case "vcs.time":
+				if info.Date == "" {
+					info.Date = s.Value
+				}
+			}
+		}
+	}
+
+	if info.Version == "" {
+		info.Version = "dev"
+	}
+	if info.Commit == "" {
+		info.Commit = unknown
+	}
+	if info.Date == "" {
+		info.Date = unknown
+	}
+	return info
+}
+
+// String formats the information on a single line
+func (i Info) String() string {
+	return fmt.Sprintf("%s (commit %s, built %s, %s)", i.Version, i.Commit, i.Date, i.GoVersion)
+}
+
+// Write prints the build information for the named binary, as JSON if asJSON is set
+func Write(w io.Writer, name string, asJSON bool) error {
+	info := Get()
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(info)
+	}
+	_, err := fmt.Fprintf(w, "%s %s\n", name, info)
+	return err
+}
+
+// NewCommand returns a "version" subcommand for cobra-based CLIs
+func NewCommand() *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Print version information",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Write(cmd.OutOrStdout(), cmd.Root().Name(), asJSON)
+		},
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print version information as JSON")
+
+	return cmd
+}
+
+// AddTo registers the version subcommand and --version flag on root
+func AddTo(root *cobra.Command) {
+	root.Version = Get().String()
+	root.SetVersionTemplate("{{.Name}} {{.Version}}\n")
+	root.AddCommand(NewCommand())
+}
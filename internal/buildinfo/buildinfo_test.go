@@ -0,0 +1,64 @@
+package buildinfo
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestGetDefaults(t *testing.T) {
+	info := Get()
+	if info.Version == "" || info.Commit == "" || info.Date == "" || info.GoVersion == "" {
+		t.Errorf("Get() left fields empty: %+v", info)
+	}
+}
+
+func TestGetFromLdflags(t *testing.T) {
+	defer func(v, c, d string) { Version, Commit, Date = v, c, d }(Version, Commit, Date)
+	Version, Commit, Date = "v1.2.3", "abc1234", "2025-02-01T12:00:00Z"
+
+	info := Get()
+	if info.Version != "v1.2.3" || info.Commit != "abc1234" || info.Date != "2025-02-01T12:00:00Z" {
+		t.Errorf("Get() = %+v, want ldflags values", info)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, "go-gitsync", true); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	var got map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, buf.String())
+	}
+	for _, key := range []string{"version", "commit", "date", "go_version"} {
+		if got[key] == "" {
+			t.Errorf("JSON output missing %q: %s", key, buf.String())
+		}
+	}
+	if len(got) != 4 {
+		t.Errorf("JSON output has %d keys, want 4: %s", len(got), buf.String())
+	}
+}
+
+func TestAddTo(t *testing.T) {
+	root := &cobra.Command{Use: "go-gitsync"}
+	AddTo(root)
+
+	for _, args := range [][]string{{"version"}, {"--version"}} {
+		var buf bytes.Buffer
+		root.SetOut(&buf)
+		root.SetArgs(args)
+		if err := root.Execute(); err != nil {
+			t.Fatalf("Execute(%v) error = %v", args, err)
+		}
+		if !strings.HasPrefix(buf.String(), "go-gitsync ") {
+			t.Errorf("Execute(%v) output = %q", args, buf.String())
+		}
+	}
+}
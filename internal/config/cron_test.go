@@ -0,0 +1,137 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextRuns(t *testing.T) {
+	// Saturday
+	from := time.Date(2025, 2, 1, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		schedule string
+		n        int
+		want     []string
+	}{
+		{
+			name:     "every six hours",
+			schedule: "0 */6 * * *",
+			n:        3,
+			want:     []string{"2025-02-01T12:00:00Z", "2025-02-01T18:00:00Z", "2025-02-02T00:00:00Z"},
+		},
+		{
+			name:     "every fifteen minutes",
+			schedule: "*/15 * * * *",
+			n:        3,
+			want:     []string{"2025-02-01T10:30:00Z", "2025-02-01T10:45:00Z", "2025-02-01T11:00:00Z"},
+		},
+		{
+			name:     "stepped range",
+			schedule: "0-30/10 9 * * *",
+			n:        4,
+			want:     []string{"2025-02-02T09:00:00Z", "2025-02-02T09:10:00Z", "2025-02-02T09:20:00Z", "2025-02-02T09:30:00Z"},
+		},
+		{
+			name:     "weekdays at midnight",
+			schedule: "0 0 * * 1-5",
+			n:        2,
+			want:     []string{"2025-02-03T00:00:00Z", "2025-02-04T00:00:00Z"},
+		},
+		{
+			name:     "day of month or day of week",
+			schedule: "30 8 15 * 0",
+			n:        3,
+			want:     []string{"2025-02-02T08:30:00Z", "2025-02-09T08:30:00Z", "2025-02-15T08:30:00Z"},
+		},
+		{
+			name:     "Sunday as 7",
+			schedule: "30 8 * * 7",
+			n:        2,
+			want:     []string{"2025-02-02T08:30:00Z", "2025-02-09T08:30:00Z"},
+		},
+		{
+			name:     "range ending on Sunday as 7",
+			schedule: "0 12 * * 5-7",
+			n:        3,
+			want:     []string{"2025-02-01T12:00:00Z", "2025-02-02T12:00:00Z", "2025-02-07T12:00:00Z"},
+		},
+		{
+			name:     "weekday names",
+			schedule: "0 0 * * MON-FRI",
+			n:        2,
+			want:     []string{"2025-02-03T00:00:00Z", "2025-02-04T00:00:00Z"},
+		},
+		{
+			name:     "month and weekday names",
+			schedule: "0 0 * mar,Apr sun",
+			n:        2,
+			want:     []string{"2025-03-02T00:00:00Z", "2025-03-09T00:00:00Z"},
+		},
+		{
+			name:     "leap day",
+			schedule: "0 0 29 2 *",
+			n:        1,
+			want:     []string{"2028-02-29T00:00:00Z"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs, err := NextRuns(tt.schedule, from, tt.n)
+			assert.NoError(t, err)
+
+			got := make([]string, len(runs))
+			for i, run := range runs {
+				got[i] = run.Format(time.RFC3339)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNextRunsErrors(t *testing.T) {
+	from := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		schedule string
+		wantErr  string
+	}{
+		{name: "empty", schedule: "", wantErr: "schedule cannot be empty"},
+		{name: "too few fields", schedule: "0 0 * *", wantErr: "expected 5 fields"},
+		{name: "out of range", schedule: "60 * * * *", wantErr: "out of range"},
+		{name: "zero step", schedule: "*/0 * * * *", wantErr: "invalid step"},
+		{name: "reversed range", schedule: "0 5-1 * * *", wantErr: "invalid range"},
+		{name: "never fires", schedule: "0 0 30 2 *", wantErr: "never fires"},
+		{name: "day of week past 7", schedule: "0 0 * * 8", wantErr: "out of range"},
+		{name: "unknown name", schedule: "0 0 * * FUN", wantErr: "invalid value"},
+		{name: "name in wrong field", schedule: "0 0 * MON *", wantErr: "invalid value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NextRuns(tt.schedule, from, 1)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestNextRunsNegativeCount(t *testing.T) {
+	from := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := NextRuns("0 * * * *", from, -1)
+	assert.ErrorContains(t, err, "must not be negative")
+
+	runs, err := NextRuns("0 * * * *", from, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, runs)
+}
+
+func TestValidateScheduleDefault(t *testing.T) {
+	assert.NoError(t, ValidateSchedule(DefaultConfig().Schedule))
+}
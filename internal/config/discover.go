@@ -0,0 +1,66 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// DefaultConfigFile is the name of the per-repository sync config
+const DefaultConfigFile = ".gitsync.json"
+
+// ErrConfigNotFound is returned by Discover when no config file exists
+var ErrConfigNotFound = errors.New("no gitsync config file found")
+
+// Discover locates the sync config file. It looks for .gitsync.json in the
+// current directory and each parent, then for gitsync/config.json under
+// $XDG_CONFIG_HOME (default ~/.config).
+func Discover() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return discoverFrom(cwd, userConfigDir())
+}
+
+// discoverFrom searches dir and its parents, then the global config dir
+func discoverFrom(dir, globalDir string) (string, error) {
+	for {
+		path := filepath.Join(dir, DefaultConfigFile)
+		if isFile(path) {
+			return path, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	if globalDir != "" {
+		path := filepath.Join(globalDir, "gitsync", "config.json")
+		if isFile(path) {
+			return path, nil
+		}
+	}
+
+	return "", ErrConfigNotFound
+}
+
+// userConfigDir returns $XDG_CONFIG_HOME, falling back to ~/.config
+func userConfigDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config")
+}
+
+// isFile reports whether path exists and is a regular file
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
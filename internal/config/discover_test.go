@@ -0,0 +1,69 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscover(t *testing.T) {
+	writeConfig := func(t *testing.T, path string) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(`{}`), 0644))
+	}
+
+	tests := []struct {
+		name  string
+		files []string // relative to the test root
+		want  string   // relative to the test root; empty means not found
+	}{
+		{
+			name:  "current directory",
+			files: []string{"repo/sub/.gitsync.json", "repo/.gitsync.json"},
+			want:  "repo/sub/.gitsync.json",
+		},
+		{
+			name:  "parent directory",
+			files: []string{"repo/.gitsync.json", "xdg/gitsync/config.json"},
+			want:  "repo/.gitsync.json",
+		},
+		{
+			name:  "global config",
+			files: []string{"xdg/gitsync/config.json"},
+			want:  "xdg/gitsync/config.json",
+		},
+		{
+			name: "not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			cwd := filepath.Join(root, "repo", "sub")
+			require.NoError(t, os.MkdirAll(cwd, 0755))
+			for _, f := range tt.files {
+				writeConfig(t, filepath.Join(root, f))
+			}
+
+			got, err := discoverFrom(cwd, filepath.Join(root, "xdg"))
+			if tt.want == "" {
+				assert.True(t, errors.Is(err, ErrConfigNotFound), "expected ErrConfigNotFound, got %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, filepath.Join(root, tt.want), got)
+		})
+	}
+}
+
+func TestDiscoverUsesXDGConfigHome(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, "xdg"))
+	assert.Equal(t, filepath.Join(root, "xdg"), userConfigDir())
+}
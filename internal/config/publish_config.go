@@ -14,6 +14,13 @@ type PublishConfig struct {
 	PublicFork  string `json:"publicFork"`
 	Branch      string `json:"branch"`
 	Token       string `json:"token,omitempty"`
+
+	// TokenEnv names an environment variable holding the token. When set,
+	// the token is read from it on load and never written to disk.
+	TokenEnv string `json:"tokenEnv,omitempty"`
+
+	// OmitToken keeps Token in memory only and leaves it out of saved files
+	OmitToken bool `json:"-"`
 }
 
 // LoadPublishConfig loads configuration from a JSON file
@@ -32,21 +39,37 @@ func LoadPublishConfig(path string) (*PublishConfig, error) {
 		return nil, err
 	}
 
+	if config.Token == "" && config.TokenEnv != "" {
+		config.Token = os.Getenv(config.TokenEnv)
+	}
+
 	return &config, nil
 }
 
-// SavePublishConfig saves configuration to a JSON file
+// SavePublishConfig saves configuration to a JSON file. The token is left
+// out when OmitToken or TokenEnv is set. An existing file keeps its
+// permissions; a new file containing a token is created 0600.
 func (c *PublishConfig) SavePublishConfig(path string) error {
 	if err := c.validate(); err != nil {
 		return err
 	}
 
-	data, err := json.MarshalIndent(c, "", "  ")
+	out := *c
+	if c.OmitToken || c.TokenEnv != "" {
+		out.Token = ""
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
 	if err != nil {
 		return errors.New("config", fmt.Errorf("failed to marshal config: %w", err))
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	perm := os.FileMode(0644)
+	if out.Token != "" {
+		perm = 0600
+	}
+
+	if err := writeFileAtomic(path, data, fileMode(path, perm)); err != nil {
 		return errors.New("config", fmt.Errorf("failed to write config file: %w", err))
 	}
 
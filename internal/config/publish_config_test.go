@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -159,6 +160,101 @@ func TestSavePublishConfig(t *testing.T) {
 	}
 }
 
+func TestSavePublishConfigMode(t *testing.T) {
+	tempDir := t.TempDir()
+	config := &PublishConfig{
+		PrivateRepo: "https://github.com/test/private-repo.git",
+		PublicFork:  "https://github.com/test/public-fork.git",
+		Token:       "test-token",
+	}
+
+	// New files holding a token are readable only by the owner
+	newPath := filepath.Join(tempDir, "new.json")
+	if err := config.SavePublishConfig(newPath); err != nil {
+		t.Fatalf("SavePublishConfig() error = %v", err)
+	}
+	if info, err := os.Stat(newPath); err != nil {
+		t.Fatal(err)
+	} else if info.Mode().Perm() != 0600 {
+		t.Errorf("new config mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	// Existing files keep the mode chosen by the user
+	existingPath := filepath.Join(tempDir, "existing.json")
+	if err := os.WriteFile(existingPath, []byte(`{}`), 0640); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(existingPath, 0640); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SavePublishConfig(existingPath); err != nil {
+		t.Fatalf("SavePublishConfig() error = %v", err)
+	}
+	if info, err := os.Stat(existingPath); err != nil {
+		t.Fatal(err)
+	} else if info.Mode().Perm() != 0640 {
+		t.Errorf("existing config mode = %v, want 0640", info.Mode().Perm())
+	}
+}
+
+func TestSavePublishConfigOmitToken(t *testing.T) {
+	tempDir := t.TempDir()
+
+	tests := []struct {
+		name   string
+		config *PublishConfig
+	}{
+		{
+			name: "omit token",
+			config: &PublishConfig{
+				PrivateRepo: "https://github.com/test/private-repo.git",
+				PublicFork:  "https://github.com/test/public-fork.git",
+				Token:       "ghp_secret_value",
+				OmitToken:   true,
+			},
+		},
+		{
+			name: "token from env",
+			config: &PublishConfig{
+				PrivateRepo: "https://github.com/test/private-repo.git",
+				PublicFork:  "https://github.com/test/public-fork.git",
+				Token:       "ghp_secret_value",
+				TokenEnv:    "TEST_PUBLISH_TOKEN",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(tempDir, tt.name+".json")
+			if err := tt.config.SavePublishConfig(configPath); err != nil {
+				t.Fatalf("SavePublishConfig() error = %v", err)
+			}
+
+			data, err := os.ReadFile(configPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(data), "ghp_secret_value") {
+				t.Errorf("saved config contains the token: %s", data)
+			}
+			if tt.config.Token != "ghp_secret_value" {
+				t.Error("SavePublishConfig() cleared the in-memory token")
+			}
+		})
+	}
+
+	// The env var reference is resolved on load
+	t.Setenv("TEST_PUBLISH_TOKEN", "ghp_from_env")
+	loaded, err := LoadPublishConfig(filepath.Join(tempDir, "token from env.json"))
+	if err != nil {
+		t.Fatalf("LoadPublishConfig() error = %v", err)
+	}
+	if loaded.Token != "ghp_from_env" {
+		t.Errorf("loaded token = %q, want %q", loaded.Token, "ghp_from_env")
+	}
+}
+
 func TestDefaultPublishConfig(t *testing.T) {
 	config := DefaultPublishConfig()
 
@@ -3,8 +3,9 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"net/mail"
+	"net/url"
 	"os"
-	"regexp"
 	"strings"
 )
 
@@ -14,6 +15,9 @@ type ErrorConfig struct {
 	RetryDelay    string `json:"retry_delay"`
 	Notify        bool   `json:"notify"`
 	NotifyEmail   string `json:"notify_email,omitempty"`
+	SMTPAddr      string `json:"smtp_addr,omitempty"`   // SMTP server (host:port) used to send NotifyEmail
+	SMTPFrom      string `json:"smtp_from,omitempty"`   // Sender address; defaults to NotifyEmail
+	WebhookURL    string `json:"webhook_url,omitempty"` // Optional Slack or generic webhook for notifications
 }
 
 // DefaultConfig provides default configuration values
@@ -59,14 +63,16 @@ func LoadConfig(path string) (*SyncConfig, error) {
 	return cfg, nil
 }
 
-// SaveConfig saves configuration to a file
+// SaveConfig saves configuration to a file. The file is replaced atomically
+// so an interrupted save leaves the previous configuration intact, and an
+// existing file keeps its permissions.
 func SaveConfig(cfg *SyncConfig, path string) error {
 	data, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	if err := writeFileAtomic(path, data, fileMode(path, 0644)); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
@@ -110,8 +116,21 @@ func (c *SyncConfig) Validate() error {
 	if c.ErrorHandling.RetryAttempts < 0 {
 		return fmt.Errorf("retry attempts cannot be negative")
 	}
-	if c.ErrorHandling.Notify && c.ErrorHandling.NotifyEmail == "" {
-		return fmt.Errorf("notify email is required when notifications are enabled")
+	if c.ErrorHandling.Notify {
+		if c.ErrorHandling.NotifyEmail == "" && c.ErrorHandling.WebhookURL == "" {
+			return fmt.Errorf("notify email or webhook URL is required when notifications are enabled")
+		}
+		if c.ErrorHandling.NotifyEmail != "" {
+			if _, err := mail.ParseAddress(c.ErrorHandling.NotifyEmail); err != nil {
+				return fmt.Errorf("invalid notify email %q: %w", c.ErrorHandling.NotifyEmail, err)
+			}
+		}
+		if c.ErrorHandling.WebhookURL != "" {
+			u, err := url.Parse(c.ErrorHandling.WebhookURL)
+			if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+				return fmt.Errorf("invalid webhook URL %q: must be an http or https URL", c.ErrorHandling.WebhookURL)
+			}
+		}
 	}
 	return nil
 }
@@ -136,24 +155,8 @@ func ValidateRepoFormat(repo string) error {
 
 // ValidateSchedule validates a cron schedule expression
 func ValidateSchedule(schedule string) error {
-	if schedule == "" {
-		return fmt.Errorf("schedule cannot be empty")
-	}
-
-	// Basic cron format validation (minute hour day-of-month month day-of-week)
-	parts := strings.Fields(schedule)
-	if len(parts) != 5 {
-		return fmt.Errorf("invalid cron format, expected 5 fields (minute hour day-of-month month day-of-week)")
-	}
-
-	// Validate each field
-	for i, part := range parts {
-		if err := validateCronField(part, i); err != nil {
-			return fmt.Errorf("invalid cron field %d: %w", i+1, err)
-		}
-	}
-
-	return nil
+	_, err := parseCron(schedule)
+	return err
 }
 
 // ParseBranchMapping parses a branch mapping string in the format "source:target"
@@ -172,45 +175,3 @@ func ParseBranchMapping(mapping string) (source, target string, err error) {
 
 	return source, target, nil
 }
-
-// validateCronField validates a single field in a cron expression
-func validateCronField(field string, position int) error {
-	// Define patterns for each field
-	patterns := []struct {
-		pattern string
-		ranges  []int
-	}{
-		{`^(\*|[0-9]|[1-5][0-9])$`, []int{0, 59}},                        // Minutes
-		{`^(\*|[0-9]|1[0-9]|2[0-3])$`, []int{0, 23}},                    // Hours
-		{`^(\*|[1-9]|[12][0-9]|3[01])$`, []int{1, 31}},                  // Day of month
-		{`^(\*|[1-9]|1[0-2])$`, []int{1, 12}},                           // Month
-		{`^(\*|[0-6])$`, []int{0, 6}},                                    // Day of week
-	}
-
-	if position < 0 || position >= len(patterns) {
-		return fmt.Errorf("invalid field position")
-	}
-
-	pattern := patterns[position]
-	if field == "*" {
-		return nil
-	}
-
-	// Handle lists and ranges
-	for _, part := range strings.Split(field, ",") {
-		if strings.Contains(part, "-") {
-			rangeParts := strings.Split(part, "-")
-			if len(rangeParts) != 2 {
-				return fmt.Errorf("invalid range format")
-			}
-			if !regexp.MustCompile(pattern.pattern).MatchString(rangeParts[0]) ||
-				!regexp.MustCompile(pattern.pattern).MatchString(rangeParts[1]) {
-				return fmt.Errorf("invalid range values")
-			}
-		} else if !regexp.MustCompile(pattern.pattern).MatchString(part) {
-			return fmt.Errorf("invalid value")
-		}
-	}
-
-	return nil
-}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -156,6 +157,46 @@ func TestSaveConfig(t *testing.T) {
 	assert.Equal(t, cfg.ErrorHandling.RetryDelay, savedCfg.ErrorHandling.RetryDelay)
 }
 
+func TestSaveConfigAtomic(t *testing.T) {
+	defer func(orig func(*os.File, []byte) error) { writeTemp = orig }(writeTemp)
+
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.json")
+	original := []byte(`{"source_repo": "owner/source"}`)
+	assert.NoError(t, os.WriteFile(configPath, original, 0644))
+
+	writeTemp = func(f *os.File, data []byte) error {
+		// Simulate a write interrupted partway through
+		f.Write(data[:len(data)/2])
+		return fmt.Errorf("disk full")
+	}
+
+	err := SaveConfig(&SyncConfig{SourceRepo: "owner/other", TargetRepo: "owner/target"}, configPath)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "disk full")
+
+	data, err := os.ReadFile(configPath)
+	assert.NoError(t, err)
+	assert.Equal(t, original, data)
+
+	entries, err := os.ReadDir(tempDir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary file should be removed after a failed write")
+}
+
+func TestSaveConfigPreservesMode(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	assert.NoError(t, os.WriteFile(configPath, []byte(`{}`), 0600))
+	assert.NoError(t, os.Chmod(configPath, 0600))
+
+	err := SaveConfig(&SyncConfig{SourceRepo: "owner/source", TargetRepo: "owner/target"}, configPath)
+	assert.NoError(t, err)
+
+	info, err := os.Stat(configPath)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
 func TestValidate(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -250,6 +291,51 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestValidateNotifyEmail(t *testing.T) {
+	tests := []struct {
+		name    string
+		notify  bool
+		email   string
+		webhook string
+		wantErr bool
+		wantMsg string
+	}{
+		{name: "valid address", notify: true, email: "ops@example.com"},
+		{name: "invalid address", notify: true, email: "ops@@example", wantErr: true},
+		{name: "missing domain", notify: true, email: "ops", wantErr: true},
+		{name: "notifications disabled", notify: false, email: "not an email"},
+		{name: "webhook only", notify: true, webhook: "https://hooks.example.com/T000/B000"},
+		{name: "invalid webhook", notify: true, webhook: "ftp://hooks.example.com", wantErr: true, wantMsg: "invalid webhook URL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &SyncConfig{
+				SourceRepo: "owner/source",
+				TargetRepo: "owner/target",
+				Schedule:   "0 0 * * *",
+				ErrorHandling: ErrorConfig{
+					Notify:      tt.notify,
+					NotifyEmail: tt.email,
+					WebhookURL:  tt.webhook,
+				},
+			}
+
+			err := cfg.Validate()
+			if tt.wantErr {
+				wantMsg := tt.wantMsg
+				if wantMsg == "" {
+					wantMsg = "invalid notify email"
+				}
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), wantMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestMergeDefaults(t *testing.T) {
 	cfg := &SyncConfig{
 		SourceRepo: "owner/source",
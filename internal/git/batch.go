@@ -0,0 +1,57 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/NicabarNimble/go-gittools/internal/errors"
+)
+
+// CloneBatch clones each entry of opts with at most concurrency clones
+// running at once. The returned slice holds the result for opts[i] at
+// index i. Once ctx is cancelled no new clones are started and the
+// remaining entries report the cancellation. Entries without their own
+// Context use ctx, and without their own OperationName are labelled with
+// their repository and position in the batch.
+func CloneBatch(ctx context.Context, opts []CloneOptions, concurrency int) []error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]error, len(opts))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i := range opts {
+		// Wait for a free slot unless the batch has been cancelled
+		select {
+		case <-ctx.Done():
+		case sem <- struct{}{}:
+		}
+		if ctx.Err() != nil {
+			for j := i; j < len(opts); j++ {
+				results[j] = errors.New("clone", fmt.Errorf("operation cancelled: %w", ctx.Err()))
+			}
+			break
+		}
+
+		cloneOpts := opts[i]
+		if cloneOpts.Context == nil {
+			cloneOpts.Context = ctx
+		}
+		if cloneOpts.OperationName == "" {
+			cloneOpts.OperationName = fmt.Sprintf("Clone %s (%d/%d)", repoLabel(cloneOpts.SourceURL), i+1, len(opts))
+		}
+
+		wg.Add(1)
+		go func(i int, cloneOpts CloneOptions) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = CloneRepository(cloneOpts)
+		}(i, cloneOpts)
+	}
+
+	wg.Wait()
+	return results
+}
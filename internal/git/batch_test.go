@@ -0,0 +1,127 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCloneBatchConcurrency(t *testing.T) {
+	originalRunGitCommand := runGitCommand
+	defer func() {
+		runGitCommand = originalRunGitCommand
+	}()
+
+	var running, maxRunning int32
+	runGitCommand = func(dir string, token string, args ...string) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+
+		if args[len(args)-1] == "repo-3" {
+			return fmt.Errorf("clone failed")
+		}
+		return nil
+	}
+
+	var opts []CloneOptions
+	for i := 0; i < 10; i++ {
+		opts = append(opts, CloneOptions{
+			SourceURL:  fmt.Sprintf("https://github.com/test/repo-%d.git", i),
+			WorkingDir: fmt.Sprintf("repo-%d", i),
+		})
+	}
+
+	const concurrency = 3
+	results := CloneBatch(context.Background(), opts, concurrency)
+
+	if len(results) != len(opts) {
+		t.Fatalf("got %d results, want %d", len(results), len(opts))
+	}
+	if got := atomic.LoadInt32(&maxRunning); got > concurrency {
+		t.Errorf("max concurrent clones = %d, want at most %d", got, concurrency)
+	}
+	for i, err := range results {
+		if (err != nil) != (i == 3) {
+			t.Errorf("result %d = %v, want error only for repo-3", i, err)
+		}
+	}
+}
+
+func TestCloneBatchCancellation(t *testing.T) {
+	originalRunGitCommand := runGitCommand
+	defer func() {
+		runGitCommand = originalRunGitCommand
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var started int32
+	runGitCommand = func(dir string, token string, args ...string) error {
+		atomic.AddInt32(&started, 1)
+		cancel()
+		return nil
+	}
+
+	opts := make([]CloneOptions, 5)
+	for i := range opts {
+		opts[i] = CloneOptions{
+			SourceURL:  "https://github.com/test/repo.git",
+			WorkingDir: fmt.Sprintf("repo-%d", i),
+		}
+	}
+
+	results := CloneBatch(ctx, opts, 1)
+
+	if got := atomic.LoadInt32(&started); got != 1 {
+		t.Errorf("started %d clones, want 1 before cancellation", got)
+	}
+	for i := 1; i < len(results); i++ {
+		if results[i] == nil {
+			t.Errorf("result %d: expected cancellation error, got nil", i)
+		}
+	}
+}
+
+func TestCloneBatchOperationNames(t *testing.T) {
+	originalRunGitCommand := runGitCommand
+	defer func() {
+		runGitCommand = originalRunGitCommand
+	}()
+	runGitCommand = func(dir string, token string, args ...string) error {
+		return nil
+	}
+
+	var opts []CloneOptions
+	var trackers []*mockProgressTracker
+	for i := 0; i < 3; i++ {
+		tracker := &mockProgressTracker{}
+		trackers = append(trackers, tracker)
+		opts = append(opts, CloneOptions{
+			SourceURL:  fmt.Sprintf("https://github.com/test/repo-%d.git", i),
+			WorkingDir: fmt.Sprintf("repo-%d", i),
+			Progress:   tracker,
+		})
+	}
+	opts[2].OperationName = "Custom"
+
+	for i, err := range CloneBatch(context.Background(), opts, 2) {
+		if err != nil {
+			t.Fatalf("clone %d: unexpected error: %v", i, err)
+		}
+	}
+
+	want := []string{"Clone test/repo-0 (1/3)", "Clone test/repo-1 (2/3)", "Custom"}
+	for i, tracker := range trackers {
+		if tracker.operation == nil || tracker.operation.Name != want[i] {
+			t.Errorf("clone %d started %+v, want %q", i, tracker.operation, want[i])
+		}
+	}
+}
@@ -1,11 +1,18 @@
 package git
 
 import (
+	"bytes"
 	"context"
+	stderrors "errors"
 	"fmt"
+	"io"
+	"log"
 	"net/url"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -22,6 +29,38 @@ const (
 // ErrInvalidOptions indicates that the provided clone options are invalid
 var ErrInvalidOptions = errors.New("clone", fmt.Errorf("invalid clone options"))
 
+// ErrRepositoryArchived indicates that the target rejected a push because the
+// repository is archived and therefore read-only
+var ErrRepositoryArchived = stderrors.New("target repository is archived and read-only")
+
+// ErrPushLeaseRejected indicates that a --force-with-lease push was refused
+// because the target no longer matches the expected refs. Callers can
+// inspect the target and retry.
+var ErrPushLeaseRejected = stderrors.New("target changed since its refs were recorded")
+
+// ErrEmptyRepository indicates that the source repository has no commits,
+// so there is nothing to push to the target
+var ErrEmptyRepository = stderrors.New("source repository is empty")
+
+// ErrVerificationFailed indicates that refs on the target do not match the
+// source after pushing, e.g. because a push was only partially applied
+var ErrVerificationFailed = stderrors.New("target refs do not match the source")
+
+// ErrTransferStalled indicates that a clone, fetch or push kept failing
+// because the transfer fell below the low-speed limit
+var ErrTransferStalled = stderrors.New("git transfer stalled")
+
+// ErrInsufficientDiskSpace indicates that the clone destination does not have
+// enough free space for the estimated repository size
+var ErrInsufficientDiskSpace = stderrors.New("insufficient disk space")
+
+// errDiskSpaceUnsupported is returned where free space cannot be determined
+var errDiskSpaceUnsupported = stderrors.New("disk space check not supported on this platform")
+
+// diskSpaceFactor accounts for the checked-out working tree on top of the
+// packed repository size
+const diskSpaceFactor = 2
+
 // CloneOptions contains configuration for repository cloning
 type CloneOptions struct {
 	SourceURL  string
@@ -30,10 +69,119 @@ type CloneOptions struct {
 	Token      string          // Token for HTTPS authentication
 	Progress   progress.Tracker
 	Context    context.Context // Context for cancellation/timeout
+
+	// OperationName labels the operation in progress tracking. Defaults to
+	// the operation and repository, e.g. "Clone owner/repo".
+	OperationName string
+
+	// SparsePaths limits the checkout to these directories using cone-mode
+	// sparse checkout. Paths are relative to the repository root.
+	SparsePaths []string
+
+	// TagsOnly pushes only tags to the target instead of all branches
+	TagsOnly bool
+
+	// Refspec overrides the refs pushed to the target (e.g. "refs/heads/main:refs/heads/main")
+	Refspec string
+
+	// PushRef publishes history only up to one commit, given as
+	// "<sha>:refs/heads/<branch>" with the full commit SHA. The branch is
+	// set to that commit instead of the source's branch tip. It cannot be
+	// combined with Refspec or TagsOnly.
+	PushRef string
+
+	// Force overwrites diverged history on the target with --force. Commits
+	// that exist only on the target are lost, so use it only for mirrors the
+	// source fully owns.
+	Force bool
+
+	// ForceWithLease force-pushes with --force-with-lease instead, refusing
+	// to overwrite target refs that no longer point where LeaseRefs says.
+	// Without LeaseRefs there is nothing to compare against, so only refs
+	// missing from the target can be written. Prefer it over Force; a
+	// rejected lease matches ErrPushLeaseRejected.
+	ForceWithLease bool
+
+	// LeaseRefs maps target refs (e.g. "refs/heads/main") to the object
+	// they pointed at when last seen, typically recorded after the previous
+	// push. Each becomes a --force-with-lease=<ref>:<sha> expectation.
+	LeaseRefs map[string]string
+
+	// Prune deletes target refs that no longer exist on the source, so
+	// branches deleted upstream disappear from the mirror. It is destructive:
+	// any branch that exists only on the target is removed.
+	Prune bool
+
+	// GPGSign signs any commit the package creates, using SigningKey if set
+	GPGSign    bool
+	SigningKey string
+
+	// PostClone is called with the clone directory after cloning and before
+	// pushing to TargetURL. Any changes it leaves behind are committed and
+	// pushed; returning an error aborts the push.
+	PostClone func(workingDir string) error
+
+	// TempDir is where the intermediate clone for TargetURL mirroring is
+	// created. Defaults to the system temp directory ($TMPDIR).
+	TempDir string
+
+	// KeepTempOnError keeps the intermediate clone when mirroring fails so its
+	// state can be inspected. The retained path is logged. Credentials are
+	// removed from its remote URLs first; if that fails, it is deleted.
+	KeepTempOnError bool
+
+	// CheckDiskSpace verifies before cloning that the destination filesystem
+	// has room for roughly twice EstimatedSize. It is skipped when
+	// EstimatedSize is unknown (zero).
+	CheckDiskSpace bool
+
+	// EstimatedSize is the expected repository size in bytes, e.g. from
+	// github.Repository.SizeBytes
+	EstimatedSize int64
+
+	// SetDefaultBranch is called after a successful push with the source's
+	// default branch, so the target's default branch can be updated to match
+	// (e.g. with github.Client.UpdateRepository). Pushing alone never changes
+	// the target's HEAD. Returning an error fails the operation.
+	SetDefaultBranch func(branch string) error
+
+	// VerifyPush compares every pushed ref on the target with the local
+	// clone after pushing and fails with ErrVerificationFailed on mismatch
+	VerifyPush bool
+
+	// RateLimitKBps limits each clone or push to about this many KB/s.
+	// Git cannot cap its own throughput, so its HTTP(S) traffic is routed
+	// through a throttling proxy on a loopback port, replacing any
+	// configured http.proxy.
+	RateLimitKBps int
+
+	// proxyURL is the throttling proxy started for RateLimitKBps
+	proxyURL string
+
+	// LowSpeedLimit (bytes per second) and LowSpeedTime abort transfers that
+	// stay slower than the limit for that long, so a dead connection fails
+	// with ErrTransferStalled after retrying instead of hanging. Zero values
+	// use DefaultLowSpeedLimit and DefaultLowSpeedTime.
+	LowSpeedLimit int
+	LowSpeedTime  time.Duration
+
+	// SeedEmptyRepository creates an empty initial commit when the source
+	// has no commits, instead of failing with ErrEmptyRepository
+	SeedEmptyRepository bool
+
+	// ExcludePaths are removed from every commit of the clone, in
+	// WorkingDir or before pushing to TargetURL, so they cannot be
+	// recovered from the result. This rewrites history: commit and tag
+	// IDs change from the first commit that touched an excluded path.
+	// Paths are relative to the repository root.
+	ExcludePaths []string
 }
 
+// tagsRefspec is the refspec used when mirroring tags only
+const tagsRefspec = "refs/tags/*:refs/tags/*"
+
 // CloneRepository clones a source repository to a target location
-func CloneRepository(opts CloneOptions) error {
+func CloneRepository(opts CloneOptions) (err error) {
 	// Set up context with timeout if not provided
 	if opts.Context == nil {
 		var cancel context.CancelFunc
@@ -52,7 +200,7 @@ func CloneRepository(opts CloneOptions) error {
 
 	// Initialize progress tracking
 	if opts.Progress != nil {
-		opts.Progress.Start("Clone Repository")
+		opts.Progress.Start(operationName(opts, "Clone", opts.SourceURL))
 		defer opts.Progress.Complete()
 	}
 
@@ -89,14 +237,74 @@ if !strings.HasPrefix(sourceURL, "file://") {
 	}
 }
 
+	if err := validateRefOptions("clone", opts); err != nil {
+		if opts.Progress != nil {
+			opts.Progress.Error(err)
+		}
+		return err
+	}
+
+	if err := validateRepoPaths("sparse", opts.SparsePaths); err != nil {
+		if opts.Progress != nil {
+			opts.Progress.Error(err)
+		}
+		return err
+	}
+	if err := validateRepoPaths("exclude", opts.ExcludePaths); err != nil {
+		if opts.Progress != nil {
+			opts.Progress.Error(err)
+		}
+		return err
+	}
+
+	if opts.TempDir != "" {
+		if err := validateTempDir(opts.TempDir); err != nil {
+			if opts.Progress != nil {
+				opts.Progress.Error(err)
+			}
+			return err
+		}
+	}
+
+	if opts.CheckDiskSpace {
+		if err := checkDiskSpace(cloneDestination(opts), opts.EstimatedSize); err != nil {
+			if opts.Progress != nil {
+				opts.Progress.Error(err)
+			}
+			return err
+		}
+	}
+
+	if opts.RateLimitKBps > 0 {
+		proxy, err := startThrottleProxy(opts.RateLimitKBps * 1024)
+		if err != nil {
+			err = errors.New("clone", fmt.Errorf("failed to start rate limiting proxy: %w", err))
+			if opts.Progress != nil {
+				opts.Progress.Error(err)
+			}
+			return err
+		}
+		defer proxy.Close()
+		opts.proxyURL = proxy.URL()
+	}
+
 	// If WorkingDir is specified, clone directly to it
 	if opts.WorkingDir != "" {
-		if err := runGitCommand("", opts.Token, "clone", sourceURL, opts.WorkingDir); err != nil {
+		if err := cloneSource("", sourceURL, opts.WorkingDir, opts); err != nil {
 			if opts.Progress != nil {
 				opts.Progress.Error(err)
 			}
 			return errors.New("clone", fmt.Errorf("failed to clone source repository: %w", err))
 		}
+		if len(opts.ExcludePaths) > 0 {
+			if err := removeExcludedPaths(opts.WorkingDir, opts); err != nil {
+				if opts.Progress != nil {
+					opts.Progress.Error(err)
+				}
+				return errors.New("clone", err)
+			}
+		}
+		reportStep(opts, 1, 1)
 		return nil
 	}
 
@@ -110,7 +318,7 @@ if !strings.HasPrefix(sourceURL, "file://") {
 	}
 
 	// Create temporary directory for initial clone with proper cleanup
-	tempDir, err := os.MkdirTemp("", "gitclone-*")
+	tempDir, err := os.MkdirTemp(opts.TempDir, tempDirPattern)
 	if err != nil {
 		if opts.Progress != nil {
 			opts.Progress.Error(err)
@@ -120,98 +328,1056 @@ if !strings.HasPrefix(sourceURL, "file://") {
 	
 	cleanup := true
 	defer func() {
+		if err != nil && opts.KeepTempOnError {
+			// The remote URLs carry the token, which must not outlive the run
+			if scrubErr := scrubRemoteCredentials(tempDir); scrubErr != nil {
+				log.Printf("clone failed, removing working directory because its credentials could not be cleared: %v", scrubErr)
+			} else {
+				cleanup = false
+				log.Printf("clone failed, keeping working directory for debugging: %s", tempDir)
+			}
+		}
 		if cleanup {
-			os.RemoveAll(tempDir)
+			if removeErr := removeTempDir(tempDir); removeErr != nil {
+				if opts.Progress != nil {
+					opts.Progress.Error(removeErr)
+				}
+				err = stderrors.Join(err, removeErr)
+			}
 		}
 	}()
 
 	// Clone source repository
-	if err := runGitCommand(tempDir, opts.Token, "clone", sourceURL, "."); err != nil {
+	if err := cloneSource(tempDir, sourceURL, ".", opts); err != nil {
 		if opts.Progress != nil {
 			opts.Progress.Error(err)
 		}
 		return errors.New("clone", fmt.Errorf("failed to clone source repository: %w", err))
 	}
+	reportStep(opts, 1, mirrorSteps)
+
+	if err := validateTargetURL(opts.TargetURL); err != nil {
+		return err
+	}
+
+	// An empty source has no refs to push
+	if err := ensureCommits(tempDir, opts); err != nil {
+		if opts.Progress != nil {
+			opts.Progress.Error(err)
+		}
+		return errors.New("clone", err)
+	}
+
+	// Run custom processing before anything reaches the target
+	if opts.PostClone != nil {
+		if err := runPostClone(tempDir, opts); err != nil {
+			if opts.Progress != nil {
+				opts.Progress.Error(err)
+			}
+			return errors.New("clone", err)
+		}
+	}
+
+	// Strip excluded paths last so nothing added by the hook slips through
+	if len(opts.ExcludePaths) > 0 {
+		if err := removeExcludedPaths(tempDir, opts); err != nil {
+			if opts.Progress != nil {
+				opts.Progress.Error(err)
+			}
+			return errors.New("clone", err)
+		}
+	}
+
+	if err := pushToTarget(tempDir, "target", opts, 2, mirrorSteps); err != nil {
+		if opts.Progress != nil {
+			opts.Progress.Error(err)
+		}
+		return errors.New("clone", err)
+	}
+
+	return nil
+}
+
+// PushRepository pushes the existing clone in dir to opts.TargetURL. The refs
+// pushed follow opts.TagsOnly, opts.Refspec and opts.PushRef, defaulting to
+// all branches. The target is added as a temporary remote, which is removed
+// again before returning so the token does not stay in dir's git config.
+func PushRepository(dir string, opts CloneOptions) error {
+	if opts.TargetURL == "" {
+		return errors.New("push", fmt.Errorf("target URL must be specified"))
+	}
+	if err := validateRefOptions("push", opts); err != nil {
+		return err
+	}
+	if err := validateTargetURL(opts.TargetURL); err != nil {
+		return err
+	}
+
+	if opts.RateLimitKBps > 0 {
+		proxy, err := startThrottleProxy(opts.RateLimitKBps * 1024)
+		if err != nil {
+			return errors.New("push", fmt.Errorf("failed to start rate limiting proxy: %w", err))
+		}
+		defer proxy.Close()
+		opts.proxyURL = proxy.URL()
+	}
+
+	if opts.Progress != nil {
+		opts.Progress.Start(operationName(opts, "Push", opts.TargetURL))
+		defer opts.Progress.Complete()
+	}
+
+	remote := fmt.Sprintf("gittools-push-%d", time.Now().UnixNano())
+	err := pushToTarget(dir, remote, opts, 1, 2)
+	if removeErr := runGitCommand(dir, "", "remote", "remove", remote); removeErr != nil && err == nil {
+		err = fmt.Errorf("failed to remove temporary remote %s: %w", remote, removeErr)
+	}
+	if err != nil {
+		if opts.Progress != nil {
+			opts.Progress.Error(err)
+		}
+		return errors.New("push", err)
+	}
+	return nil
+}
+
+// validateRefOptions rejects conflicting or malformed options selecting
+// the refs to push, reporting them as failures of op
+func validateRefOptions(op string, opts CloneOptions) error {
+	if opts.TagsOnly && opts.Refspec != "" {
+		return errors.New(op, fmt.Errorf("TagsOnly and Refspec cannot both be set"))
+	}
+	if opts.PushRef == "" {
+		return nil
+	}
+	if opts.TagsOnly || opts.Refspec != "" {
+		return errors.New(op, fmt.Errorf("PushRef cannot be combined with TagsOnly or Refspec"))
+	}
+	if err := validatePushRef(opts.PushRef); err != nil {
+		return errors.New(op, err)
+	}
+	return nil
+}
+
+// fullSHAPattern matches a full SHA-1 or SHA-256 object name
+var fullSHAPattern = regexp.MustCompile(`^([0-9a-f]{40}|[0-9a-f]{64})$`)
+
+// validatePushRef checks that pushRef is "<sha>:refs/heads/<branch>" with a
+// full SHA, which unlike an abbreviated one cannot become ambiguous, and a
+// branch name git accepts
+func validatePushRef(pushRef string) error {
+	sha, ref, ok := strings.Cut(pushRef, ":")
+	if !ok {
+		return fmt.Errorf("invalid PushRef %q: want <sha>:refs/heads/<branch>", pushRef)
+	}
+	if !fullSHAPattern.MatchString(sha) {
+		return fmt.Errorf("invalid PushRef %q: %q is not a full lowercase commit SHA", pushRef, sha)
+	}
+	branch, ok := strings.CutPrefix(ref, "refs/heads/")
+	if !ok || !validBranchName(branch) {
+		return fmt.Errorf("invalid PushRef %q: %q is not a valid branch ref", pushRef, ref)
+	}
+	return nil
+}
+
+// validBranchName applies git's check-ref-format rules to a branch name
+func validBranchName(name string) bool {
+	if name == "" || name == "@" || strings.HasPrefix(name, "-") ||
+		strings.HasSuffix(name, "/") || strings.HasSuffix(name, ".") ||
+		strings.Contains(name, "..") || strings.Contains(name, "//") ||
+		strings.Contains(name, "@{") || strings.ContainsAny(name, " ~^:?*[\\") {
+		return false
+	}
+	for _, c := range name {
+		if c < 0x20 || c == 0x7f {
+			return false
+		}
+	}
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") || strings.HasSuffix(part, ".lock") {
+			return false
+		}
+	}
+	return true
+}
 
-// Parse and validate target URL if specified
-targetURL := opts.TargetURL
-if targetURL != "" {
+// validateTargetURL rejects SSH and otherwise invalid target URLs. Empty
+// and file:// URLs (used in tests) are accepted.
+func validateTargetURL(targetURL string) error {
+	if targetURL == "" || strings.HasPrefix(targetURL, "file://") {
+		return nil
+	}
 	if strings.HasPrefix(targetURL, "git@") {
 		return errors.New("clone", fmt.Errorf("SSH URLs are not supported, please use HTTPS"))
 	}
-	
-	// Skip URL validation for file:// URLs (used in tests)
-	if !strings.HasPrefix(targetURL, "file://") {
-		// Validate the HTTPS URL
-		if err := urlutils.ValidateURL(targetURL); err != nil {
-			return errors.New("clone", fmt.Errorf("invalid target URL: %w", err))
+	if err := urlutils.ValidateURL(targetURL); err != nil {
+		return errors.New("clone", fmt.Errorf("invalid target URL: %w", err))
+	}
+	return nil
+}
+
+// mirrorSteps counts the progress steps of mirroring to a target: cloned,
+// target remote added and pushed
+const mirrorSteps = 3
+
+// reportStep reports that step of total workflow steps has finished
+func reportStep(opts CloneOptions, step, total int64) {
+	if opts.Progress != nil {
+		opts.Progress.Update(step, total)
+	}
+}
+
+// pushToTarget adds opts.TargetURL as remote of dir and pushes to it,
+// reporting steps step (remote added) and step+1 (pushed) of total
+func pushToTarget(dir, remote string, opts CloneOptions, step, total int64) error {
+	if err := runGitCommand(dir, opts.Token, "remote", "add", remote, opts.TargetURL); err != nil {
+		return fmt.Errorf("failed to add target remote: %w", err)
+	}
+	reportStep(opts, step, total)
+	var localBranches []string
+	if opts.Prune && defaultRefspec(opts) {
+		// origin/HEAD would otherwise be pushed as a branch named HEAD
+		if err := runGitCommand(dir, opts.Token, "update-ref", "-d", "--no-deref", "refs/remotes/origin/HEAD"); err != nil {
+			return fmt.Errorf("failed to remove origin/HEAD: %w", err)
+		}
+		branches, err := gitLocalBranches(dir)
+		if err != nil {
+			return fmt.Errorf("failed to list local branches: %w", err)
+		}
+		localBranches = branches
+	}
+	if err := runGitCommand(dir, opts.Token, pushArgs(opts, remote, localBranches)...); err != nil {
+		return fmt.Errorf("failed to push to target repository: %w", err)
+	}
+	reportStep(opts, step+1, total)
+
+	if opts.VerifyPush {
+		if err := verifyPush(dir, remote, opts, localBranches); err != nil {
+			return err
+		}
+	}
+
+	if opts.SetDefaultBranch != nil {
+		branch, err := gitHeadBranch(dir)
+		if err != nil {
+			return fmt.Errorf("failed to read default branch: %w", err)
+		}
+		if err := opts.SetDefaultBranch(branch); err != nil {
+			return fmt.Errorf("failed to set default branch to %s: %w", branch, err)
+		}
+	}
+	return nil
+}
+
+// verifyPush checks that the target's refs match the local refs they were
+// pushed from, naming every ref that is missing or differs
+func verifyPush(dir, remote string, opts CloneOptions, localBranches []string) error {
+	local, err := gitRefs(dir, ".")
+	if err != nil {
+		return fmt.Errorf("failed to list local refs: %w", err)
+	}
+	remoteRefs, err := gitRefs(dir, remote)
+	if err != nil {
+		return fmt.Errorf("failed to list target refs: %w", err)
+	}
+
+	var mismatched []string
+	for ref, hash := range pushedRefs(local, opts, localBranches) {
+		if remoteRefs[ref] != hash {
+			mismatched = append(mismatched, ref)
+		}
+	}
+	if len(mismatched) > 0 {
+		sort.Strings(mismatched)
+		return fmt.Errorf("%w: %s", ErrVerificationFailed, strings.Join(mismatched, ", "))
+	}
+	return nil
+}
+
+// pushedRefs maps the target refs a push with opts should have updated to
+// the local object they should point at
+func pushedRefs(local map[string]string, opts CloneOptions, localBranches []string) map[string]string {
+	if opts.PushRef != "" {
+		sha, ref, _ := strings.Cut(opts.PushRef, ":")
+		return map[string]string{ref: sha}
+	}
+
+	var refspecs []string
+	switch {
+	case opts.Refspec != "":
+		refspecs = []string{opts.Refspec}
+	case opts.TagsOnly:
+		refspecs = []string{tagsRefspec}
+	case opts.Prune:
+		refspecs = pruneRefspecs(localBranches)
+	default:
+		refspecs = []string{"refs/heads/*:refs/heads/*"}
+	}
+
+	excluded := make(map[string]bool)
+	for _, spec := range refspecs {
+		if strings.HasPrefix(spec, "^") {
+			excluded[spec[1:]] = true
+		}
+	}
+
+	pushed := make(map[string]string)
+	for _, spec := range refspecs {
+		if strings.HasPrefix(spec, "^") {
+			continue
+		}
+		src, dst, found := strings.Cut(strings.TrimPrefix(spec, "+"), ":")
+		if !found {
+			dst = src
+		}
+		for ref, hash := range local {
+			if excluded[ref] || strings.HasSuffix(ref, "/HEAD") {
+				continue
+			}
+			if target, ok := mapRef(ref, src, dst); ok {
+				pushed[target] = hash
+			}
+		}
+	}
+	return pushed
+}
+
+// mapRef returns the destination of ref under the refspec src:dst. Short
+// names are taken to be branches; a single "*" in src matches any suffix.
+func mapRef(ref, src, dst string) (string, bool) {
+	if !strings.HasPrefix(src, "refs/") {
+		src = "refs/heads/" + src
+	}
+	if !strings.HasPrefix(dst, "refs/") {
+		dst = "refs/heads/" + dst
+	}
+
+	prefix, suffix, wildcard := strings.Cut(src, "*")
+	if !wildcard {
+		return dst, ref == src
+	}
+	if !strings.HasPrefix(ref, prefix) || !strings.HasSuffix(ref, suffix) || len(ref) < len(prefix)+len(suffix) {
+		return "", false
+	}
+	match := ref[len(prefix) : len(ref)-len(suffix)]
+	return strings.Replace(dst, "*", match, 1), true
+}
+
+// gitRefs lists the refs of remote as seen from dir, mapping each ref name
+// to the object it points at. "." lists the local repository. It is a
+// variable so it can be mocked in tests.
+var gitRefs = func(dir, remote string) (map[string]string, error) {
+	cmd := exec.Command("git", "ls-remote", remote)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.New("git-command", fmt.Errorf("git ls-remote failed: %w", err))
+	}
+	return parseLsRemote(out), nil
+}
+
+// parseLsRemote maps each ref in git ls-remote output to its object
+func parseLsRemote(out []byte) map[string]string {
+	refs := make(map[string]string)
+	for _, line := range strings.Split(string(out), "\n") {
+		hash, ref, ok := strings.Cut(line, "\t")
+		// Skip peeled tags; annotated tags are compared by tag object
+		if !ok || strings.HasSuffix(ref, "^{}") {
+			continue
+		}
+		refs[ref] = hash
+	}
+	return refs
+}
+
+// gitHasCommits reports whether HEAD in dir points at a commit. The SHA
+// printed by rev-parse is captured rather than shown. It is a variable so
+// it can be mocked in tests.
+var gitHasCommits = func(dir string) bool {
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", "HEAD")
+	cmd.Dir = dir
+	_, err := cmd.Output()
+	return err == nil
+}
+
+// scrubRemoteCredentials removes the user info, such as an injected token,
+// from the URL of every remote of the repository in dir. A clone that
+// failed before creating the repository has nothing to clear.
+func scrubRemoteCredentials(dir string) error {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); os.IsNotExist(err) {
+		return nil
+	}
+	cmd := exec.Command("git", "remote")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to list remotes: %w", err)
+	}
+	for _, remote := range strings.Fields(string(out)) {
+		cmd := exec.Command("git", "remote", "get-url", remote)
+		cmd.Dir = dir
+		rawURL, err := cmd.Output()
+		if err != nil {
+			return fmt.Errorf("failed to read URL of remote %s: %w", remote, err)
+		}
+		u, err := url.Parse(strings.TrimSpace(string(rawURL)))
+		if err != nil || u.User == nil {
+			continue
+		}
+		u.User = nil
+		cmd = exec.Command("git", "remote", "set-url", remote, u.String())
+		cmd.Dir = dir
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to reset URL of remote %s: %w", remote, err)
+		}
+	}
+	return nil
+}
+
+// gitHeadBranch returns the branch HEAD points at in dir, which for a fresh
+// clone is the source's default branch. It is a variable so it can be
+// mocked in tests.
+var gitHeadBranch = func(dir string) (string, error) {
+	cmd := exec.Command("git", "symbolic-ref", "--short", "HEAD")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", errors.New("git-command", fmt.Errorf("git symbolic-ref failed: %w", err))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// tempDirPattern names the intermediate clones created for mirroring
+const tempDirPattern = "gitclone-*"
+
+// removeAll is a variable so it can be mocked in tests
+var removeAll = os.RemoveAll
+
+// removeTempDir deletes an intermediate clone, logging any failure so
+// leaked directories do not go unnoticed
+func removeTempDir(dir string) error {
+	if err := removeAll(dir); err != nil {
+		log.Printf("failed to remove temporary directory %s: %v", dir, err)
+		return errors.New("cleanup", fmt.Errorf("failed to remove temporary directory: %w", err))
+	}
+	return nil
+}
+
+// CleanupTempDirs removes intermediate clones in dir (the system temp
+// directory if empty) last modified more than olderThan ago, such as those
+// left behind by interrupted runs or KeepTempOnError. Failures to remove
+// individual directories are joined into the returned error.
+func CleanupTempDirs(dir string, olderThan time.Duration) error {
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	matches, err := filepath.Glob(filepath.Join(dir, tempDirPattern))
+	if err != nil {
+		return errors.New("cleanup", fmt.Errorf("failed to list temporary directories: %w", err))
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	var errs []error
+	for _, path := range matches {
+		info, err := os.Stat(path)
+		if err != nil || !info.IsDir() || info.ModTime().After(cutoff) {
+			continue
+		}
+		if err := removeTempDir(path); err != nil {
+			errs = append(errs, err)
 		}
 	}
+	return stderrors.Join(errs...)
+}
+
+// operationName returns opts.OperationName, or verb followed by the
+// repository at location
+func operationName(opts CloneOptions, verb, location string) string {
+	if opts.OperationName != "" {
+		return opts.OperationName
+	}
+	return verb + " " + repoLabel(location)
+}
+
+// repoLabel shortens a repository URL or path to its last two path
+// segments, e.g. "owner/repo", for display
+func repoLabel(location string) string {
+	path := location
+	if u, err := url.Parse(location); err == nil && u.Path != "" {
+		path = u.Path
+	}
+	path = strings.TrimSuffix(strings.Trim(filepath.ToSlash(path), "/"), ".git")
+
+	parts := strings.Split(path, "/")
+	if len(parts) > 2 {
+		parts = parts[len(parts)-2:]
+	}
+	if label := strings.Join(parts, "/"); label != "" {
+		return label
+	}
+	return location
 }
 
-	// Add target remote
-	if err := runGitCommand(tempDir, opts.Token, "remote", "add", "target", targetURL); err != nil {
+// cloneDestination returns an existing directory on the filesystem the clone
+// will be written to
+func cloneDestination(opts CloneOptions) string {
+	if opts.WorkingDir == "" {
+		if opts.TempDir != "" {
+			return opts.TempDir
+		}
+		return os.TempDir()
+	}
+	dir := opts.WorkingDir
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "."
+		}
+		dir = parent
+	}
+}
+
+// validateTempDir checks that dir is an existing, writable directory
+func validateTempDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return errors.New("clone", fmt.Errorf("invalid temp directory: %w", err))
+	}
+	if !info.IsDir() {
+		return errors.New("clone", fmt.Errorf("invalid temp directory: %s is not a directory", dir))
+	}
+
+	f, err := os.CreateTemp(dir, ".gitclone-write-*")
+	if err != nil {
+		return errors.New("clone", fmt.Errorf("temp directory %s is not writable: %w", dir, err))
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return nil
+}
+
+// checkDiskSpace fails with ErrInsufficientDiskSpace if dir's filesystem
+// cannot hold a repository of estimatedSize bytes
+func checkDiskSpace(dir string, estimatedSize int64) error {
+	if estimatedSize <= 0 {
+		return nil
+	}
+
+	available, err := freeDiskSpace(dir)
+	if err != nil {
+		if stderrors.Is(err, errDiskSpaceUnsupported) {
+			return nil
+		}
+		return errors.New("clone", fmt.Errorf("failed to check free disk space: %w", err))
+	}
+
+	required := uint64(estimatedSize) * diskSpaceFactor
+	if available < required {
+		return errors.New("clone", fmt.Errorf("%w: need about %d MB in %s, %d MB available",
+			ErrInsufficientDiskSpace, required>>20, dir, available>>20))
+	}
+	return nil
+}
+
+// freeDiskSpace is a variable so it can be mocked in tests
+var freeDiskSpace = availableDiskSpace
+
+// UpdateRepository brings an existing clone in opts.WorkingDir up to date by
+// fetching all remotes and fast-forwarding the current branch. If WorkingDir
+// is not a git repository yet, it falls back to a full CloneRepository.
+func UpdateRepository(opts CloneOptions) error {
+	if opts.WorkingDir == "" {
+		return errors.New("update", fmt.Errorf("working directory must be specified"))
+	}
+
+	if !isGitRepository(opts.WorkingDir) {
+		return CloneRepository(opts)
+	}
+
+	if opts.Progress != nil {
+		opts.Progress.Start(operationName(opts, "Update", opts.WorkingDir))
+		defer opts.Progress.Complete()
+	}
+
+	if err := runGitCommand(opts.WorkingDir, opts.Token, networkArgs(opts, "fetch", "--all", "--prune")...); err != nil {
 		if opts.Progress != nil {
 			opts.Progress.Error(err)
 		}
-		return errors.New("clone", fmt.Errorf("failed to add target remote: %w", err))
+		return errors.New("update", fmt.Errorf("failed to fetch updates: %w", err))
 	}
 
-	// Push to target repository
-	if err := runGitCommand(tempDir, opts.Token, "push", "target", "--all"); err != nil {
+	if err := runGitCommand(opts.WorkingDir, opts.Token, "merge", "--ff-only", "@{upstream}"); err != nil {
 		if opts.Progress != nil {
 			opts.Progress.Error(err)
 		}
-		return errors.New("clone", fmt.Errorf("failed to push to target repository: %w", err))
+		return errors.New("update", fmt.Errorf("failed to fast-forward: %w", err))
 	}
 
 	return nil
 }
 
-// runGitCommand is a variable so it can be mocked in tests
-var runGitCommand = func(dir string, token string, args ...string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-	defer cancel()
+// isGitRepository reports whether dir contains a git repository
+func isGitRepository(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// ensureCommits fails with ErrEmptyRepository if the clone in dir has no
+// HEAD commit, or seeds one when opts.SeedEmptyRepository is set
+func ensureCommits(dir string, opts CloneOptions) error {
+	if gitHasCommits(dir) {
+		return nil
+	}
+	if !opts.SeedEmptyRepository {
+		return ErrEmptyRepository
+	}
 
-	cmd := exec.CommandContext(ctx, "git", args...)
+	commitArgs := append(SigningArgs(opts.GPGSign, opts.SigningKey), "commit", "--allow-empty", "-m", "Initial commit")
+	if err := runGitCommand(dir, opts.Token, commitArgs...); err != nil {
+		return fmt.Errorf("failed to seed empty repository: %w", err)
+	}
+	return nil
+}
+
+// runPostClone invokes the PostClone hook in dir and commits whatever it changed
+func runPostClone(dir string, opts CloneOptions) error {
+	if err := opts.PostClone(dir); err != nil {
+		return fmt.Errorf("post-clone hook failed: %w", err)
+	}
+	return commitChanges(dir, opts, "Apply post-clone changes")
+}
+
+// commitChanges stages all changes in dir and commits them with message.
+// Nothing is committed when the working tree is clean.
+func commitChanges(dir string, opts CloneOptions, message string) error {
+	changed, err := gitHasChanges(dir)
+	if err != nil {
+		return fmt.Errorf("failed to check for changes: %w", err)
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := runGitCommand(dir, opts.Token, "add", "-A"); err != nil {
+		return fmt.Errorf("failed to stage changes: %w", err)
+	}
+	commitArgs := append(SigningArgs(opts.GPGSign, opts.SigningKey), "commit", "-m", message)
+	if err := runGitCommand(dir, opts.Token, commitArgs...); err != nil {
+		return fmt.Errorf("failed to commit changes: %w", err)
+	}
+	return nil
+}
+
+// removeExcludedPaths rewrites every commit reachable from the refs in
+// dir without opts.ExcludePaths, so the excluded files cannot be recovered
+// from any pushed commit
+func removeExcludedPaths(dir string, opts CloneOptions) error {
+	files, err := gitHistoryFiles(dir, opts.ExcludePaths)
+	if err != nil {
+		return fmt.Errorf("failed to list excluded files: %w", err)
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	if err := gitFilterPaths(dir, opts.ExcludePaths); err != nil {
+		return fmt.Errorf("failed to remove excluded paths from history: %w", err)
+	}
+	for _, f := range files {
+		log.Printf("excluded %s from mirror", f)
+	}
+	return nil
+}
+
+// gitHistoryFiles lists the files under paths that appear in any commit
+// reachable from the refs in dir. It is a variable so it can be mocked in
+// tests.
+var gitHistoryFiles = func(dir string, paths []string) ([]string, error) {
+	args := append([]string{"log", "--all", "--format=", "--name-only", "--"}, paths...)
+	cmd := exec.Command("git", args...)
 	cmd.Dir = dir
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	// Handle HTTPS with token for clone and push commands with retries for rate limits
-	if len(args) > 0 && (args[0] == "clone" || args[0] == "push") && len(args) > 1 && token != "" {
-		rawURL := args[1]
-		if strings.HasPrefix(rawURL, "https://") {
-			parsedURL, err := url.Parse(rawURL)
-			if err != nil {
-				return errors.New("git-command", fmt.Errorf("invalid URL format: %w", err))
-			}
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.New("git-command", fmt.Errorf("git log failed: %w", err))
+	}
+	seen := make(map[string]bool)
+	var files []string
+	for _, f := range strings.Split(string(out), "\n") {
+		if f != "" && !seen[f] {
+			seen[f] = true
+			files = append(files, f)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// gitFilterPaths removes paths from every commit reachable from the refs in
+// dir, rewriting branches, remote-tracking branches and tags, and drops the
+// backup refs that would keep the old commits. It is a variable so it can
+// be mocked in tests.
+var gitFilterPaths = func(dir string, paths []string) error {
+	quoted := make([]string, len(paths))
+	for i, p := range paths {
+		quoted[i] = "'" + strings.ReplaceAll(p, "'", `'\''`) + "'"
+	}
+	indexFilter := "git rm -r --cached --ignore-unmatch -q -- " + strings.Join(quoted, " ")
+	cmd := exec.Command("git", "filter-branch", "--force", "--prune-empty",
+		"--index-filter", indexFilter, "--tag-name-filter", "cat", "--", "--all")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "FILTER_BRANCH_SQUELCH_WARNING=1")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.New("git-command", fmt.Errorf("git filter-branch failed: %w: %s", err, strings.TrimSpace(string(out))))
+	}
+
+	cmd = exec.Command("git", "for-each-ref", "--format=%(refname)", "refs/original/")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return errors.New("git-command", fmt.Errorf("git for-each-ref failed: %w", err))
+	}
+	for _, ref := range strings.Fields(string(out)) {
+		cmd := exec.Command("git", "update-ref", "-d", ref)
+		cmd.Dir = dir
+		if err := cmd.Run(); err != nil {
+			return errors.New("git-command", fmt.Errorf("failed to delete backup ref %s: %w", ref, err))
+		}
+	}
+	return nil
+}
+
+// gitHasChanges reports whether the working tree in dir has uncommitted
+// changes. It is a variable so it can be mocked in tests.
+var gitHasChanges = func(dir string) (bool, error) {
+	cmd := exec.Command("git", "status", "--porcelain")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return false, errors.New("git-command", fmt.Errorf("git status failed: %w", err))
+	}
+	return len(strings.TrimSpace(string(out))) > 0, nil
+}
+
+const (
+	// DefaultLowSpeedLimit and DefaultLowSpeedTime abort HTTP transfers that
+	// stay below DefaultLowSpeedLimit bytes per second for DefaultLowSpeedTime
+	DefaultLowSpeedLimit = 1000
+	DefaultLowSpeedTime  = 60 * time.Second
+)
+
+// ThrottleArgs returns the git config flags that size the HTTP post buffer
+// to one second of transfer at kbps, so pushes over a throttled link are
+// sent in steady requests. It does not limit the rate by itself. It
+// returns nil when kbps is not positive.
+func ThrottleArgs(kbps int) []string {
+	if kbps <= 0 {
+		return nil
+	}
+	return []string{"-c", fmt.Sprintf("http.postBuffer=%d", kbps*1024)}
+}
+
+// StallArgs returns the git config flags that abort transfers slower than
+// limit bytes per second for d. Zero values use the defaults.
+func StallArgs(limit int, d time.Duration) []string {
+	if limit <= 0 {
+		limit = DefaultLowSpeedLimit
+	}
+	if d <= 0 {
+		d = DefaultLowSpeedTime
+	}
+	return []string{
+		"-c", fmt.Sprintf("http.lowSpeedLimit=%d", limit),
+		"-c", fmt.Sprintf("http.lowSpeedTime=%d", int(d.Seconds())),
+	}
+}
+
+// networkArgs prefixes a network git command with opts' throttle and stall
+// flags. Without either, runGitCommand applies the default stall limits.
+func networkArgs(opts CloneOptions, args ...string) []string {
+	prefix := ThrottleArgs(opts.RateLimitKBps)
+	if opts.proxyURL != "" {
+		prefix = append(prefix, "-c", "http.proxy="+opts.proxyURL)
+	}
+	if opts.RateLimitKBps > 0 || opts.LowSpeedLimit > 0 || opts.LowSpeedTime > 0 {
+		prefix = append(prefix, StallArgs(opts.LowSpeedLimit, opts.LowSpeedTime)...)
+	}
+	return append(prefix, args...)
+}
+
+// SigningArgs returns the git config flags that enable commit signing.
+// They must be placed before the git subcommand, e.g.
+// append(SigningArgs(true, key), "commit", "-m", msg).
+// It returns nil when signing is disabled.
+func SigningArgs(gpgSign bool, signingKey string) []string {
+	if !gpgSign {
+		return nil
+	}
+	args := []string{"-c", "commit.gpgsign=true"}
+	if signingKey != "" {
+		args = append(args, "-c", "user.signingkey="+signingKey)
+	}
+	return args
+}
+
+// defaultRefspec reports whether opts leave the refs to push unspecified,
+// so all branches are pushed
+func defaultRefspec(opts CloneOptions) bool {
+	return opts.Refspec == "" && opts.PushRef == "" && !opts.TagsOnly
+}
+
+// pushRefspec returns the refspec argument for pushing to the target
+func pushRefspec(opts CloneOptions) string {
+	switch {
+	case opts.PushRef != "":
+		return opts.PushRef
+	case opts.Refspec != "":
+		return opts.Refspec
+	case opts.TagsOnly:
+		return tagsRefspec
+	default:
+		return "--all"
+	}
+}
+
+// pushArgs returns the git arguments for pushing to remote. localBranches
+// is only used when pruning with the default refspec.
+func pushArgs(opts CloneOptions, remote string, localBranches []string) []string {
+	args := networkArgs(opts, "push", remote)
+	if opts.Prune {
+		args = append(args, "--prune")
+		if defaultRefspec(opts) {
+			args = append(args, pruneRefspecs(localBranches)...)
+		} else {
+			args = append(args, pushRefspec(opts))
+		}
+	} else {
+		args = append(args, pushRefspec(opts))
+	}
+
+	switch {
+	case opts.ForceWithLease && len(opts.LeaseRefs) > 0:
+		refs := make([]string, 0, len(opts.LeaseRefs))
+		for ref := range opts.LeaseRefs {
+			refs = append(refs, ref)
+		}
+		sort.Strings(refs)
+		for _, ref := range refs {
+			args = append(args, "--force-with-lease="+ref+":"+opts.LeaseRefs[ref])
+		}
+	case opts.ForceWithLease:
+		args = append(args, "--force-with-lease")
+	case opts.Force:
+		args = append(args, "--force")
+	}
+	return args
+}
+
+// pruneRefspecs returns refspecs covering every source branch. A fresh
+// clone only has the default branch locally, so pruning against --all would
+// delete every other branch; the remaining branches are pushed from their
+// remote-tracking refs instead, skipping those with a local counterpart
+// that may carry new commits.
+func pruneRefspecs(localBranches []string) []string {
+	refspecs := []string{
+		"refs/heads/*:refs/heads/*",
+		"refs/remotes/origin/*:refs/heads/*",
+	}
+	for _, b := range localBranches {
+		refspecs = append(refspecs, "^refs/remotes/origin/"+b)
+	}
+	return refspecs
+}
+
+// gitLocalBranches lists the local branch names in dir. It is a variable so
+// it can be mocked in tests.
+var gitLocalBranches = func(dir string) ([]string, error) {
+	cmd := exec.Command("git", "for-each-ref", "--format=%(refname:short)", "refs/heads/")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.New("git-command", fmt.Errorf("git for-each-ref failed: %w", err))
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// ListBranches returns the names of the local branches in the repository at
+// dir, sorted by name. In a bare or mirror clone these are all of the
+// source's branches; a regular clone only has the branches checked out so
+// far. A repository without commits has no branches and yields an empty
+// list.
+func ListBranches(dir string) ([]string, error) {
+	branches, err := gitLocalBranches(dir)
+	if err != nil {
+		return nil, err
+	}
+	if branches == nil {
+		branches = []string{}
+	}
+	return branches, nil
+}
+
+// cloneSource clones sourceURL into dest, relative to dir. When
+// opts.SparsePaths is set, the clone is made without a checkout and only
+// those paths are checked out afterwards.
+func cloneSource(dir, sourceURL, dest string, opts CloneOptions) error {
+	token := opts.Token
+	if len(opts.SparsePaths) == 0 {
+		return runGitCommand(dir, token, networkArgs(opts, "clone", sourceURL, dest)...)
+	}
+
+	// The URL must directly follow "clone" so runGitCommand can inject the token
+	if err := runGitCommand(dir, token, networkArgs(opts, "clone", sourceURL, dest, "--no-checkout")...); err != nil {
+		return err
+	}
 
-			// Format URL with token
-			tokenURL, err := urlutils.FormatTokenURL(parsedURL, token)
-			if err != nil {
-				return errors.New("git-command", fmt.Errorf("failed to format URL with token: %w", err))
+	repoDir := dest
+	if dir != "" && !filepath.IsAbs(dest) {
+		repoDir = filepath.Join(dir, dest)
+	}
+
+	if err := runGitCommand(repoDir, token, "sparse-checkout", "init", "--cone"); err != nil {
+		return fmt.Errorf("failed to initialize sparse checkout: %w", err)
+	}
+	setArgs := append([]string{"sparse-checkout", "set"}, opts.SparsePaths...)
+	if err := runGitCommand(repoDir, token, setArgs...); err != nil {
+		return fmt.Errorf("failed to set sparse checkout paths: %w", err)
+	}
+	if err := runGitCommand(repoDir, token, "checkout"); err != nil {
+		return fmt.Errorf("failed to check out sparse paths: %w", err)
+	}
+	return nil
+}
+
+// validateRepoPaths rejects paths that are empty or escape the repository.
+// kind names the option being validated in error messages.
+func validateRepoPaths(kind string, paths []string) error {
+	for _, p := range paths {
+		if strings.TrimSpace(p) == "" {
+			return errors.New("clone", fmt.Errorf("%s paths must not be empty", kind))
+		}
+		for _, part := range strings.Split(filepath.ToSlash(p), "/") {
+			if part == ".." {
+				return errors.New("clone", fmt.Errorf("invalid %s path %q: must not contain '..'", kind, p))
 			}
+		}
+	}
+	return nil
+}
+
+// isArchivedError reports whether git's stderr shows the remote rejected the
+// operation because the repository is archived
+func isArchivedError(stderr string) bool {
+	return strings.Contains(strings.ToLower(stderr), "repository was archived")
+}
+
+// isLeaseRejectedError reports whether git's stderr shows a
+// --force-with-lease push was rejected because its expectation was stale
+func isLeaseRejectedError(stderr string) bool {
+	return strings.Contains(stderr, "(stale info)")
+}
+
+// urlArgIndex returns the position of the remote URL in args, or 0 if the
+// command does not take one. Leading "-c name=value" flags are skipped.
+func urlArgIndex(args []string) int {
+	offset := 0
+	for offset+1 < len(args) && args[offset] == "-c" {
+		offset += 2
+	}
+	cmd := args[offset:]
 
-			args[1] = tokenURL.String()
-			cmd.Args = append([]string{cmd.Args[0]}, args...)
+	switch {
+	case len(cmd) > 1 && (cmd[0] == "clone" || cmd[0] == "push"):
+		return offset + 1
+	case len(cmd) > 3 && cmd[0] == "remote" && cmd[1] == "add":
+		return offset + 3
+	default:
+		return 0
+	}
+}
+
+// authURL adds token to an HTTPS URL. Other URLs, and any URL when token
+// is empty, are returned unchanged.
+func authURL(rawURL, token string) (string, error) {
+	if token == "" || !strings.HasPrefix(rawURL, "https://") {
+		return rawURL, nil
+	}
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return "", errors.New("git-command", fmt.Errorf("invalid URL format: %w", err))
+	}
+
+	// Format URL with token
+	tokenURL, err := urlutils.FormatTokenURL(parsedURL, token)
+	if err != nil {
+		return "", errors.New("git-command", fmt.Errorf("failed to format URL with token: %w", err))
+	}
+	return tokenURL.String(), nil
+}
+
+// isStalledError reports whether git's stderr shows a transfer was aborted
+// by the low-speed limit
+func isStalledError(stderr string) bool {
+	return strings.Contains(stderr, "Operation too slow")
+}
+
+// stallEnv returns the environment applying the default low-speed limits,
+// unless the environment or args already configure them
+func stallEnv(args []string) []string {
+	if _, ok := os.LookupEnv("GIT_HTTP_LOW_SPEED_LIMIT"); ok {
+		return nil
+	}
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "http.lowSpeed") {
+			return nil
 		}
 	}
+	return []string{
+		fmt.Sprintf("GIT_HTTP_LOW_SPEED_LIMIT=%d", DefaultLowSpeedLimit),
+		fmt.Sprintf("GIT_HTTP_LOW_SPEED_TIME=%d", int(DefaultLowSpeedTime.Seconds())),
+	}
+}
+
+// retryDelay is the base delay between retries. It is a variable so it can
+// be shortened in tests.
+var retryDelay = 5 * time.Second
 
-	// For testing purposes, use test credentials
-	if token != "" {
-		cmd.Env = append(os.Environ(),
-			"GIT_AUTHOR_NAME=test",
-			"GIT_AUTHOR_EMAIL=test@example.com",
-			"GIT_COMMITTER_NAME=test",
-			"GIT_COMMITTER_EMAIL=test@example.com",
-			"GIT_SSL_NO_VERIFY=true",
-		)
+// testGitEnv is added to the environment of every git command. It is
+// empty outside tests, which may set a fixed identity or trust a local TLS
+// server with it.
+var testGitEnv []string
+
+// runGitCommand is a variable so it can be mocked in tests
+var runGitCommand = func(dir string, token string, args ...string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	// Handle HTTPS with token for clone, push and remote add commands with retries for rate limits
+	if i := urlArgIndex(args); i > 0 {
+		tokenURL, err := authURL(args[i], token)
+		if err != nil {
+			return err
+		}
+		args[i] = tokenURL
 	}
 
-	// Retry logic for rate limits and auth failures
+	// Git must fail rather than prompt for or look up other credentials
+	env := append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=", "GIT_CREDENTIAL_HELPER=")
+	env = append(env, testGitEnv...)
+	env = append(env, stallEnv(args)...)
+
+	// Retry logic for rate limits, auth failures and stalled transfers
 	var lastErr error
 	for i := 0; i < maxRetries; i++ {
+		// A command can only be run once, so each attempt gets a fresh one
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = dir
+		cmd.Env = env
+		cmd.Stdout = os.Stdout
+		var stderr bytes.Buffer
+		cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
+
 		err := cmd.Run()
 		if err == nil {
 			return nil
@@ -219,19 +1385,34 @@ var runGitCommand = func(dir string, token string, args ...string) error {
 
 		lastErr = err
 		errStr := err.Error()
+		stalled := isStalledError(stderr.String())
+		if stalled {
+			lastErr = fmt.Errorf("%w (%v)", ErrTransferStalled, err)
+		}
 
-		// Check for rate limit or auth failures
-		if strings.Contains(errStr, "HTTP 429") || 
-		   strings.Contains(errStr, "rate limit") || 
-		   strings.Contains(errStr, "Authentication failed") {
+		// Check for rate limit or auth failures and stalled transfers
+		if strings.Contains(errStr, "HTTP 429") ||
+			strings.Contains(errStr, "rate limit") ||
+			strings.Contains(errStr, "Authentication failed") ||
+			stalled {
 			select {
 			case <-ctx.Done():
 				return errors.New("git-command", fmt.Errorf("operation timed out: %w", ctx.Err()))
-			case <-time.After(time.Duration(i+1) * 5 * time.Second):
+			case <-time.After(time.Duration(i+1) * retryDelay):
 				continue
 			}
 		}
 
+		// GitHub answers pushes to archived repositories with a 403 that
+		// would otherwise surface as a bare exit status
+		if isArchivedError(stderr.String()) {
+			return errors.New("git-command", fmt.Errorf("%w: unarchive it before pushing (%v)", ErrRepositoryArchived, err))
+		}
+
+		if isLeaseRejectedError(stderr.String()) {
+			return errors.New("git-command", fmt.Errorf("%w: check the target and retry the push (%v)", ErrPushLeaseRejected, err))
+		}
+
 		// For non-retryable errors, return immediately
 		return errors.New("git-command", fmt.Errorf("git command failed: %w", err))
 	}
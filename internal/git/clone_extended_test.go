@@ -1,8 +1,17 @@
 package git
 
 import (
+	"bytes"
 	"context"
+	stderrors "errors"
 	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -195,3 +204,1514 @@ func TestCloneRepositoryTimeout(t *testing.T) {
 		t.Errorf("Expected deadline exceeded error, got: %v", err)
 	}
 }
+
+func TestCloneRepositorySparse(t *testing.T) {
+	originalRunGitCommand := runGitCommand
+	defer func() {
+		runGitCommand = originalRunGitCommand
+	}()
+
+	type call struct {
+		dir  string
+		args []string
+	}
+	var calls []call
+	runGitCommand = func(dir string, token string, args ...string) error {
+		calls = append(calls, call{dir: dir, args: append([]string(nil), args...)})
+		return nil
+	}
+
+	opts := CloneOptions{
+		SourceURL:   "https://github.com/test/repo.git",
+		WorkingDir:  "testdata",
+		Token:       "test-token",
+		SparsePaths: []string{"services/api", "docs"},
+	}
+
+	if err := CloneRepository(opts); err != nil {
+		t.Fatalf("CloneRepository() unexpected error: %v", err)
+	}
+
+	want := []call{
+		{dir: "", args: []string{"clone", "https://github.com/test/repo.git", "testdata", "--no-checkout"}},
+		{dir: "testdata", args: []string{"sparse-checkout", "init", "--cone"}},
+		{dir: "testdata", args: []string{"sparse-checkout", "set", "services/api", "docs"}},
+		{dir: "testdata", args: []string{"checkout"}},
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("got %d git commands, want %d: %v", len(calls), len(want), calls)
+	}
+	for i := range want {
+		if calls[i].dir != want[i].dir || strings.Join(calls[i].args, " ") != strings.Join(want[i].args, " ") {
+			t.Errorf("command %d = %q in %q, want %q in %q", i, calls[i].args, calls[i].dir, want[i].args, want[i].dir)
+		}
+	}
+}
+
+func TestCloneRepositorySparseInvalidPath(t *testing.T) {
+	originalRunGitCommand := runGitCommand
+	defer func() {
+		runGitCommand = originalRunGitCommand
+	}()
+
+	runGitCommand = func(dir string, token string, args ...string) error {
+		t.Fatalf("git should not run for invalid sparse paths, got %v", args)
+		return nil
+	}
+
+	for _, paths := range [][]string{{"../outside"}, {"docs/../../etc"}, {""}} {
+		opts := CloneOptions{
+			SourceURL:   "https://github.com/test/repo.git",
+			WorkingDir:  "testdata",
+			SparsePaths: paths,
+		}
+		if err := CloneRepository(opts); err == nil {
+			t.Errorf("CloneRepository() with sparse paths %q: expected error, got nil", paths)
+		}
+	}
+}
+
+func TestCloneRepositoryPushRefspec(t *testing.T) {
+	originalRunGitCommand := runGitCommand
+	defer func() {
+		runGitCommand = originalRunGitCommand
+	}()
+	stubHasCommits(t, true)
+
+	tests := []struct {
+		name     string
+		tagsOnly bool
+		refspec  string
+		pushRef  string
+		wantPush string
+		wantErr  bool
+	}{
+		{
+			name:     "all branches by default",
+			wantPush: "push target --all",
+		},
+		{
+			name:     "tags only",
+			tagsOnly: true,
+			wantPush: "push target refs/tags/*:refs/tags/*",
+		},
+		{
+			name:     "custom refspec",
+			refspec:  "refs/heads/main:refs/heads/main",
+			wantPush: "push target refs/heads/main:refs/heads/main",
+		},
+		{
+			name:     "tags only with refspec",
+			tagsOnly: true,
+			refspec:  "refs/heads/main:refs/heads/main",
+			wantErr:  true,
+		},
+		{
+			name:     "push ref",
+			pushRef:  "0123456789abcdef0123456789abcdef01234567:refs/heads/release",
+			wantPush: "push target 0123456789abcdef0123456789abcdef01234567:refs/heads/release",
+		},
+		{
+			name:    "push ref with abbreviated sha",
+			pushRef: "0123456:refs/heads/release",
+			wantErr: true,
+		},
+		{
+			name:    "push ref with refspec",
+			pushRef: "0123456789abcdef0123456789abcdef01234567:refs/heads/release",
+			refspec: "refs/heads/main:refs/heads/main",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var push string
+			runGitCommand = func(dir string, token string, args ...string) error {
+				if len(args) > 0 && args[0] == "push" {
+					push = strings.Join(args, " ")
+				}
+				return nil
+			}
+
+			err := CloneRepository(CloneOptions{
+				SourceURL: "https://github.com/test/repo.git",
+				TargetURL: "https://github.com/test/mirror.git",
+				Token:     "test-token",
+				TagsOnly:  tt.tagsOnly,
+				Refspec:   tt.refspec,
+				PushRef:   tt.pushRef,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CloneRepository() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if push != tt.wantPush {
+				t.Errorf("push command = %q, want %q", push, tt.wantPush)
+			}
+		})
+	}
+}
+
+func TestCloneRepositoryForcePush(t *testing.T) {
+	originalRunGitCommand := runGitCommand
+	defer func() {
+		runGitCommand = originalRunGitCommand
+	}()
+	stubHasCommits(t, true)
+
+	tests := []struct {
+		name           string
+		force          bool
+		forceWithLease bool
+		leaseRefs      map[string]string
+		wantCalls      []string
+	}{
+		{
+			name:      "no force by default",
+			wantCalls: []string{"remote add target", "push target --all"},
+		},
+		{
+			name:      "force",
+			force:     true,
+			wantCalls: []string{"remote add target", "push target --all --force"},
+		},
+		{
+			name:           "force with lease does not fetch the target",
+			forceWithLease: true,
+			wantCalls:      []string{"remote add target", "push target --all --force-with-lease"},
+		},
+		{
+			name:           "lease takes precedence over force",
+			force:          true,
+			forceWithLease: true,
+			wantCalls:      []string{"remote add target", "push target --all --force-with-lease"},
+		},
+		{
+			name:           "recorded refs become explicit leases",
+			forceWithLease: true,
+			leaseRefs: map[string]string{
+				"refs/heads/main": "1111111111111111111111111111111111111111",
+				"refs/heads/dev":  "2222222222222222222222222222222222222222",
+			},
+			wantCalls: []string{"remote add target", "push target --all" +
+				" --force-with-lease=refs/heads/dev:2222222222222222222222222222222222222222" +
+				" --force-with-lease=refs/heads/main:1111111111111111111111111111111111111111"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			runGitCommand = func(dir string, token string, args ...string) error {
+				if args[0] != "clone" {
+					calls = append(calls, strings.Join(args, " "))
+				}
+				return nil
+			}
+
+			err := CloneRepository(CloneOptions{
+				SourceURL:      "https://github.com/test/repo.git",
+				TargetURL:      "https://github.com/test/mirror.git",
+				Force:          tt.force,
+				ForceWithLease: tt.forceWithLease,
+				LeaseRefs:      tt.leaseRefs,
+			})
+			if err != nil {
+				t.Fatalf("CloneRepository() unexpected error: %v", err)
+			}
+
+			if len(calls) != len(tt.wantCalls) {
+				t.Fatalf("got git commands %q, want %q", calls, tt.wantCalls)
+			}
+			for i, want := range tt.wantCalls {
+				if !strings.HasPrefix(calls[i], want) || (strings.HasPrefix(want, "push") && calls[i] != want) {
+					t.Errorf("command %d = %q, want %q", i, calls[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestCloneRepositoryPrune(t *testing.T) {
+	originalRunGitCommand := runGitCommand
+	originalGitLocalBranches := gitLocalBranches
+	defer func() {
+		runGitCommand = originalRunGitCommand
+		gitLocalBranches = originalGitLocalBranches
+	}()
+	stubHasCommits(t, true)
+	gitLocalBranches = func(dir string) ([]string, error) {
+		return []string{"main"}, nil
+	}
+
+	tests := []struct {
+		name     string
+		opts     CloneOptions
+		wantPush string
+	}{
+		{
+			name:     "no prune by default",
+			wantPush: "push target --all",
+		},
+		{
+			name:     "prune mirrors all source branches",
+			opts:     CloneOptions{Prune: true},
+			wantPush: "push target --prune refs/heads/*:refs/heads/* refs/remotes/origin/*:refs/heads/* ^refs/remotes/origin/main",
+		},
+		{
+			name:     "prune tags only",
+			opts:     CloneOptions{Prune: true, TagsOnly: true},
+			wantPush: "push target --prune refs/tags/*:refs/tags/*",
+		},
+		{
+			name:     "prune custom refspec with force",
+			opts:     CloneOptions{Prune: true, Refspec: "refs/heads/release/*:refs/heads/release/*", Force: true},
+			wantPush: "push target --prune refs/heads/release/*:refs/heads/release/* --force",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var push string
+			runGitCommand = func(dir string, token string, args ...string) error {
+				if args[0] == "push" {
+					push = strings.Join(args, " ")
+				}
+				return nil
+			}
+
+			opts := tt.opts
+			opts.SourceURL = "https://github.com/test/repo.git"
+			opts.TargetURL = "https://github.com/test/mirror.git"
+			if err := CloneRepository(opts); err != nil {
+				t.Fatalf("CloneRepository() unexpected error: %v", err)
+			}
+			if push != tt.wantPush {
+				t.Errorf("push command = %q, want %q", push, tt.wantPush)
+			}
+		})
+	}
+}
+
+func TestCloneRepositoryPruneDeletesTargetBranches(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	base := t.TempDir()
+	work := filepath.Join(base, "work")
+	source := filepath.Join(base, "source.git")
+	target := filepath.Join(base, "target.git")
+	commit := []string{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m"}
+
+	for _, step := range []struct {
+		dir  string
+		args []string
+	}{
+		{base, []string{"init", "-q", "-b", "main", work}},
+		{work, append(commit, "initial")},
+		{work, []string{"branch", "feature"}},
+		{work, []string{"branch", "stale"}},
+		{base, []string{"clone", "-q", "--bare", work, source}},
+		{base, []string{"clone", "-q", "--bare", source, target}},
+		{source, []string{"branch", "-D", "stale"}},
+	} {
+		if err := runGitCommand(step.dir, "", step.args...); err != nil {
+			t.Fatalf("git %s: %v", strings.Join(step.args, " "), err)
+		}
+	}
+
+	err := CloneRepository(CloneOptions{
+		SourceURL: "file://" + source,
+		TargetURL:  "file://" + target,
+		Prune:      true,
+		VerifyPush: true,
+	})
+	if err != nil {
+		t.Fatalf("CloneRepository() unexpected error: %v", err)
+	}
+
+	out, err := exec.Command("git", "-C", target, "for-each-ref", "--format=%(refname)").Output()
+	if err != nil {
+		t.Fatalf("failed to list target refs: %v", err)
+	}
+	if got, want := strings.Fields(string(out)), []string{"refs/heads/feature", "refs/heads/main"}; strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("target refs = %q, want %q", got, want)
+	}
+}
+
+// stubHasCommits makes ensureCommits see a clone with or without a HEAD
+// commit, since mocked clones create no repository
+func stubHasCommits(t *testing.T, has bool) {
+	t.Helper()
+	original := gitHasCommits
+	t.Cleanup(func() { gitHasCommits = original })
+	gitHasCommits = func(string) bool { return has }
+}
+
+func TestCloneRepositoryEmptyRepository(t *testing.T) {
+	originalRunGitCommand := runGitCommand
+	defer func() {
+		runGitCommand = originalRunGitCommand
+	}()
+	// A fresh clone of an empty repository has no HEAD commit
+	stubHasCommits(t, false)
+
+	tests := []struct {
+		name      string
+		seed      bool
+		wantCalls []string
+		wantErr   error
+	}{
+		{
+			name: "empty source is rejected",
+			wantCalls: []string{
+				"clone",
+			},
+			wantErr: ErrEmptyRepository,
+		},
+		{
+			name: "empty source is seeded",
+			seed: true,
+			wantCalls: []string{
+				"clone",
+				"commit --allow-empty -m Initial commit",
+				"remote add target",
+				"push target --all",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			runGitCommand = func(dir string, token string, args ...string) error {
+				calls = append(calls, strings.Join(args, " "))
+				return nil
+			}
+
+			err := CloneRepository(CloneOptions{
+				SourceURL:           "https://github.com/test/repo.git",
+				TargetURL:           "https://github.com/test/mirror.git",
+				SeedEmptyRepository: tt.seed,
+			})
+			if tt.wantErr != nil {
+				if !stderrors.Is(err, tt.wantErr) {
+					t.Fatalf("CloneRepository() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("CloneRepository() unexpected error: %v", err)
+			}
+
+			if len(calls) != len(tt.wantCalls) {
+				t.Fatalf("got git commands %q, want %q", calls, tt.wantCalls)
+			}
+			for i, want := range tt.wantCalls {
+				if !strings.HasPrefix(calls[i], want) {
+					t.Errorf("command %d = %q, want prefix %q", i, calls[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestPushRepositorySetDefaultBranch(t *testing.T) {
+	originalRunGitCommand := runGitCommand
+	originalGitHeadBranch := gitHeadBranch
+	defer func() {
+		runGitCommand = originalRunGitCommand
+		gitHeadBranch = originalGitHeadBranch
+	}()
+	gitHeadBranch = func(dir string) (string, error) {
+		return "develop", nil
+	}
+
+	var pushed bool
+	runGitCommand = func(dir string, token string, args ...string) error {
+		if args[0] == "push" {
+			pushed = true
+		}
+		return nil
+	}
+
+	var branches []string
+	opts := CloneOptions{
+		TargetURL: "https://github.com/test/mirror.git",
+		SetDefaultBranch: func(branch string) error {
+			if !pushed {
+				t.Error("default branch set before pushing")
+			}
+			branches = append(branches, branch)
+			return nil
+		},
+	}
+	if err := PushRepository("/work/clone", opts); err != nil {
+		t.Fatalf("PushRepository() unexpected error: %v", err)
+	}
+	if len(branches) != 1 || branches[0] != "develop" {
+		t.Errorf("SetDefaultBranch called with %q, want [develop]", branches)
+	}
+
+	// A failed update fails the push operation
+	opts.SetDefaultBranch = func(branch string) error {
+		return fmt.Errorf("forbidden")
+	}
+	err := PushRepository("/work/clone", opts)
+	if err == nil || !strings.Contains(err.Error(), "failed to set default branch to develop") {
+		t.Errorf("PushRepository() error = %v, want default branch failure", err)
+	}
+
+	// Nothing is updated when the push fails
+	branches = nil
+	opts.SetDefaultBranch = func(branch string) error {
+		branches = append(branches, branch)
+		return nil
+	}
+	runGitCommand = func(dir string, token string, args ...string) error {
+		if args[0] == "push" {
+			return fmt.Errorf("remote rejected")
+		}
+		return nil
+	}
+	if err := PushRepository("/work/clone", opts); err == nil {
+		t.Error("PushRepository() expected error")
+	}
+	if len(branches) != 0 {
+		t.Errorf("SetDefaultBranch called after failed push with %q", branches)
+	}
+}
+
+func TestPushRepositoryVerify(t *testing.T) {
+	originalRunGitCommand := runGitCommand
+	originalGitRefs := gitRefs
+	defer func() {
+		runGitCommand = originalRunGitCommand
+		gitRefs = originalGitRefs
+	}()
+	runGitCommand = func(dir string, token string, args ...string) error {
+		return nil
+	}
+
+	local := map[string]string{
+		"HEAD":                     "aaaa",
+		"refs/heads/main":          "aaaa",
+		"refs/heads/develop":       "bbbb",
+		"refs/remotes/origin/main": "aaaa",
+		"refs/tags/v1.0":           "cccc",
+	}
+
+	tests := []struct {
+		name       string
+		opts       CloneOptions
+		remote     map[string]string
+		mismatched string
+	}{
+		{
+			name:   "all branches match",
+			remote: map[string]string{"refs/heads/main": "aaaa", "refs/heads/develop": "bbbb"},
+		},
+		{
+			name:       "partial push",
+			remote:     map[string]string{"refs/heads/main": "aaaa", "refs/heads/develop": "0000"},
+			mismatched: "refs/heads/develop",
+		},
+		{
+			name:       "missing ref",
+			remote:     map[string]string{"refs/heads/develop": "bbbb"},
+			mismatched: "refs/heads/main",
+		},
+		{
+			name:       "tags only",
+			opts:       CloneOptions{TagsOnly: true},
+			remote:     map[string]string{},
+			mismatched: "refs/tags/v1.0",
+		},
+		{
+			name:   "custom refspec",
+			opts:   CloneOptions{Refspec: "main:refs/heads/release"},
+			remote: map[string]string{"refs/heads/release": "aaaa"},
+		},
+		{
+			name:       "push ref",
+			opts:       CloneOptions{PushRef: "0123456789abcdef0123456789abcdef01234567:refs/heads/release"},
+			remote:     map[string]string{"refs/heads/release": "aaaa"},
+			mismatched: "refs/heads/release",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gitRefs = func(dir, remote string) (map[string]string, error) {
+				if remote == "." {
+					return local, nil
+				}
+				return tt.remote, nil
+			}
+
+			opts := tt.opts
+			opts.TargetURL = "https://github.com/test/mirror.git"
+			opts.VerifyPush = true
+			err := PushRepository("/work/clone", opts)
+			if tt.mismatched == "" {
+				if err != nil {
+					t.Fatalf("PushRepository() unexpected error: %v", err)
+				}
+				return
+			}
+			if !stderrors.Is(err, ErrVerificationFailed) {
+				t.Fatalf("PushRepository() error = %v, want ErrVerificationFailed", err)
+			}
+			if !strings.HasSuffix(err.Error(), ": "+tt.mismatched) {
+				t.Errorf("expected error to name only %s, got %q", tt.mismatched, err.Error())
+			}
+		})
+	}
+}
+
+func TestMapRef(t *testing.T) {
+	tests := []struct {
+		ref, src, dst string
+		want          string
+		wantOK        bool
+	}{
+		{"refs/heads/main", "refs/heads/*", "refs/heads/*", "refs/heads/main", true},
+		{"refs/remotes/origin/dev", "refs/remotes/origin/*", "refs/heads/*", "refs/heads/dev", true},
+		{"refs/tags/v1", "refs/heads/*", "refs/heads/*", "", false},
+		{"refs/heads/main", "main", "release", "refs/heads/release", true},
+		{"refs/heads/other", "main", "main", "refs/heads/main", false},
+	}
+	for _, tt := range tests {
+		got, ok := mapRef(tt.ref, tt.src, tt.dst)
+		if ok != tt.wantOK || (ok && got != tt.want) {
+			t.Errorf("mapRef(%q, %q, %q) = %q, %v, want %q, %v", tt.ref, tt.src, tt.dst, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestCloneRepositoryRateLimit(t *testing.T) {
+	originalRunGitCommand := runGitCommand
+	defer func() {
+		runGitCommand = originalRunGitCommand
+	}()
+	stubHasCommits(t, true)
+
+	var calls []string
+	runGitCommand = func(dir string, token string, args ...string) error {
+		calls = append(calls, strings.Join(args, " "))
+		return nil
+	}
+
+	err := CloneRepository(CloneOptions{
+		SourceURL:      "https://github.com/test/repo.git",
+		TargetURL:      "https://github.com/test/mirror.git",
+		RateLimitKBps:  256,
+		ForceWithLease: true,
+	})
+	if err != nil {
+		t.Fatalf("CloneRepository() unexpected error: %v", err)
+	}
+
+	// Network commands go through the throttling proxy, which is stopped
+	// once the clone returns
+	proxy := regexp.MustCompile(`http\.proxy=(http://127\.0\.0\.1:\d+)`).FindStringSubmatch(strings.Join(calls, "\n"))
+	if proxy == nil {
+		t.Fatalf("git commands %q do not use the throttling proxy", calls)
+	}
+	if conn, err := net.Dial("tcp", strings.TrimPrefix(proxy[1], "http://")); err == nil {
+		conn.Close()
+		t.Error("throttling proxy still accepts connections after CloneRepository returned")
+	}
+
+	throttle := "-c http.postBuffer=262144 -c http.proxy=" + proxy[1] + " -c http.lowSpeedLimit=1000 -c http.lowSpeedTime=60 "
+	wantCalls := []string{
+		throttle + "clone https://github.com/test/repo.git .",
+		"remote add target https://github.com/test/mirror.git",
+		throttle + "push target --all --force-with-lease",
+	}
+	if strings.Join(calls, "\n") != strings.Join(wantCalls, "\n") {
+		t.Errorf("git commands =\n%s\nwant\n%s", strings.Join(calls, "\n"), strings.Join(wantCalls, "\n"))
+	}
+
+	if args := ThrottleArgs(0); args != nil {
+		t.Errorf("ThrottleArgs(0) = %q, want nil", args)
+	}
+}
+
+func TestPushRepository(t *testing.T) {
+	originalRunGitCommand := runGitCommand
+	defer func() {
+		runGitCommand = originalRunGitCommand
+	}()
+
+	var calls []string
+	var tokens []string
+	runGitCommand = func(dir string, token string, args ...string) error {
+		if dir != "/work/clone" {
+			t.Errorf("git ran in %q, want /work/clone", dir)
+		}
+		calls = append(calls, strings.Join(args, " "))
+		tokens = append(tokens, token)
+		return nil
+	}
+
+	tracker := &mockProgressTracker{}
+	err := PushRepository("/work/clone", CloneOptions{
+		TargetURL: "https://github.com/test/mirror.git",
+		Token:     "test-token",
+		TagsOnly:  true,
+		Progress:  tracker,
+	})
+	if err != nil {
+		t.Fatalf("PushRepository() unexpected error: %v", err)
+	}
+
+	// The target is pushed through a temporary remote that is removed again
+	if len(calls) == 0 || !strings.HasPrefix(calls[0], "remote add gittools-push-") {
+		t.Fatalf("got git commands %q, want a temporary remote to be added first", calls)
+	}
+	remote := strings.Fields(calls[0])[2]
+	wantCalls := []string{
+		"remote add " + remote + " https://github.com/test/mirror.git",
+		"push " + remote + " refs/tags/*:refs/tags/*",
+		"remote remove " + remote,
+	}
+	if strings.Join(calls, "\n") != strings.Join(wantCalls, "\n") {
+		t.Errorf("got git commands %q, want %q", calls, wantCalls)
+	}
+	wantTokens := []string{"test-token", "test-token", ""}
+	if strings.Join(tokens, ",") != strings.Join(wantTokens, ",") {
+		t.Errorf("git ran with tokens %q, want %q", tokens, wantTokens)
+	}
+	if !tracker.started || !tracker.completed {
+		t.Error("expected progress to be started and completed")
+	}
+
+	// The remote is removed even when the push fails
+	calls = nil
+	runGitCommand = func(dir string, token string, args ...string) error {
+		calls = append(calls, strings.Join(args, " "))
+		if args[0] == "push" {
+			return fmt.Errorf("push rejected")
+		}
+		return nil
+	}
+	err = PushRepository("/work/clone", CloneOptions{TargetURL: "https://github.com/test/mirror.git"})
+	if err == nil || !strings.Contains(err.Error(), "push rejected") {
+		t.Errorf("PushRepository() error = %v, want push rejected", err)
+	}
+	if len(calls) != 3 || !strings.HasPrefix(calls[2], "remote remove gittools-push-") {
+		t.Errorf("got git commands %q, want the temporary remote removed", calls)
+	}
+
+	// Invalid options are rejected before running git
+	calls = nil
+	for _, opts := range []CloneOptions{
+		{},
+		{TargetURL: "git@github.com:test/mirror.git"},
+		{TargetURL: "https://github.com/test/mirror.git", TagsOnly: true, Refspec: "refs/heads/main"},
+	} {
+		if err := PushRepository("/work/clone", opts); err == nil {
+			t.Errorf("PushRepository(%+v) expected error", opts)
+		}
+	}
+	if len(calls) != 0 {
+		t.Errorf("expected no git commands for invalid options, got %q", calls)
+	}
+}
+
+func TestValidatePushRef(t *testing.T) {
+	const sha = "0123456789abcdef0123456789abcdef01234567"
+	tests := []struct {
+		pushRef string
+		wantErr bool
+	}{
+		{sha + ":refs/heads/main", false},
+		{sha + ":refs/heads/release/1.0", false},
+		{"0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef:refs/heads/main", false},
+		{sha, true},
+		{"0123456:refs/heads/main", true},
+		{"0123456789ABCDEF0123456789ABCDEF01234567:refs/heads/main", true},
+		{"main:refs/heads/main", true},
+		{sha + ":main", true},
+		{sha + ":refs/tags/v1.0", true},
+		{sha + ":refs/heads/", true},
+		{sha + ":refs/heads/a..b", true},
+		{sha + ":refs/heads/feature.lock", true},
+		{sha + ":refs/heads/.hidden", true},
+		{sha + ":refs/heads/has space", true},
+		{sha + ":refs/heads/-flag", true},
+	}
+	for _, tt := range tests {
+		if err := validatePushRef(tt.pushRef); (err != nil) != tt.wantErr {
+			t.Errorf("validatePushRef(%q) error = %v, wantErr %v", tt.pushRef, err, tt.wantErr)
+		}
+	}
+}
+
+func TestURLArgIndex(t *testing.T) {
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{[]string{"clone", "https://github.com/o/r.git", "."}, 1},
+		{[]string{"push", "target", "--all"}, 1},
+		{[]string{"remote", "add", "target", "https://github.com/o/r.git"}, 3},
+		{[]string{"remote", "remove", "target"}, 0},
+		{[]string{"fetch", "--all"}, 0},
+		{[]string{"-c", "http.postBuffer=1024", "clone", "https://github.com/o/r.git", "."}, 3},
+		{[]string{"-c", "http.postBuffer=1024", "-c", "http.lowSpeedTime=60", "push", "target"}, 5},
+		{[]string{"-c", "commit.gpgsign=true", "commit", "-m", "msg"}, 0},
+	}
+	for _, tt := range tests {
+		if got := urlArgIndex(tt.args); got != tt.want {
+			t.Errorf("urlArgIndex(%q) = %d, want %d", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateRepository(t *testing.T) {
+	originalRunGitCommand := runGitCommand
+	defer func() {
+		runGitCommand = originalRunGitCommand
+	}()
+
+	tests := []struct {
+		name      string
+		existing  bool
+		wantCalls []string
+	}{
+		{
+			name:     "existing repository is fetched and fast-forwarded",
+			existing: true,
+			wantCalls: []string{
+				"fetch --all --prune",
+				"merge --ff-only @{upstream}",
+			},
+		},
+		{
+			name:     "empty directory falls back to clone",
+			existing: false,
+			wantCalls: []string{
+				"clone https://github.com/test/repo.git",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.existing {
+				if err := os.Mkdir(filepath.Join(dir, ".git"), 0755); err != nil {
+					t.Fatalf("failed to create .git directory: %v", err)
+				}
+			}
+
+			var calls []string
+			runGitCommand = func(cmdDir string, token string, args ...string) error {
+				calls = append(calls, strings.Join(args, " "))
+				return nil
+			}
+
+			err := UpdateRepository(CloneOptions{
+				SourceURL:  "https://github.com/test/repo.git",
+				WorkingDir: dir,
+				Token:      "test-token",
+			})
+			if err != nil {
+				t.Fatalf("UpdateRepository() unexpected error: %v", err)
+			}
+
+			if len(calls) != len(tt.wantCalls) {
+				t.Fatalf("got git commands %q, want %q", calls, tt.wantCalls)
+			}
+			for i, want := range tt.wantCalls {
+				if !strings.HasPrefix(calls[i], want) {
+					t.Errorf("command %d = %q, want prefix %q", i, calls[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestCloneRepositoryPostClone(t *testing.T) {
+	originalRunGitCommand := runGitCommand
+	originalGitHasChanges := gitHasChanges
+	defer func() {
+		runGitCommand = originalRunGitCommand
+		gitHasChanges = originalGitHasChanges
+	}()
+	stubHasCommits(t, true)
+
+	// Report changes only if the hook's file exists in the clone directory
+	gitHasChanges = func(dir string) (bool, error) {
+		_, err := os.Stat(filepath.Join(dir, "NOTICE"))
+		return err == nil, nil
+	}
+
+	tests := []struct {
+		name      string
+		hook      func(string) error
+		wantCalls []string
+		wantErr   bool
+	}{
+		{
+			name: "hook changes are committed before push",
+			hook: func(dir string) error {
+				return os.WriteFile(filepath.Join(dir, "NOTICE"), []byte("mirrored\n"), 0644)
+			},
+			wantCalls: []string{
+				"clone",
+				"add -A",
+				"commit -m Apply post-clone changes",
+				"remote add target",
+				"push target --all",
+			},
+		},
+		{
+			name: "hook without changes skips commit",
+			hook: func(dir string) error { return nil },
+			wantCalls: []string{
+				"clone",
+				"remote add target",
+				"push target --all",
+			},
+		},
+		{
+			name: "hook error aborts push",
+			hook: func(dir string) error { return fmt.Errorf("secret found") },
+			wantCalls: []string{
+				"clone",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			runGitCommand = func(dir string, token string, args ...string) error {
+				calls = append(calls, strings.Join(args, " "))
+				return nil
+			}
+
+			err := CloneRepository(CloneOptions{
+				SourceURL: "https://github.com/test/repo.git",
+				TargetURL: "https://github.com/test/mirror.git",
+				PostClone: tt.hook,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CloneRepository() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if len(calls) != len(tt.wantCalls) {
+				t.Fatalf("got git commands %q, want %q", calls, tt.wantCalls)
+			}
+			for i, want := range tt.wantCalls {
+				if !strings.HasPrefix(calls[i], want) {
+					t.Errorf("command %d = %q, want prefix %q", i, calls[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestCloneRepositoryExcludePaths(t *testing.T) {
+	originalRunGitCommand := runGitCommand
+	originalHistoryFiles, originalFilterPaths := gitHistoryFiles, gitFilterPaths
+	defer func() {
+		runGitCommand = originalRunGitCommand
+		gitHistoryFiles, gitFilterPaths = originalHistoryFiles, originalFilterPaths
+	}()
+	stubHasCommits(t, true)
+
+	var calls []string
+	runGitCommand = func(dir string, token string, args ...string) error {
+		calls = append(calls, strings.Join(args, " "))
+		return nil
+	}
+	gitHistoryFiles = func(dir string, paths []string) ([]string, error) {
+		calls = append(calls, "log "+strings.Join(paths, " "))
+		return []string{"secrets/config.yml"}, nil
+	}
+	gitFilterPaths = func(dir string, paths []string) error {
+		calls = append(calls, "filter-branch "+strings.Join(paths, " "))
+		return nil
+	}
+
+	err := CloneRepository(CloneOptions{
+		SourceURL:    "https://github.com/test/repo.git",
+		TargetURL:    "https://github.com/test/mirror.git",
+		ExcludePaths: []string{"secrets", "internal/keys"},
+	})
+	if err != nil {
+		t.Fatalf("CloneRepository() unexpected error: %v", err)
+	}
+
+	wantCalls := []string{
+		"clone",
+		"log secrets internal/keys",
+		"filter-branch secrets internal/keys",
+		"remote add target",
+		"push target --all",
+	}
+	if len(calls) != len(wantCalls) {
+		t.Fatalf("got git commands %q, want %q", calls, wantCalls)
+	}
+	for i, want := range wantCalls {
+		if !strings.HasPrefix(calls[i], want) {
+			t.Errorf("command %d = %q, want prefix %q", i, calls[i], want)
+		}
+	}
+
+	// History is left alone when no commit touches the excluded paths
+	calls = nil
+	gitHistoryFiles = func(dir string, paths []string) ([]string, error) {
+		return nil, nil
+	}
+	err = CloneRepository(CloneOptions{
+		SourceURL:    "https://github.com/test/repo.git",
+		WorkingDir:   t.TempDir(),
+		ExcludePaths: []string{"secrets"},
+	})
+	if err != nil {
+		t.Fatalf("CloneRepository() unexpected error: %v", err)
+	}
+	for _, call := range calls {
+		if strings.HasPrefix(call, "filter-branch") {
+			t.Errorf("history rewritten without excluded files: %q", calls)
+		}
+	}
+
+	// An exclusion escaping the repository is rejected before cloning
+	calls = nil
+	err = CloneRepository(CloneOptions{
+		SourceURL:    "https://github.com/test/repo.git",
+		TargetURL:    "https://github.com/test/mirror.git",
+		ExcludePaths: []string{"../outside"},
+	})
+	if err == nil {
+		t.Error("expected error for exclude path containing '..'")
+	}
+	if len(calls) != 0 {
+		t.Errorf("expected no git commands, got %q", calls)
+	}
+}
+
+func TestRemoveExcludedPathsRewritesHistory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	// A secret committed early and kept on a branch and a tag
+	dir := t.TempDir()
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+		return string(out)
+	}
+	run("init", "-q", "-b", "main")
+	for _, f := range []string{"secrets/key", "it's/key", "README.md"} {
+		if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(f)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, f), []byte(f), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run("add", "-A")
+	run("commit", "-q", "-m", "initial")
+	run("tag", "-a", "v1", "-m", "v1")
+	run("branch", "old")
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("updated"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run("commit", "-q", "-am", "update")
+
+	if err := removeExcludedPaths(dir, CloneOptions{ExcludePaths: []string{"secrets", "it's"}}); err != nil {
+		t.Fatalf("removeExcludedPaths() unexpected error: %v", err)
+	}
+
+	if leaked := run("log", "--all", "--format=%H", "--", "secrets", "it's"); leaked != "" {
+		t.Errorf("excluded paths still in history:\n%s", leaked)
+	}
+	if refs := run("for-each-ref", "refs/original/"); refs != "" {
+		t.Errorf("backup refs keep the old history:\n%s", refs)
+	}
+	if files := run("ls-tree", "-r", "--name-only", "v1"); strings.TrimSpace(files) != "README.md" {
+		t.Errorf("tag v1 has files %q, want README.md", files)
+	}
+	if subjects := run("log", "--format=%s", "main"); subjects != "update\ninitial\n" {
+		t.Errorf("main history = %q, want both commits", subjects)
+	}
+}
+
+func TestCloneRepositoryDiskSpaceCheck(t *testing.T) {
+	originalRunGitCommand := runGitCommand
+	originalFreeDiskSpace := freeDiskSpace
+	defer func() {
+		runGitCommand = originalRunGitCommand
+		freeDiskSpace = originalFreeDiskSpace
+	}()
+
+	var cloned bool
+	runGitCommand = func(dir string, token string, args ...string) error {
+		cloned = true
+		return nil
+	}
+	freeDiskSpace = func(path string) (uint64, error) {
+		return 100 << 20, nil // 100 MB
+	}
+
+	tests := []struct {
+		name          string
+		check         bool
+		estimatedSize int64
+		wantErr       bool
+	}{
+		{name: "enough space", check: true, estimatedSize: 10 << 20},
+		{name: "insufficient space", check: true, estimatedSize: 80 << 20, wantErr: true},
+		{name: "check disabled", check: false, estimatedSize: 80 << 20},
+		{name: "unknown size", check: true, estimatedSize: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cloned = false
+			err := CloneRepository(CloneOptions{
+				SourceURL:      "https://github.com/test/repo.git",
+				WorkingDir:     "testdata",
+				CheckDiskSpace: tt.check,
+				EstimatedSize:  tt.estimatedSize,
+			})
+
+			if tt.wantErr {
+				if !stderrors.Is(err, ErrInsufficientDiskSpace) {
+					t.Fatalf("expected ErrInsufficientDiskSpace, got %v", err)
+				}
+				if cloned {
+					t.Error("clone should not start when disk space is insufficient")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestCloneRepositoryTempDir(t *testing.T) {
+	originalRunGitCommand := runGitCommand
+	defer func() {
+		runGitCommand = originalRunGitCommand
+	}()
+	stubHasCommits(t, true)
+
+	var cloneDir string
+	runGitCommand = func(dir string, token string, args ...string) error {
+		if args[0] == "clone" {
+			cloneDir = dir
+		}
+		return nil
+	}
+
+	baseDir := t.TempDir()
+	err := CloneRepository(CloneOptions{
+		SourceURL: "https://github.com/test/repo.git",
+		TargetURL: "https://github.com/test/mirror.git",
+		TempDir:   baseDir,
+	})
+	if err != nil {
+		t.Fatalf("CloneRepository() unexpected error: %v", err)
+	}
+	if filepath.Dir(cloneDir) != baseDir {
+		t.Errorf("clone ran in %q, want a directory under %q", cloneDir, baseDir)
+	}
+
+	// A missing temp directory is rejected before cloning
+	cloneDir = ""
+	err = CloneRepository(CloneOptions{
+		SourceURL: "https://github.com/test/repo.git",
+		TargetURL: "https://github.com/test/mirror.git",
+		TempDir:   filepath.Join(baseDir, "missing"),
+	})
+	if err == nil {
+		t.Error("expected error for missing temp directory")
+	}
+	if cloneDir != "" {
+		t.Error("clone should not start with an invalid temp directory")
+	}
+}
+
+func TestCloneRepositoryKeepTempOnError(t *testing.T) {
+	originalRunGitCommand := runGitCommand
+	defer func() {
+		runGitCommand = originalRunGitCommand
+	}()
+
+	var logBuf bytes.Buffer
+	log.SetOutput(&logBuf)
+	defer log.SetOutput(os.Stderr)
+
+	tests := []struct {
+		name     string
+		keep     bool
+		wantKept bool
+	}{
+		{name: "kept on push failure", keep: true, wantKept: true},
+		{name: "removed by default", keep: false, wantKept: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logBuf.Reset()
+			var cloneDir string
+			runGitCommand = func(dir string, token string, args ...string) error {
+				switch args[0] {
+				case "clone":
+					cloneDir = dir
+				case "push":
+					return fmt.Errorf("remote rejected")
+				}
+				return nil
+			}
+
+			err := CloneRepository(CloneOptions{
+				SourceURL:       "https://github.com/test/repo.git",
+				TargetURL:       "https://github.com/test/mirror.git",
+				TempDir:         t.TempDir(),
+				KeepTempOnError: tt.keep,
+			})
+			if err == nil {
+				t.Fatal("expected push failure")
+			}
+
+			_, statErr := os.Stat(cloneDir)
+			if kept := statErr == nil; kept != tt.wantKept {
+				t.Errorf("working directory kept = %v, want %v", kept, tt.wantKept)
+			}
+			if reported := strings.Contains(logBuf.String(), cloneDir); reported != tt.wantKept {
+				t.Errorf("path reported = %v, want %v (log: %q)", reported, tt.wantKept, logBuf.String())
+			}
+		})
+	}
+}
+
+func TestCloneRepositoryKeepTempOnErrorClearsToken(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	originalRunGitCommand := runGitCommand
+	defer func() {
+		runGitCommand = originalRunGitCommand
+	}()
+	stubHasCommits(t, true)
+
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	// Record the remotes the way real clones with a token would
+	var cloneDir string
+	realGit := func(dir string, args ...string) error {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		return cmd.Run()
+	}
+	runGitCommand = func(dir string, token string, args ...string) error {
+		switch args[0] {
+		case "clone":
+			cloneDir = dir
+			origin, err := authURL(args[1], token)
+			if err != nil {
+				return err
+			}
+			if err := realGit(dir, "init", "-q"); err != nil {
+				return err
+			}
+			return realGit(dir, "remote", "add", "origin", origin)
+		case "remote":
+			target, err := authURL(args[3], token)
+			if err != nil {
+				return err
+			}
+			return realGit(dir, "remote", "add", args[2], target)
+		case "push":
+			return fmt.Errorf("remote rejected")
+		}
+		return nil
+	}
+
+	err := CloneRepository(CloneOptions{
+		SourceURL:       "https://github.com/test/repo.git",
+		TargetURL:       "https://github.com/test/mirror.git",
+		Token:           "ghp_secrettoken123",
+		TempDir:         t.TempDir(),
+		KeepTempOnError: true,
+	})
+	if err == nil {
+		t.Fatal("expected push failure")
+	}
+
+	config, readErr := os.ReadFile(filepath.Join(cloneDir, ".git", "config"))
+	if readErr != nil {
+		t.Fatalf("kept working directory has no git config: %v", readErr)
+	}
+	if strings.Contains(string(config), "ghp_secrettoken123") {
+		t.Errorf("kept git config still contains the token:\n%s", config)
+	}
+	for _, want := range []string{"https://github.com/test/repo.git", "https://github.com/test/mirror.git"} {
+		if !strings.Contains(string(config), want) {
+			t.Errorf("kept git config is missing remote %s:\n%s", want, config)
+		}
+	}
+}
+
+func TestCloneRepositoryReportsCleanupFailure(t *testing.T) {
+	originalRunGitCommand := runGitCommand
+	originalRemoveAll := removeAll
+	defer func() {
+		runGitCommand = originalRunGitCommand
+		removeAll = originalRemoveAll
+	}()
+
+	var logBuf bytes.Buffer
+	log.SetOutput(&logBuf)
+	defer log.SetOutput(os.Stderr)
+
+	runGitCommand = func(dir string, token string, args ...string) error {
+		return nil
+	}
+	var removed string
+	removeAll = func(path string) error {
+		removed = path
+		os.RemoveAll(path)
+		return fmt.Errorf("device busy")
+	}
+
+	tracker := &mockProgressTracker{}
+	err := CloneRepository(CloneOptions{
+		SourceURL: "https://github.com/test/repo.git",
+		TargetURL: "https://github.com/test/mirror.git",
+		TempDir:   t.TempDir(),
+		Progress:  tracker,
+	})
+	if err == nil || !strings.Contains(err.Error(), "device busy") {
+		t.Fatalf("CloneRepository() error = %v, want cleanup failure", err)
+	}
+	if tracker.lastError == nil {
+		t.Error("expected cleanup failure to be reported to progress")
+	}
+	if !strings.Contains(logBuf.String(), removed) {
+		t.Errorf("expected log to name %s, got %q", removed, logBuf.String())
+	}
+}
+
+func TestCleanupTempDirs(t *testing.T) {
+	dir := t.TempDir()
+	old := time.Now().Add(-48 * time.Hour)
+
+	stale := filepath.Join(dir, "gitclone-stale")
+	fresh := filepath.Join(dir, "gitclone-fresh")
+	other := filepath.Join(dir, "unrelated")
+	for _, d := range []string{stale, fresh, other} {
+		if err := os.MkdirAll(filepath.Join(d, ".git"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, d := range []string{stale, other} {
+		if err := os.Chtimes(d, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := CleanupTempDirs(dir, 24*time.Hour); err != nil {
+		t.Fatalf("CleanupTempDirs() unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("expected stale clone to be removed, stat error: %v", err)
+	}
+	for _, d := range []string{fresh, other} {
+		if _, err := os.Stat(d); err != nil {
+			t.Errorf("expected %s to be kept: %v", d, err)
+		}
+	}
+}
+
+func TestCleanupTempDirsJoinsErrors(t *testing.T) {
+	originalRemoveAll := removeAll
+	defer func() {
+		removeAll = originalRemoveAll
+	}()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	dir := t.TempDir()
+	old := time.Now().Add(-48 * time.Hour)
+	for _, name := range []string{"gitclone-a", "gitclone-b"} {
+		path := filepath.Join(dir, name)
+		if err := os.Mkdir(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removeAll = func(path string) error {
+		return fmt.Errorf("cannot remove %s", filepath.Base(path))
+	}
+
+	err := CleanupTempDirs(dir, time.Hour)
+	if err == nil {
+		t.Fatal("CleanupTempDirs() expected error")
+	}
+	for _, name := range []string{"gitclone-a", "gitclone-b"} {
+		if !strings.Contains(err.Error(), "cannot remove "+name) {
+			t.Errorf("expected error to mention %s, got %q", name, err.Error())
+		}
+	}
+}
+
+func TestCloneRepositoryOperationName(t *testing.T) {
+	originalRunGitCommand := runGitCommand
+	defer func() {
+		runGitCommand = originalRunGitCommand
+	}()
+	runGitCommand = func(dir string, token string, args ...string) error {
+		return nil
+	}
+
+	tests := []struct {
+		name string
+		opts CloneOptions
+		want string
+	}{
+		{
+			name: "source repository",
+			opts: CloneOptions{SourceURL: "https://github.com/owner/repo.git", WorkingDir: "repo"},
+			want: "Clone owner/repo",
+		},
+		{
+			name: "explicit name",
+			opts: CloneOptions{SourceURL: "https://github.com/owner/repo.git", WorkingDir: "repo", OperationName: "Backup"},
+			want: "Backup",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := &mockProgressTracker{}
+			tt.opts.Progress = tracker
+			if err := CloneRepository(tt.opts); err != nil {
+				t.Fatalf("CloneRepository() unexpected error: %v", err)
+			}
+			if tracker.operation == nil || tracker.operation.Name != tt.want {
+				t.Errorf("started operation = %+v, want %q", tracker.operation, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepoLabel(t *testing.T) {
+	tests := []struct {
+		location string
+		want     string
+	}{
+		{"https://github.com/owner/repo.git", "owner/repo"},
+		{"https://gitlab.com/group/sub/project", "sub/project"},
+		{"file:///tmp/mirrors/repo.git", "mirrors/repo"},
+		{"/srv/work/repo", "work/repo"},
+		{"repo", "repo"},
+	}
+	for _, tt := range tests {
+		if got := repoLabel(tt.location); got != tt.want {
+			t.Errorf("repoLabel(%q) = %q, want %q", tt.location, got, tt.want)
+		}
+	}
+}
+
+// stepTracker records every progress update
+type stepTracker struct {
+	mockProgressTracker
+	updates []string
+}
+
+func (s *stepTracker) Update(current, total int64) {
+	s.updates = append(s.updates, fmt.Sprintf("%d/%d", current, total))
+}
+
+func TestCloneRepositoryStepProgress(t *testing.T) {
+	originalRunGitCommand := runGitCommand
+	defer func() {
+		runGitCommand = originalRunGitCommand
+	}()
+	stubHasCommits(t, true)
+
+	tests := []struct {
+		name        string
+		opts        CloneOptions
+		failOn      string
+		wantUpdates []string
+	}{
+		{
+			name:        "mirror",
+			opts:        CloneOptions{TargetURL: "https://github.com/test/mirror.git"},
+			wantUpdates: []string{"1/3", "2/3", "3/3"},
+		},
+		{
+			name:        "working directory",
+			opts:        CloneOptions{WorkingDir: "repo"},
+			wantUpdates: []string{"1/1"},
+		},
+		{
+			name:        "push failure stops progress",
+			opts:        CloneOptions{TargetURL: "https://github.com/test/mirror.git"},
+			failOn:      "push",
+			wantUpdates: []string{"1/3", "2/3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runGitCommand = func(dir string, token string, args ...string) error {
+				if args[0] == tt.failOn {
+					return fmt.Errorf("remote rejected")
+				}
+				return nil
+			}
+
+			tracker := &stepTracker{}
+			opts := tt.opts
+			opts.SourceURL = "https://github.com/test/repo.git"
+			opts.Progress = tracker
+			err := CloneRepository(opts)
+			if (err != nil) != (tt.failOn != "") {
+				t.Fatalf("CloneRepository() error = %v", err)
+			}
+
+			if strings.Join(tracker.updates, " ") != strings.Join(tt.wantUpdates, " ") {
+				t.Errorf("progress updates = %q, want %q", tracker.updates, tt.wantUpdates)
+			}
+		})
+	}
+
+	// PushRepository reports its own two steps
+	runGitCommand = func(dir string, token string, args ...string) error {
+		return nil
+	}
+	tracker := &stepTracker{}
+	if err := PushRepository("/work/clone", CloneOptions{TargetURL: "https://github.com/test/mirror.git", Progress: tracker}); err != nil {
+		t.Fatalf("PushRepository() unexpected error: %v", err)
+	}
+	if got := strings.Join(tracker.updates, " "); got != "1/2 2/2" {
+		t.Errorf("push progress updates = %q, want \"1/2 2/2\"", got)
+	}
+}
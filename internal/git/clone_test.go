@@ -1,7 +1,17 @@
 package git
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 )
 
 func mockRunGitCommand(shouldFail bool) func(string, string, ...string) error {
@@ -72,3 +82,248 @@ func TestCloneRepository(t *testing.T) {
 		})
 	}
 }
+
+func TestSigningArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		gpgSign bool
+		key     string
+		want    string
+	}{
+		{name: "disabled", gpgSign: false, key: "ABCDEF12", want: ""},
+		{name: "default key", gpgSign: true, want: "-c commit.gpgsign=true"},
+		{name: "explicit key", gpgSign: true, key: "ABCDEF12", want: "-c commit.gpgsign=true -c user.signingkey=ABCDEF12"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.Join(SigningArgs(tt.gpgSign, tt.key), " ")
+			if got != tt.want {
+				t.Errorf("SigningArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunGitCommandArchivedPush(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	// Mimic GitHub's response to a push to an archived repository
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("This repository was archived so it is read-only.\n"))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	t.Setenv("GIT_TERMINAL_PROMPT", "0")
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "initial"},
+	} {
+		if err := runGitCommand(dir, "", args...); err != nil {
+			t.Fatalf("git %s: %v", args[0], err)
+		}
+	}
+
+	err := runGitCommand(dir, "", "push", server.URL+"/owner/repo.git", "HEAD:refs/heads/main")
+	if !errors.Is(err, ErrRepositoryArchived) {
+		t.Fatalf("runGitCommand() error = %v, want ErrRepositoryArchived", err)
+	}
+	if !strings.Contains(err.Error(), "unarchive it before pushing") {
+		t.Errorf("Expected error to explain how to fix the push, got %q", err.Error())
+	}
+}
+
+func TestRunGitCommandLeaseRejected(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	base := t.TempDir()
+	target := filepath.Join(base, "target.git")
+	local := filepath.Join(base, "local")
+	other := filepath.Join(base, "other")
+	commit := []string{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m"}
+
+	for _, step := range []struct {
+		dir  string
+		args []string
+	}{
+		{base, []string{"init", "-q", "--bare", target}},
+		{base, []string{"clone", "-q", target, local}},
+		{local, append(commit, "initial")},
+		{local, []string{"push", "-q", "origin", "HEAD:refs/heads/main"}},
+		{base, []string{"clone", "-q", "--branch", "main", target, other}},
+		// Someone else pushes after local last fetched
+		{other, append(commit, "concurrent")},
+		{other, []string{"push", "-q", "origin", "main"}},
+		{local, append(commit, "rewrite", "--amend")},
+	} {
+		if err := runGitCommand(step.dir, "", step.args...); err != nil {
+			t.Fatalf("git %s: %v", strings.Join(step.args, " "), err)
+		}
+	}
+
+	err := runGitCommand(local, "", "push", "origin", "HEAD:refs/heads/main", "--force-with-lease")
+	if !errors.Is(err, ErrPushLeaseRejected) {
+		t.Fatalf("runGitCommand() error = %v, want ErrPushLeaseRejected", err)
+	}
+}
+
+func TestIsLeaseRejectedError(t *testing.T) {
+	tests := []struct {
+		stderr string
+		want   bool
+	}{
+		{"To ../target.git\n ! [rejected]        HEAD -> main (stale info)\nerror: failed to push some refs", true},
+		{"To ../target.git\n ! [rejected]        main -> main (fetch first)\nerror: failed to push some refs", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := isLeaseRejectedError(tt.stderr); got != tt.want {
+			t.Errorf("isLeaseRejectedError(%q) = %v, want %v", tt.stderr, got, tt.want)
+		}
+	}
+}
+
+// fakeGit puts a git script on PATH that fails with a low-speed abort
+// until it has been run succeedOn times, recording each run's environment
+func fakeGit(t *testing.T, succeedOn int) (countFile string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake git requires a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	countFile = filepath.Join(dir, "count")
+	script := fmt.Sprintf(`#!/bin/sh
+n=$(cat %[1]q 2>/dev/null || echo 0)
+n=$((n+1))
+echo $n > %[1]q
+echo "$GIT_HTTP_LOW_SPEED_LIMIT $GIT_HTTP_LOW_SPEED_TIME" > %[2]q
+if [ "$n" -lt %[3]d ]; then
+	echo "error: RPC failed; curl 28 Operation too slow. Less than 1000 bytes/sec transferred the last 60 seconds" >&2
+	exit 128
+fi
+`, countFile, filepath.Join(dir, "env"), succeedOn)
+	if err := os.WriteFile(filepath.Join(dir, "git"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return countFile
+}
+
+func TestRunGitCommandStalledTransfer(t *testing.T) {
+	originalRetryDelay := retryDelay
+	defer func() { retryDelay = originalRetryDelay }()
+	retryDelay = time.Millisecond
+
+	// A stall is retried and the next attempt succeeds
+	countFile := fakeGit(t, 2)
+	if err := runGitCommand(t.TempDir(), "", "fetch", "origin"); err != nil {
+		t.Fatalf("runGitCommand() unexpected error: %v", err)
+	}
+	if got := readTrimmed(t, countFile); got != "2" {
+		t.Errorf("git ran %s times, want 2", got)
+	}
+	env := readTrimmed(t, filepath.Join(filepath.Dir(countFile), "env"))
+	if env != fmt.Sprintf("%d %d", DefaultLowSpeedLimit, int(DefaultLowSpeedTime.Seconds())) {
+		t.Errorf("low-speed environment = %q, want the defaults", env)
+	}
+
+	// A transfer that keeps stalling gives up with ErrTransferStalled
+	countFile = fakeGit(t, maxRetries+1)
+	err := runGitCommand(t.TempDir(), "", "fetch", "origin")
+	if !errors.Is(err, ErrTransferStalled) {
+		t.Fatalf("runGitCommand() error = %v, want ErrTransferStalled", err)
+	}
+	if got := readTrimmed(t, countFile); got != fmt.Sprint(maxRetries) {
+		t.Errorf("git ran %s times, want %d", got, maxRetries)
+	}
+
+	// Explicit limits take precedence over the defaults
+	countFile = fakeGit(t, 1)
+	args := append(StallArgs(500, 30*time.Second), "fetch", "origin")
+	if err := runGitCommand(t.TempDir(), "", args...); err != nil {
+		t.Fatalf("runGitCommand() unexpected error: %v", err)
+	}
+	if env := readTrimmed(t, filepath.Join(filepath.Dir(countFile), "env")); env != "" {
+		t.Errorf("low-speed environment = %q, want none with explicit limits", env)
+	}
+}
+
+func TestRunGitCommandEnvironment(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake git requires a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	envFile := filepath.Join(dir, "env")
+	script := fmt.Sprintf("#!/bin/sh\nenv > %q\n", envFile)
+	if err := os.WriteFile(filepath.Join(dir, "git"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	if err := runGitCommand(t.TempDir(), "test-token", "fetch", "origin"); err != nil {
+		t.Fatalf("runGitCommand() unexpected error: %v", err)
+	}
+	env := "\n" + readTrimmed(t, envFile) + "\n"
+	for _, want := range []string{"GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=", "GIT_CREDENTIAL_HELPER="} {
+		if !strings.Contains(env, "\n"+want+"\n") {
+			t.Errorf("git environment is missing %s", want)
+		}
+	}
+	// Test identities and TLS overrides never apply outside tests
+	for _, unwanted := range []string{"GIT_SSL_NO_VERIFY=", "GIT_AUTHOR_NAME=test"} {
+		if strings.Contains(env, "\n"+unwanted) {
+			t.Errorf("git environment unexpectedly has %s", unwanted)
+		}
+	}
+}
+
+func readTrimmed(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func TestStallArgs(t *testing.T) {
+	if got := strings.Join(StallArgs(0, 0), " "); got != "-c http.lowSpeedLimit=1000 -c http.lowSpeedTime=60" {
+		t.Errorf("StallArgs(0, 0) = %q", got)
+	}
+	if got := strings.Join(StallArgs(500, 30*time.Second), " "); got != "-c http.lowSpeedLimit=500 -c http.lowSpeedTime=30" {
+		t.Errorf("StallArgs(500, 30s) = %q", got)
+	}
+	if got := strings.Join(networkArgs(CloneOptions{LowSpeedTime: 10 * time.Second}, "fetch"), " "); got != "-c http.lowSpeedLimit=1000 -c http.lowSpeedTime=10 fetch" {
+		t.Errorf("networkArgs() = %q", got)
+	}
+	if got := strings.Join(networkArgs(CloneOptions{}, "fetch"), " "); got != "fetch" {
+		t.Errorf("networkArgs() without limits = %q", got)
+	}
+}
+
+func TestIsArchivedError(t *testing.T) {
+	tests := []struct {
+		stderr string
+		want   bool
+	}{
+		{"remote: This repository was archived so it is read-only.\nfatal: unable to access 'https://github.com/o/r.git/': The requested URL returned error: 403", true},
+		{"fatal: unable to access 'https://github.com/o/r.git/': The requested URL returned error: 403", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := isArchivedError(tt.stderr); got != tt.want {
+			t.Errorf("isArchivedError(%q) = %v, want %v", tt.stderr, got, tt.want)
+		}
+	}
+}
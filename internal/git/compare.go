@@ -0,0 +1,118 @@
+package git
+
+import (
+	stderrors "errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/NicabarNimble/go-gittools/internal/errors"
+)
+
+// BranchUpToDate reports whether branch points at the same commit in
+// opts.SourceURL and opts.TargetURL, in which case pushing it would change
+// nothing. Both repositories are queried with ls-remote, so nothing is
+// cloned. A branch missing from either repository is not up to date.
+func BranchUpToDate(opts CloneOptions, branch string) (bool, error) {
+	if opts.SourceURL == "" || opts.TargetURL == "" {
+		return false, errors.New("compare", fmt.Errorf("source and target URLs must be specified"))
+	}
+	if err := validateTargetURL(opts.TargetURL); err != nil {
+		return false, err
+	}
+
+	ref := "refs/heads/" + branch
+	source, err := remoteRef(opts.SourceURL, opts.Token, ref)
+	if err != nil {
+		return false, errors.New("compare", fmt.Errorf("failed to list source refs: %w", err))
+	}
+	target, err := remoteRef(opts.TargetURL, opts.Token, ref)
+	if err != nil {
+		return false, errors.New("compare", fmt.Errorf("failed to list target refs: %w", err))
+	}
+	return source != "" && source == target, nil
+}
+
+// remoteRef returns the object ref points at in the repository at rawURL,
+// or "" if it does not exist
+func remoteRef(rawURL, token, ref string) (string, error) {
+	refs, err := FetchRefs(rawURL, token)
+	if err != nil {
+		return "", err
+	}
+	return refs[ref], nil
+}
+
+// FetchRefs lists the refs of the repository at sourceURL, mapping each
+// full ref name, such as "refs/heads/main", to the SHA it points at. It
+// runs git ls-remote, so nothing is cloned or fetched. Peeled tag entries
+// are omitted. token authenticates HTTPS URLs and never appears in errors.
+func FetchRefs(sourceURL, token string) (map[string]string, error) {
+	if sourceURL == "" {
+		return nil, errors.New("compare", fmt.Errorf("source URL must be specified"))
+	}
+	remote, err := authURL(sourceURL, token)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := gitRefs("", remote)
+	if err != nil {
+		return nil, errors.New("git-command", fmt.Errorf("failed to list refs of %s: %s", redactToken(sourceURL, token), lsRemoteError(err, token)))
+	}
+	return refs, nil
+}
+
+// lsRemoteError describes a failed ls-remote, including git's stderr, with
+// token redacted. git echoes the remote URL, credentials included, in many
+// of its messages.
+func lsRemoteError(err error, token string) string {
+	msg := err.Error()
+	var exitErr *exec.ExitError
+	if stderrors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		msg += ": " + strings.TrimSpace(string(exitErr.Stderr))
+	}
+	return redactToken(msg, token)
+}
+
+// redactToken replaces every occurrence of token in s
+func redactToken(s, token string) string {
+	if token == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, token, "[REDACTED]")
+}
+
+// AheadBehind counts the commits head has that base lacks (ahead) and those
+// base has that head lacks (behind) in the local repository at dir. Both
+// may be any revision git understands, such as a branch, a remote-tracking
+// branch or a SHA. Unlike the compare API this works offline and costs no
+// rate limit.
+func AheadBehind(dir, base, head string) (ahead, behind int, err error) {
+	if base == "" || head == "" {
+		return 0, 0, errors.New("compare", fmt.Errorf("base and head must be specified"))
+	}
+	if strings.HasPrefix(base, "-") || strings.HasPrefix(head, "-") {
+		return 0, 0, errors.New("compare", fmt.Errorf("invalid revision range %s...%s", base, head))
+	}
+
+	cmd := exec.Command("git", "rev-list", "--left-right", "--count", base+"..."+head)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, 0, errors.New("git-command", fmt.Errorf("git rev-list failed: %w", err))
+	}
+
+	// The left side counts commits only reachable from base
+	fields := strings.Fields(string(out))
+	if len(fields) != 2 {
+		return 0, 0, errors.New("git-command", fmt.Errorf("unexpected git rev-list output %q", out))
+	}
+	if behind, err = strconv.Atoi(fields[0]); err != nil {
+		return 0, 0, errors.New("git-command", fmt.Errorf("unexpected git rev-list output %q", out))
+	}
+	if ahead, err = strconv.Atoi(fields[1]); err != nil {
+		return 0, 0, errors.New("git-command", fmt.Errorf("unexpected git rev-list output %q", out))
+	}
+	return ahead, behind, nil
+}
@@ -0,0 +1,229 @@
+package git
+
+import (
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBranchUpToDate(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	base := t.TempDir()
+	work := filepath.Join(base, "work")
+	source := filepath.Join(base, "source.git")
+	target := filepath.Join(base, "target.git")
+	commit := []string{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m"}
+
+	run := func(dir string, args ...string) {
+		t.Helper()
+		if err := runGitCommand(dir, "", args...); err != nil {
+			t.Fatalf("git %s: %v", strings.Join(args, " "), err)
+		}
+	}
+	run(base, "init", "-q", "-b", "main", work)
+	run(work, append(commit, "initial")...)
+	run(base, "clone", "-q", "--bare", work, source)
+	run(base, "clone", "-q", "--bare", work, target)
+
+	opts := CloneOptions{SourceURL: "file://" + source, TargetURL: "file://" + target}
+	check := func(branch string, want bool) {
+		t.Helper()
+		got, err := BranchUpToDate(opts, branch)
+		if err != nil {
+			t.Fatalf("BranchUpToDate(%q) unexpected error: %v", branch, err)
+		}
+		if got != want {
+			t.Errorf("BranchUpToDate(%q) = %v, want %v", branch, got, want)
+		}
+	}
+
+	check("main", true)
+	check("missing", false)
+
+	// A new commit on the source leaves the target behind
+	run(work, append(commit, "change")...)
+	run(work, "push", "-q", source, "main")
+	check("main", false)
+
+	if _, err := BranchUpToDate(CloneOptions{SourceURL: opts.SourceURL}, "main"); err == nil {
+		t.Error("BranchUpToDate() without a target URL should fail")
+	}
+}
+
+func TestAuthURL(t *testing.T) {
+	tests := []struct {
+		rawURL string
+		token  string
+		want   string
+	}{
+		{"https://github.com/owner/repo.git", "", "https://github.com/owner/repo.git"},
+		{"file:///tmp/repo.git", "secret", "file:///tmp/repo.git"},
+		{"https://github.com/owner/repo.git", "secret", "https://secret@github.com/owner/repo.git"},
+	}
+
+	for _, tt := range tests {
+		got, err := authURL(tt.rawURL, tt.token)
+		if err != nil {
+			t.Fatalf("authURL(%q) unexpected error: %v", tt.rawURL, err)
+		}
+		if got != tt.want {
+			t.Errorf("authURL(%q, %q) = %q, want %q", tt.rawURL, tt.token, got, tt.want)
+		}
+	}
+}
+
+func TestAheadBehind(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	commit := []string{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m"}
+	run := func(args ...string) {
+		t.Helper()
+		if err := runGitCommand(dir, "", args...); err != nil {
+			t.Fatalf("git %s: %v", strings.Join(args, " "), err)
+		}
+	}
+	run("init", "-q", "-b", "main")
+	run(append(commit, "initial")...)
+	run("branch", "base")
+	run(append(commit, "first")...)
+	run(append(commit, "second")...)
+
+	tests := []struct {
+		base, head            string
+		wantAhead, wantBehind int
+	}{
+		{"base", "main", 2, 0},
+		{"main", "base", 0, 2},
+		{"main", "main", 0, 0},
+	}
+	for _, tt := range tests {
+		ahead, behind, err := AheadBehind(dir, tt.base, tt.head)
+		if err != nil {
+			t.Fatalf("AheadBehind(%q, %q) unexpected error: %v", tt.base, tt.head, err)
+		}
+		if ahead != tt.wantAhead || behind != tt.wantBehind {
+			t.Errorf("AheadBehind(%q, %q) = %d, %d, want %d, %d", tt.base, tt.head, ahead, behind, tt.wantAhead, tt.wantBehind)
+		}
+	}
+
+	if _, _, err := AheadBehind(dir, "main", "missing"); err == nil {
+		t.Error("AheadBehind() with an unknown revision should fail")
+	}
+	if _, _, err := AheadBehind(dir, "--all", "main"); err == nil {
+		t.Error("AheadBehind() should reject revisions that look like options")
+	}
+}
+
+func TestListBranches(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	base := t.TempDir()
+	work := filepath.Join(base, "work")
+	mirror := filepath.Join(base, "mirror.git")
+	run := func(dir string, args ...string) {
+		t.Helper()
+		if err := runGitCommand(dir, "", args...); err != nil {
+			t.Fatalf("git %s: %v", strings.Join(args, " "), err)
+		}
+	}
+	run(base, "init", "-q", "-b", "main", work)
+
+	branches, err := ListBranches(work)
+	if err != nil {
+		t.Fatalf("ListBranches() on an empty repository unexpected error: %v", err)
+	}
+	if len(branches) != 0 {
+		t.Errorf("ListBranches() on an empty repository = %v, want none", branches)
+	}
+
+	run(work, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "initial")
+	run(work, "branch", "release/1.0")
+	run(work, "branch", "develop")
+	run(base, "clone", "-q", "--mirror", work, mirror)
+
+	want := []string{"develop", "main", "release/1.0"}
+	for _, dir := range []string{work, mirror} {
+		branches, err := ListBranches(dir)
+		if err != nil {
+			t.Fatalf("ListBranches(%q) unexpected error: %v", dir, err)
+		}
+		if !reflect.DeepEqual(branches, want) {
+			t.Errorf("ListBranches(%q) = %v, want %v", dir, branches, want)
+		}
+	}
+
+	if _, err := ListBranches(filepath.Join(base, "missing")); err == nil {
+		t.Error("ListBranches() outside a repository should fail")
+	}
+}
+
+func TestParseLsRemote(t *testing.T) {
+	out := "8d3f0c1c1a8f2c3a6e0d5b4a7f9e8d7c6b5a4f3e\tHEAD\n" +
+		"8d3f0c1c1a8f2c3a6e0d5b4a7f9e8d7c6b5a4f3e\trefs/heads/main\n" +
+		"1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b\trefs/heads/release/1.0\n" +
+		"9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d4c3b2a1f0e\trefs/tags/v1.0\n" +
+		"8d3f0c1c1a8f2c3a6e0d5b4a7f9e8d7c6b5a4f3e\trefs/tags/v1.0^{}\n"
+
+	want := map[string]string{
+		"HEAD":                   "8d3f0c1c1a8f2c3a6e0d5b4a7f9e8d7c6b5a4f3e",
+		"refs/heads/main":        "8d3f0c1c1a8f2c3a6e0d5b4a7f9e8d7c6b5a4f3e",
+		"refs/heads/release/1.0": "1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b",
+		"refs/tags/v1.0":         "9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d4c3b2a1f0e",
+	}
+	if got := parseLsRemote([]byte(out)); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseLsRemote() = %v, want %v", got, want)
+	}
+	if got := parseLsRemote(nil); len(got) != 0 {
+		t.Errorf("parseLsRemote(nil) = %v, want no refs", got)
+	}
+}
+
+func TestFetchRefs(t *testing.T) {
+	originalGitRefs := gitRefs
+	defer func() { gitRefs = originalGitRefs }()
+
+	var gotRemote string
+	gitRefs = func(dir, remote string) (map[string]string, error) {
+		gotRemote = remote
+		return parseLsRemote([]byte("abc123\trefs/heads/main\n")), nil
+	}
+
+	refs, err := FetchRefs("https://github.com/owner/repo.git", "secret")
+	if err != nil {
+		t.Fatalf("FetchRefs() unexpected error: %v", err)
+	}
+	if want := "https://secret@github.com/owner/repo.git"; gotRemote != want {
+		t.Errorf("FetchRefs() listed %q, want %q", gotRemote, want)
+	}
+	if want := map[string]string{"refs/heads/main": "abc123"}; !reflect.DeepEqual(refs, want) {
+		t.Errorf("FetchRefs() = %v, want %v", refs, want)
+	}
+
+	gitRefs = func(dir, remote string) (map[string]string, error) {
+		return nil, &exec.ExitError{Stderr: []byte("fatal: unable to access '" + remote + "': The requested URL returned error: 403\n")}
+	}
+	_, err = FetchRefs("https://github.com/owner/repo.git", "secret")
+	if err == nil {
+		t.Fatal("FetchRefs() should fail when ls-remote fails")
+	}
+	if strings.Contains(err.Error(), "secret") {
+		t.Errorf("FetchRefs() error leaks the token: %v", err)
+	}
+	if !strings.Contains(err.Error(), "returned error: 403") {
+		t.Errorf("FetchRefs() error = %v, want git's stderr", err)
+	}
+
+	if _, err := FetchRefs("", "secret"); err == nil {
+		t.Error("FetchRefs() without a URL should fail")
+	}
+}
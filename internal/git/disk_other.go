@@ -0,0 +1,9 @@
+//go:build !linux && !darwin && !freebsd
+
+package git
+
+// availableDiskSpace is not implemented on this platform, so the disk-space
+// pre-check is skipped
+func availableDiskSpace(path string) (uint64, error) {
+	return 0, errDiskSpaceUnsupported
+}
@@ -9,12 +9,26 @@
 //
 // CloneOptions: Configuration struct for repository cloning operations.
 // Contains settings for source and target URLs, working directory,
-// authentication tokens, and progress tracking.
+// authentication tokens, progress tracking, and optional sparse
+// checkout paths for cloning only part of a repository.
 //
 // CloneRepository: Main function for cloning git repositories.
 // Handles the complete workflow of cloning from a source and
 // configuring the target remote.
 //
+// PushRepository: Pushes an existing clone to a target URL, used by
+// callers that modify the clone themselves between cloning and pushing.
+//
+// UpdateRepository: Incremental alternative to CloneRepository.
+// Fetches and fast-forwards an existing clone in the working
+// directory, falling back to a full clone when none exists.
+//
+// BranchUpToDate: Compares a branch between the source and target with
+// ls-remote, so callers can skip pushes that would change nothing.
+//
+// CleanupTempDirs: Removes intermediate clones left behind by interrupted
+// runs once they are older than a threshold.
+//
 // Example Usage:
 //
 //	opts := CloneOptions{
@@ -33,6 +47,16 @@
 //
 // All operations return detailed errors that can be handled by the caller.
 // Errors are wrapped with context about the operation that failed.
+// A push rejected because the target is archived matches
+// ErrRepositoryArchived with errors.Is.
+// Mirroring a source without commits fails with ErrEmptyRepository unless
+// SeedEmptyRepository is set.
+// With VerifyPush set, target refs that do not match the source after a
+// push are reported as ErrVerificationFailed.
+// Transfers that stay below the low-speed limit are retried and then
+// reported as ErrTransferStalled.
+// A --force-with-lease push refused because the target no longer matches
+// CloneOptions.LeaseRefs matches ErrPushLeaseRejected.
 // Progress tracking is integrated throughout operations to provide
 // real-time feedback.
 //
@@ -0,0 +1,170 @@
+package git
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// throttleProxy is a local HTTP proxy that limits the combined throughput
+// of everything passing through it. Git is pointed at it with http.proxy to
+// enforce CloneOptions.RateLimitKBps, which git cannot do itself. HTTPS is
+// tunnelled with CONNECT, so TLS stays end to end between git and the host.
+type throttleProxy struct {
+	listener net.Listener
+	server   *http.Server
+	limiter  *byteLimiter
+}
+
+// startThrottleProxy starts a proxy on a loopback port that passes at most
+// bytesPerSec bytes per second in total, in either direction
+func startThrottleProxy(bytesPerSec int) (*throttleProxy, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	p := &throttleProxy{listener: ln, limiter: newByteLimiter(bytesPerSec)}
+	p.server = &http.Server{Handler: p, ReadHeaderTimeout: 30 * time.Second}
+	go p.server.Serve(ln)
+	return p, nil
+}
+
+// URL returns the proxy's address in the form http.proxy expects
+func (p *throttleProxy) URL() string {
+	return "http://" + p.listener.Addr().String()
+}
+
+// Close stops accepting connections
+func (p *throttleProxy) Close() error {
+	return p.server.Close()
+}
+
+// ServeHTTP tunnels CONNECT requests and forwards plain HTTP ones
+func (p *throttleProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodConnect {
+		p.tunnel(w, r)
+		return
+	}
+	p.forward(w, r)
+}
+
+// tunnel relays the raw connection to r.Host, throttling both directions
+func (p *throttleProxy) tunnel(w http.ResponseWriter, r *http.Request) {
+	upstream, err := net.DialTimeout("tcp", r.Host, 30*time.Second)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		upstream.Close()
+		http.Error(w, "tunnelling not supported", http.StatusInternalServerError)
+		return
+	}
+	client, buffered, err := hijacker.Hijack()
+	if err != nil {
+		upstream.Close()
+		return
+	}
+	if _, err := io.WriteString(client, "HTTP/1.1 200 Connection Established\r\n\r\n"); err != nil {
+		client.Close()
+		upstream.Close()
+		return
+	}
+
+	// Closing both ends once either direction finishes unblocks the other
+	var once sync.Once
+	closeBoth := func() {
+		client.Close()
+		upstream.Close()
+	}
+	// The buffered reader holds anything the client sent after CONNECT
+	go func() {
+		io.Copy(upstream, p.limiter.reader(buffered.Reader))
+		once.Do(closeBoth)
+	}()
+	go func() {
+		io.Copy(client, p.limiter.reader(upstream))
+		once.Do(closeBoth)
+	}()
+}
+
+// forward sends a plain HTTP request on and relays the response
+func (p *throttleProxy) forward(w http.ResponseWriter, r *http.Request) {
+	out := r.Clone(r.Context())
+	out.RequestURI = ""
+	out.Header.Del("Proxy-Connection")
+	out.Header.Del("Proxy-Authorization")
+	if r.Body != nil {
+		out.Body = io.NopCloser(p.limiter.reader(r.Body))
+	}
+
+	resp, err := http.DefaultTransport.RoundTrip(out)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	for key, values := range resp.Header {
+		for _, v := range values {
+			w.Header().Add(key, v)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	io.Copy(w, p.limiter.reader(resp.Body))
+}
+
+// byteLimiter paces reads so that all readers sharing it pass at most rate
+// bytes per second together
+type byteLimiter struct {
+	rate  float64
+	chunk int // Largest read, so pacing stays smooth at low rates
+
+	mu   sync.Mutex
+	next time.Time // When the next read may return
+}
+
+func newByteLimiter(bytesPerSec int) *byteLimiter {
+	chunk := bytesPerSec / 10
+	if chunk < 512 {
+		chunk = 512
+	}
+	return &byteLimiter{rate: float64(bytesPerSec), chunk: chunk}
+}
+
+// wait blocks until n more bytes fit within the rate
+func (l *byteLimiter) wait(n int) {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(time.Duration(float64(n) / l.rate * float64(time.Second)))
+	l.mu.Unlock()
+	time.Sleep(delay)
+}
+
+// reader returns r paced by the limiter
+func (l *byteLimiter) reader(r io.Reader) io.Reader {
+	return &limitedReader{r: r, l: l}
+}
+
+type limitedReader struct {
+	r io.Reader
+	l *byteLimiter
+}
+
+func (lr *limitedReader) Read(p []byte) (int, error) {
+	if len(p) > lr.l.chunk {
+		p = p[:lr.l.chunk]
+	}
+	n, err := lr.r.Read(p)
+	if n > 0 {
+		lr.l.wait(n)
+	}
+	return n, err
+}
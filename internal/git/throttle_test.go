@@ -0,0 +1,130 @@
+package git
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestThrottleProxyLimitsRate(t *testing.T) {
+	payload := make([]byte, 30*1024)
+	if _, err := rand.Read(payload); err != nil {
+		t.Fatal(err)
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(payload)
+	})
+
+	for _, tt := range []struct {
+		name   string
+		server *httptest.Server
+	}{
+		{name: "http", server: httptest.NewServer(handler)},
+		{name: "https via CONNECT", server: httptest.NewTLSServer(handler)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			defer tt.server.Close()
+
+			// 30 KB at 20 KB/s takes about 1.5s; unthrottled it is instant
+			proxy, err := startThrottleProxy(20 * 1024)
+			if err != nil {
+				t.Fatalf("startThrottleProxy() error = %v", err)
+			}
+			defer proxy.Close()
+			proxyURL, _ := url.Parse(proxy.URL())
+
+			transport := tt.server.Client().Transport.(*http.Transport).Clone()
+			transport.Proxy = http.ProxyURL(proxyURL)
+			client := &http.Client{Transport: transport, Timeout: 30 * time.Second}
+
+			start := time.Now()
+			resp, err := client.Get(tt.server.URL)
+			if err != nil {
+				t.Fatalf("GET through proxy failed: %v", err)
+			}
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			elapsed := time.Since(start)
+
+			if err != nil || !bytes.Equal(body, payload) {
+				t.Fatalf("body through proxy differs (%d bytes, err %v)", len(body), err)
+			}
+			if elapsed < time.Second {
+				t.Errorf("transfer took %s, want at least 1s at 20 KB/s", elapsed)
+			}
+		})
+	}
+}
+
+func TestRateLimitedClone(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("no_proxy", "")
+	t.Setenv("NO_PROXY", "")
+
+	// A repository with an incompressible file, served over git's dumb
+	// HTTP protocol
+	base := t.TempDir()
+	work := filepath.Join(base, "work")
+	blob := make([]byte, 40*1024)
+	if _, err := rand.Read(blob); err != nil {
+		t.Fatal(err)
+	}
+	run := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run(base, "init", "-q", "-b", "main", work)
+	if err := os.WriteFile(filepath.Join(work, "data.bin"), blob, 0644); err != nil {
+		t.Fatal(err)
+	}
+	run(work, "add", "-A")
+	run(work, "commit", "-q", "-m", "data")
+	run(base, "clone", "-q", "--bare", work, filepath.Join(base, "repo.git"))
+	run(filepath.Join(base, "repo.git"), "update-server-info")
+
+	var served atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served.Add(1)
+		http.FileServer(http.Dir(base)).ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	proxy, err := startThrottleProxy(20 * 1024)
+	if err != nil {
+		t.Fatalf("startThrottleProxy() error = %v", err)
+	}
+	defer proxy.Close()
+
+	opts := CloneOptions{RateLimitKBps: 20, proxyURL: proxy.URL()}
+	start := time.Now()
+	args := networkArgs(opts, "clone", "-q", server.URL+"/repo.git", filepath.Join(base, "clone"))
+	if err := runGitCommand(base, "", args...); err != nil {
+		t.Fatalf("rate-limited clone failed: %v", err)
+	}
+	elapsed := time.Since(start)
+
+	if served.Load() == 0 {
+		t.Fatal("clone did not reach the server")
+	}
+	if _, err := os.Stat(filepath.Join(base, "clone", "data.bin")); err != nil {
+		t.Errorf("clone is missing data.bin: %v", err)
+	}
+	if elapsed < time.Second {
+		t.Errorf("40 KB clone took %s, want at least 1s at 20 KB/s", elapsed)
+	}
+}
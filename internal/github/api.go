@@ -4,18 +4,30 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/NicabarNimble/go-gittools/internal/progress"
 	"github.com/NicabarNimble/go-gittools/internal/token"
 )
 
 const (
 	apiBaseURL = "https://api.github.com"
 	userAgent  = "go-gittools/1.0"
+
+	// defaultAccept is sent unless a request sets its own Accept header,
+	// such as a raw content or diff media type
+	defaultAccept = "application/vnd.github.v3+json"
+
+	// DefaultMaxLogBytes is the largest workflow log archive read into memory
+	// unless overridden with WithMaxLogBytes
+	DefaultMaxLogBytes int64 = 512 << 20
 )
 
 // UserInfo represents GitHub user information
@@ -27,10 +39,21 @@ type UserInfo struct {
 
 // Client handles GitHub API operations
 type Client struct {
-	httpClient *http.Client
-	token      string
-	baseURL    string // Allow custom base URL for testing
-	username   string // Cached username after validation
+	httpClient  *http.Client
+	token       string
+	baseURL     string // Allow custom base URL for testing
+	username    string // Cached username after validation
+	metrics     Metrics
+	trace       TraceFunc
+	maxLogBytes int64
+	debug       io.Writer
+	dryRun      io.Writer // Set by WithDryRun; mutating requests are only printed
+	maxRateWait time.Duration
+	// validatorCache is set by WithValidatorCache
+	validatorCache *ValidatorCache
+	skipValidation bool         // Set by WithSkipValidation
+	retryBudget    *RetryBudget // Set by WithRetryBudget
+	apiVersion     string       // Set by WithAPIVersion
 }
 
 // GitHubClient is an alias for Client to maintain backward compatibility
@@ -41,6 +64,7 @@ type WorkflowRun struct {
 	ID         int64     `json:"id"`
 	Status     string    `json:"status"`
 	Conclusion string    `json:"conclusion"`
+	HeadBranch string    `json:"head_branch"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 	LogsURL    string    `json:"logs_url"`
@@ -62,31 +86,101 @@ type PROptions struct {
 	Body  string `json:"body"`
 	Head  string `json:"head"`
 	Base  string `json:"base"`
+	Draft bool   `json:"draft,omitempty"` // Open for manual promotion to ready for review
+}
+
+// PullRequest is a pull request returned by the API
+type PullRequest struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	HTMLURL string `json:"html_url"`
+	State   string `json:"state"`  // "open" or "closed"
+	Merged  bool   `json:"merged"` // Only reported by GetPullRequest
+	// Mergeable is nil while GitHub is still computing it, and is only
+	// reported by GetPullRequest
+	Mergeable *bool `json:"mergeable"`
+	Head      PRRef `json:"head"`
+	Base      PRRef `json:"base"`
 }
 
-// NewClient creates a new GitHub API client with token validation
-func NewClient(ctx context.Context, t *token.Token) (*Client, error) {
+// PRRef is the head or base branch of a pull request
+type PRRef struct {
+	Label string `json:"label"` // "owner:branch"
+	Ref   string `json:"ref"`   // Branch name
+	SHA   string `json:"sha"`
+}
+
+// NewClient creates a new GitHub API client with token validation, which
+// WithSkipValidation turns off
+func NewClient(ctx context.Context, t *token.Token, opts ...ClientOption) (*Client, error) {
 	client := &Client{
 		httpClient: &http.Client{Timeout: 30 * time.Second},
 		token:      t.Value,
 		baseURL:    apiBaseURL,
 	}
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	if client.skipValidation {
+		return client, nil
+	}
+
+	if cached, ok := client.validatorCache.lookup(client.baseURL, t); ok {
+		client.username = cached.login
+		return client, nil
+	}
 
 	validator := &TokenValidator{baseURL: client.baseURL}
 	if err := validator.Validate(ctx, t); err != nil {
 		return nil, fmt.Errorf("token validation failed: %w", err)
 	}
 
-	// Get and cache username during client creation
-	userInfo, err := client.GetUserInfo(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get user info: %w", err)
+	// Get and cache username during client creation, unless the caller
+	// already knows it
+	if client.username == "" {
+		userInfo, err := client.GetUserInfo(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user info: %w", err)
+		}
+		client.username = userInfo.Login
 	}
-	client.username = userInfo.Login
+	client.validatorCache.store(client.baseURL, t, client.username)
 
 	return client, nil
 }
 
+// fallbackTokenEnvVars are checked in order when storage has no default
+// GitHub token, matching the gh CLI and GitHub Actions conventions
+var fallbackTokenEnvVars = []string{"GITHUB_TOKEN", "GH_TOKEN"}
+
+// NewClientFromStorage retrieves the token for a GitHub account from storage
+// and creates a validated client. An empty account selects the default token,
+// falling back to GITHUB_TOKEN and then GH_TOKEN when none is stored.
+func NewClientFromStorage(ctx context.Context, storage token.Storage, account string, opts ...ClientOption) (*Client, error) {
+	t, err := retrieveToken(ctx, storage, account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve GitHub token: %w", err)
+	}
+	return NewClient(ctx, &t, opts...)
+}
+
+// retrieveToken looks up the account's token in storage, applying the
+// environment fallback for the default account
+func retrieveToken(ctx context.Context, storage token.Storage, account string) (token.Token, error) {
+	t, err := storage.Retrieve(ctx, token.Key(token.ProviderGitHub, account))
+	if err == nil || !errors.Is(err, token.ErrTokenNotFound) || account != "" {
+		return t, err
+	}
+
+	for _, name := range fallbackTokenEnvVars {
+		if value := strings.TrimSpace(os.Getenv(name)); value != "" {
+			return token.Token{Value: value, CreatedAt: time.Now()}, nil
+		}
+	}
+	return token.Token{}, err
+}
+
 // GetUserInfo retrieves authenticated user information
 func (c *Client) GetUserInfo(ctx context.Context) (*UserInfo, error) {
 	url := fmt.Sprintf("%s/user", c.baseURL)
@@ -208,6 +302,53 @@ func (c *Client) GetWorkflowRun(ctx context.Context, owner, repo string, runID i
 	return &run, nil
 }
 
+// WithBaseURL points the client at a different API, such as a GitHub
+// Enterprise Server's "https://ghe.example.com/api/v3". Use
+// DetectAPIBaseURL to derive it from a repository URL.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithUsername supplies the login of the token's user, so NewClient skips
+// the GetUserInfo request it otherwise makes to look it up. Callers can
+// keep the value from GetUsername between runs, keyed by the token's
+// fingerprint.
+func WithUsername(login string) ClientOption {
+	return func(c *Client) {
+		c.username = login
+	}
+}
+
+// WithSkipValidation makes NewClient return without validating the token
+// or looking up its user, for air-gapped tests or when the API is rate
+// limiting. GetUsername then returns "" unless WithUsername is also given.
+// An invalid, expired or under-scoped token is only noticed when a later
+// request fails, possibly partway through an operation.
+func WithSkipValidation(skip bool) ClientOption {
+	return func(c *Client) {
+		c.skipValidation = skip
+	}
+}
+
+// WithAPIVersion sends version, such as "2022-11-28", as the
+// X-GitHub-Api-Version header of every request. Without it GitHub serves
+// its default version.
+func WithAPIVersion(version string) ClientOption {
+	return func(c *Client) {
+		c.apiVersion = version
+	}
+}
+
+// WithMaxLogBytes limits how many bytes GetWorkflowLogs reads before failing.
+// Values of zero or less keep DefaultMaxLogBytes.
+func WithMaxLogBytes(n int64) ClientOption {
+	return func(c *Client) {
+		c.maxLogBytes = n
+	}
+}
+
 // GetWorkflowLogs gets the logs for a workflow run
 func (c *Client) GetWorkflowLogs(ctx context.Context, owner, repo string, runID int64) ([]byte, error) {
 	url := fmt.Sprintf("%s/repos/%s/%s/actions/runs/%d/logs", c.baseURL, owner, repo, runID)
@@ -220,18 +361,95 @@ func (c *Client) GetWorkflowLogs(ctx context.Context, owner, repo string, runID
 	if err != nil {
 		return nil, fmt.Errorf("failed to get workflow logs: %w", err)
 	}
+	defer resp.Body.Close()
+
+	limit := c.maxLogBytes
+	if limit <= 0 {
+		limit = DefaultMaxLogBytes
+	}
 
-	logs, err := io.ReadAll(resp.Body)
+	// Read one byte past the limit so an oversized archive can be detected
+	logs, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
 	if err != nil {
 		return nil, fmt.Errorf("failed to read logs: %w", err)
 	}
+	if int64(len(logs)) > limit {
+		return nil, fmt.Errorf("workflow logs exceed the %d byte limit", limit)
+	}
 
 	return logs, nil
 }
 
-// ListWorkflowRuns lists recent workflow runs
+// DownloadWorkflowLogs streams the log archive for a workflow run to w,
+// reporting bytes read against the Content-Length header to tracker.
+// Unlike GetWorkflowLogs the archive is not buffered, so no size limit applies.
+// The total passed to Update is -1 when the server does not report a length.
+func (c *Client) DownloadWorkflowLogs(ctx context.Context, owner, repo string, runID int64, w io.Writer, tracker progress.Tracker) (int64, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/actions/runs/%d/logs", c.baseURL, owner, repo, runID)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.sendRequest(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get workflow logs: %w", err)
+	}
+	defer resp.Body.Close()
+
+	tracker.Start(fmt.Sprintf("Downloading logs for run %d", runID))
+	n, err := io.Copy(w, &progressReader{r: resp.Body, total: resp.ContentLength, tracker: tracker})
+	if err != nil {
+		err = fmt.Errorf("failed to download logs: %w", err)
+		tracker.Error(err)
+		return n, err
+	}
+	tracker.Complete()
+
+	return n, nil
+}
+
+// progressReader reports the running byte count to a tracker as it is read
+type progressReader struct {
+	r       io.Reader
+	read    int64
+	total   int64
+	tracker progress.Tracker
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.read += int64(n)
+		p.tracker.Update(p.read, p.total)
+	}
+	return n, err
+}
+
+// ListWorkflowRuns lists the runs of a workflow, following pagination
 func (c *Client) ListWorkflowRuns(ctx context.Context, owner, repo, workflowID string) ([]WorkflowRun, error) {
-	url := fmt.Sprintf("%s/repos/%s/%s/actions/workflows/%s/runs", c.baseURL, owner, repo, workflowID)
+	url := fmt.Sprintf("%s/repos/%s/%s/actions/workflows/%s/runs?per_page=100", c.baseURL, owner, repo, workflowID)
+	runs, err := Paginate(ctx, c, url, func(body []byte) ([]WorkflowRun, error) {
+		var response struct {
+			WorkflowRuns []WorkflowRun `json:"workflow_runs"`
+		}
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, err
+		}
+		return response.WorkflowRuns, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflow runs: %w", err)
+	}
+
+	return runs, nil
+}
+
+// LatestWorkflowRun returns the most recent run of a workflow, or nil if it
+// has never run. Only one run is requested, so unlike ListWorkflowRuns its
+// cost does not grow with the workflow's history.
+func (c *Client) LatestWorkflowRun(ctx context.Context, owner, repo, workflowID string) (*WorkflowRun, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/actions/workflows/%s/runs?per_page=1", c.baseURL, owner, repo, workflowID)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -239,8 +457,9 @@ func (c *Client) ListWorkflowRuns(ctx context.Context, owner, repo, workflowID s
 
 	resp, err := c.sendRequest(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list workflow runs: %w", err)
+		return nil, fmt.Errorf("failed to get latest workflow run: %w", err)
 	}
+	defer resp.Body.Close()
 
 	var response struct {
 		WorkflowRuns []WorkflowRun `json:"workflow_runs"`
@@ -248,8 +467,10 @@ func (c *Client) ListWorkflowRuns(ctx context.Context, owner, repo, workflowID s
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
-
-	return response.WorkflowRuns, nil
+	if len(response.WorkflowRuns) == 0 {
+		return nil, nil
+	}
+	return &response.WorkflowRuns[0], nil
 }
 
 // CreateRepository creates a new repository
@@ -265,13 +486,47 @@ func (c *Client) CreateRepository(ctx context.Context, opts RepoOptions) error {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	if _, err = c.sendRequest(req); err != nil {
+	resp, err := c.sendRequest(req)
+	if err != nil {
+		if isRepositoryExists(resp, err) {
+			return fmt.Errorf("failed to create repository %s: %w", opts.Name, ErrRepositoryExists)
+		}
 		return fmt.Errorf("failed to create repository: %w", err)
 	}
+	resp.Body.Close()
 
 	return nil
 }
 
+// GetDefaultBranch returns the default branch of an owner/repo repository
+func (c *Client) GetDefaultBranch(ctx context.Context, repoString string) (string, error) {
+	owner, repo, err := ParseRepo(repoString)
+	if err != nil {
+		return "", err
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/%s", c.baseURL, owner, repo)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.sendRequest(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get repository: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var repository struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&repository); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return repository.DefaultBranch, nil
+}
+
 // CreateFork creates a fork of a repository
 func (c *Client) CreateFork(ctx context.Context, repoString string) error {
 	owner, repo, err := ParseRepo(repoString)
@@ -292,10 +547,153 @@ func (c *Client) CreateFork(ctx context.Context, repoString string) error {
 	return nil
 }
 
+// PRFilter narrows the pull requests returned by ListPullRequests. Empty
+// fields are not filtered on.
+type PRFilter struct {
+	State string // "open", "closed" or "all"; GitHub defaults to "open"
+	Head  string // "user:branch" for pull requests from forks
+	Base  string // Branch the pull requests merge into
+}
+
+// ListPullRequests lists the pull requests of a repository matching filter,
+// following pagination
+func (c *Client) ListPullRequests(ctx context.Context, owner, repo string, filter PRFilter) ([]PullRequest, error) {
+	query := url.Values{"per_page": {"100"}}
+	if filter.State != "" {
+		query.Set("state", filter.State)
+	}
+	if filter.Head != "" {
+		query.Set("head", filter.Head)
+	}
+	if filter.Base != "" {
+		query.Set("base", filter.Base)
+	}
+
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/pulls?%s", c.baseURL, owner, repo, query.Encode())
+	pulls, err := Paginate(ctx, c, endpoint, func(body []byte) ([]PullRequest, error) {
+		var page []PullRequest
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, err
+		}
+		return page, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull requests: %w", err)
+	}
+
+	return pulls, nil
+}
+
 // CreatePullRequest creates a new pull request
 func (c *Client) CreatePullRequest(ctx context.Context, opts PROptions) error {
+	_, err := c.OpenPullRequest(ctx, opts)
+	return err
+}
+
+// OpenPullRequest creates a pull request and returns it
+func (c *Client) OpenPullRequest(ctx context.Context, opts PROptions) (*PullRequest, error) {
 	url := fmt.Sprintf("%s/repos/%s/%s/pulls", c.baseURL, opts.Owner, opts.Repo)
 	jsonBody, err := json.Marshal(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.sendRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pull request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var pr PullRequest
+	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to decode pull request: %w", err)
+	}
+	return &pr, nil
+}
+
+// GetPullRequest returns a pull request, including whether it was merged
+// or can be merged and the commits its head and base point at
+func (c *Client) GetPullRequest(ctx context.Context, owner, repo string, number int) (*PullRequest, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/pulls/%d", c.baseURL, owner, repo, number)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.sendRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var pr PullRequest
+	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &pr, nil
+}
+
+// PRPatch lists pull request fields to change with UpdatePullRequest.
+// Nil fields are left unchanged.
+type PRPatch struct {
+	Title *string `json:"title,omitempty"`
+	Body  *string `json:"body,omitempty"`
+	Base  *string `json:"base,omitempty"`
+	State *string `json:"state,omitempty"` // "open" or "closed"
+}
+
+// UpdatePullRequest changes the title, body, base branch or state of a pull
+// request and returns the updated pull request
+func (c *Client) UpdatePullRequest(ctx context.Context, owner, repo string, number int, patch PRPatch) (*PullRequest, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/pulls/%d", c.baseURL, owner, repo, number)
+	jsonBody, err := json.Marshal(patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.sendRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update pull request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var pr PullRequest
+	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &pr, nil
+}
+
+// ClosePullRequest closes a pull request without merging it
+func (c *Client) ClosePullRequest(ctx context.Context, owner, repo string, number int) error {
+	closed := "closed"
+	if _, err := c.UpdatePullRequest(ctx, owner, repo, number, PRPatch{State: &closed}); err != nil {
+		return fmt.Errorf("failed to close pull request: %w", err)
+	}
+	return nil
+}
+
+// RequestReviewers asks users and teams (by slug) to review a pull request
+func (c *Client) RequestReviewers(ctx context.Context, owner, repo string, number int, users, teams []string) error {
+	if len(users) == 0 && len(teams) == 0 {
+		return nil
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/%s/pulls/%d/requested_reviewers", c.baseURL, owner, repo, number)
+	jsonBody, err := json.Marshal(struct {
+		Reviewers     []string `json:"reviewers,omitempty"`
+		TeamReviewers []string `json:"team_reviewers,omitempty"`
+	}{Reviewers: users, TeamReviewers: teams})
 	if err != nil {
 		return fmt.Errorf("failed to marshal request body: %w", err)
 	}
@@ -305,9 +703,40 @@ func (c *Client) CreatePullRequest(ctx context.Context, opts PROptions) error {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	if _, err = c.sendRequest(req); err != nil {
-		return fmt.Errorf("failed to create pull request: %w", err)
+	resp, err := c.sendRequest(req)
+	if err != nil {
+		return fmt.Errorf("failed to request reviewers: %w", err)
 	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// AddLabels adds labels to a pull request or issue, keeping its existing
+// labels. Labels that do not exist yet are created by GitHub.
+func (c *Client) AddLabels(ctx context.Context, owner, repo string, number int, labels []string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d/labels", c.baseURL, owner, repo, number)
+	jsonBody, err := json.Marshal(struct {
+		Labels []string `json:"labels"`
+	}{Labels: labels})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonBody))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.sendRequest(req)
+	if err != nil {
+		return fmt.Errorf("failed to add labels: %w", err)
+	}
+	resp.Body.Close()
 
 	return nil
 }
@@ -317,26 +746,123 @@ func (c *Client) makeRequest(req *http.Request) (*http.Response, error) {
 	return c.sendRequest(req)
 }
 
-// sendRequest sends an HTTP request with the necessary headers
+// sendRequest sends an HTTP request with the necessary headers. An Accept
+// header already set on req is kept, so callers can ask for other media types.
+// A request rejected by the secondary rate limit is retried once after
+// the delay given in its Retry-After header, and one rejected by the
+// primary limit is retried after the reset when WithWaitOnRateLimit is set.
+// In dry-run mode mutating requests are printed instead of sent.
 func (c *Client) sendRequest(req *http.Request) (*http.Response, error) {
 	req.Header.Set("Authorization", "Bearer "+c.token)
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", defaultAccept)
+	}
 	req.Header.Set("User-Agent", userAgent)
+	if c.apiVersion != "" {
+		req.Header.Set("X-GitHub-Api-Version", c.apiVersion)
+	}
 
-	resp, err := c.httpClient.Do(req)
+	if c.dryRun != nil && isMutating(req) {
+		return c.planRequest(req)
+	}
+
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
 
+	if delay, ok := secondaryRetryAfter(resp); ok {
+		if resp, err = c.retryAfter(req, resp, delay); err != nil {
+			return nil, err
+		}
+	}
+
+	if delay, ok := c.primaryRetryAfter(req, resp); ok {
+		if resp, err = c.retryAfter(req, resp, delay); err != nil {
+			return nil, err
+		}
+	}
+
 	if resp.StatusCode >= 400 {
+		// A rejected token must be validated again by the next client
+		if resp.StatusCode == http.StatusUnauthorized {
+			c.validatorCache.Invalidate(&token.Token{Value: c.token})
+		}
 		body, _ := io.ReadAll(resp.Body)
 		resp.Body.Close()
-		return resp, fmt.Errorf("GitHub API error: %s: %s", resp.Status, string(body))
+		return resp, newAPIError(resp, body)
+	}
+
+	return resp, nil
+}
+
+// do performs a single HTTP round trip, reporting it to the configured
+// metrics and tracing hooks
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	var tracer *requestTracer
+	if c.trace != nil {
+		tracer = &requestTracer{}
+		req = tracer.withTrace(req)
 	}
 
+	metrics := c.getMetrics()
+	c.debugRequest(req)
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	elapsed := time.Since(start)
+	metrics.ObserveLatency(req.Method, elapsed)
+	c.debugResponse(req, resp, err, elapsed)
+	if tracer != nil {
+		c.trace(tracer.timing)
+	}
+	if err != nil {
+		metrics.IncRequest(req.Method, 0)
+		return nil, err
+	}
+
+	metrics.IncRequest(req.Method, resp.StatusCode)
+	if isRateLimited(resp) {
+		metrics.IncRateLimited()
+	}
 	return resp, nil
 }
 
+// retryAfter closes resp, waits for delay and sends req again. If req cannot
+// be replayed or the retry budget is exhausted, resp is returned unchanged.
+func (c *Client) retryAfter(req *http.Request, resp *http.Response, delay time.Duration) (*http.Response, error) {
+	retry, err := cloneRequest(req)
+	if err != nil {
+		return resp, nil
+	}
+	if !c.retryBudget.Allow() {
+		return resp, nil
+	}
+	resp.Body.Close()
+	if err := sleepContext(req.Context(), delay); err != nil {
+		return nil, err
+	}
+	c.getMetrics().IncRetry()
+	return c.do(retry)
+}
+
+// cloneRequest returns a copy of req that can be sent again, including a
+// fresh body. It fails for requests whose body cannot be replayed.
+func cloneRequest(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return clone, nil
+	}
+	if req.GetBody == nil {
+		return nil, fmt.Errorf("request body cannot be replayed")
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	clone.Body = body
+	return clone, nil
+}
+
 // ParseRepo parses an owner/repo string into separate owner and repo parts
 func ParseRepo(repoString string) (owner, repo string, err error) {
 	parts := strings.Split(repoString, "/")
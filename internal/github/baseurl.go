@@ -0,0 +1,82 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/NicabarNimble/go-gittools/internal/urlutils"
+)
+
+// enterpriseAPIPath is where GitHub Enterprise Server serves its REST API
+const enterpriseAPIPath = "/api/v3"
+
+// probeClient performs the enterprise self-check; tests replace it to
+// trust their TLS server
+var probeClient = &http.Client{Timeout: 10 * time.Second}
+
+// DetectAPIBaseURL derives the REST API base URL for a repository URL.
+// github.com and GitHub Enterprise Cloud repositories use api.github.com,
+// and allowed GitHub Enterprise Server hosts use https://<host>/api/v3.
+// Any other host is probed at /api/v3/meta and accepted only if it answers
+// like a GitHub Enterprise Server.
+func DetectAPIBaseURL(ctx context.Context, repoURL string) (string, error) {
+	if strings.HasPrefix(repoURL, "git@") {
+		return "", urlutils.ErrNotHTTPS
+	}
+
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", urlutils.ErrInvalidURL, err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return "", urlutils.ErrInvalidURL
+	}
+
+	switch {
+	case urlutils.IsPublicGitHubHost(u.Host):
+		return apiBaseURL, nil
+	case urlutils.IsGitHubEnterpriseHost(u.Host):
+		return "https://" + u.Host + enterpriseAPIPath, nil
+	}
+
+	baseURL := "https://" + u.Host + enterpriseAPIPath
+	if err := probeEnterprise(ctx, baseURL); err != nil {
+		return "", fmt.Errorf("%w: %s: %v", urlutils.ErrInvalidHost, u.Host, err)
+	}
+	return baseURL, nil
+}
+
+// probeEnterprise checks that baseURL serves the GitHub meta endpoint.
+// The endpoint needs no authentication, so no token is sent to an
+// unverified host.
+func probeEnterprise(ctx context.Context, baseURL string) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+"/meta", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := probeClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("not a GitHub Enterprise Server: status %d", resp.StatusCode)
+	}
+
+	var meta struct {
+		InstalledVersion string `json:"installed_version"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&meta); err != nil || meta.InstalledVersion == "" {
+		return fmt.Errorf("not a GitHub Enterprise Server: unexpected meta response")
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NicabarNimble/go-gittools/internal/urlutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectAPIBaseURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		repoURL string
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "public GitHub",
+			repoURL: "https://github.com/owner/repo",
+			want:    "https://api.github.com",
+		},
+		{
+			name:    "public GitHub with .git suffix",
+			repoURL: "https://github.com/owner/repo.git",
+			want:    "https://api.github.com",
+		},
+		{
+			name:    "GitHub Enterprise Cloud",
+			repoURL: "https://enterprise.github.com/owner/repo",
+			want:    "https://api.github.com",
+		},
+		{
+			name:    "GitHub Enterprise Server",
+			repoURL: "https://github.enterprise.com/owner/repo",
+			want:    "https://github.enterprise.com/api/v3",
+		},
+		{
+			name:    "SSH URL",
+			repoURL: "git@github.com:owner/repo.git",
+			wantErr: urlutils.ErrNotHTTPS,
+		},
+		{
+			name:    "HTTP URL",
+			repoURL: "http://github.com/owner/repo",
+			wantErr: urlutils.ErrInvalidURL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DetectAPIBaseURL(context.Background(), tt.repoURL)
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), "got error %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDetectAPIBaseURLProbe(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/meta", func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Authorization"))
+		w.Write([]byte(`{"installed_version": "3.12.0"}`))
+	})
+	server := httptest.NewTLSServer(mux)
+	defer server.Close()
+
+	oldClient := probeClient
+	probeClient = server.Client()
+	defer func() { probeClient = oldClient }()
+
+	got, err := DetectAPIBaseURL(context.Background(), server.URL+"/owner/repo")
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/api/v3", got)
+
+	// A host that doesn't answer like GitHub Enterprise is rejected
+	other := httptest.NewTLSServer(http.NotFoundHandler())
+	defer other.Close()
+	probeClient = other.Client()
+
+	_, err = DetectAPIBaseURL(context.Background(), other.URL+"/owner/repo")
+	assert.True(t, errors.Is(err, urlutils.ErrInvalidHost), "got error %v", err)
+}
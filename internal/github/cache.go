@@ -0,0 +1,120 @@
+package github
+
+import (
+	"sync"
+	"time"
+
+	"github.com/NicabarNimble/go-gittools/internal/token"
+)
+
+// DefaultValidatorCacheTTL is how long a validated token is trusted when
+// NewValidatorCache is given no TTL
+const DefaultValidatorCacheTTL = time.Minute
+
+// ValidatorCache remembers tokens that recently passed validation, so
+// clients created in quick succession with WithValidatorCache validate a
+// token once instead of on every NewClient. Entries are keyed by token
+// fingerprint and API base URL, and a token is dropped as soon as a
+// request made with it is rejected as unauthorized. It is safe for
+// concurrent use.
+type ValidatorCache struct {
+	ttl time.Duration
+	now func() time.Time // replaced in tests
+
+	mu      sync.Mutex
+	entries map[validatorCacheKey]validatorCacheEntry
+}
+
+type validatorCacheKey struct {
+	baseURL     string
+	fingerprint string
+}
+
+// validatorCacheEntry holds what validation learned about a token
+type validatorCacheEntry struct {
+	scope     string
+	expiresAt time.Time // token expiry reported by GitHub, if any
+	login     string
+	validated time.Time
+}
+
+// NewValidatorCache creates a cache trusting validated tokens for ttl.
+// A ttl of zero or less uses DefaultValidatorCacheTTL.
+func NewValidatorCache(ttl time.Duration) *ValidatorCache {
+	if ttl <= 0 {
+		ttl = DefaultValidatorCacheTTL
+	}
+	return &ValidatorCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[validatorCacheKey]validatorCacheEntry),
+	}
+}
+
+// WithValidatorCache makes NewClient skip validating tokens that cache
+// has seen validated within its TTL
+func WithValidatorCache(cache *ValidatorCache) ClientOption {
+	return func(c *Client) {
+		c.validatorCache = cache
+	}
+}
+
+// lookup returns the cached validation of t against baseURL, applying the
+// cached scope and expiry to t. A nil cache never hits.
+func (vc *ValidatorCache) lookup(baseURL string, t *token.Token) (validatorCacheEntry, bool) {
+	if vc == nil {
+		return validatorCacheEntry{}, false
+	}
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+
+	key := validatorCacheKey{baseURL: baseURL, fingerprint: t.Fingerprint()}
+	entry, ok := vc.entries[key]
+	if !ok {
+		return validatorCacheEntry{}, false
+	}
+	now := vc.now()
+	if now.Sub(entry.validated) >= vc.ttl || (!entry.expiresAt.IsZero() && !now.Before(entry.expiresAt)) {
+		delete(vc.entries, key)
+		return validatorCacheEntry{}, false
+	}
+
+	t.Scope = entry.scope
+	if !entry.expiresAt.IsZero() {
+		t.ExpiresAt = entry.expiresAt
+	}
+	return entry, true
+}
+
+// store records that t was validated against baseURL for the given login
+func (vc *ValidatorCache) store(baseURL string, t *token.Token, login string) {
+	if vc == nil {
+		return
+	}
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+
+	vc.entries[validatorCacheKey{baseURL: baseURL, fingerprint: t.Fingerprint()}] = validatorCacheEntry{
+		scope:     t.Scope,
+		expiresAt: t.ExpiresAt,
+		login:     login,
+		validated: vc.now(),
+	}
+}
+
+// Invalidate forgets every cached validation of t, so the next NewClient
+// validates it again
+func (vc *ValidatorCache) Invalidate(t *token.Token) {
+	if vc == nil {
+		return
+	}
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+
+	fingerprint := t.Fingerprint()
+	for key := range vc.entries {
+		if key.fingerprint == fingerprint {
+			delete(vc.entries, key)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/NicabarNimble/go-gittools/internal/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatorCacheSkipsRevalidation(t *testing.T) {
+	var userCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/user":
+			atomic.AddInt32(&userCalls, 1)
+			w.Header().Set("X-OAuth-Scopes", "repo, workflow")
+			w.Write([]byte(`{"login": "octocat"}`))
+		case "/repos/owner/repo":
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message": "Bad credentials"}`))
+		}
+	}))
+	defer server.Close()
+
+	cache := NewValidatorCache(time.Minute)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	newClient := func() *Client {
+		t.Helper()
+		tok := &token.Token{Value: "ghp_cached"}
+		client, err := NewClient(context.Background(), tok, WithBaseURL(server.URL), WithValidatorCache(cache))
+		require.NoError(t, err)
+		assert.Equal(t, "repo, workflow", tok.Scope, "cached scopes are applied to the token")
+		return client
+	}
+
+	client := newClient()
+	validated := atomic.LoadInt32(&userCalls)
+	require.NotZero(t, validated)
+
+	// A second client within the TTL does not hit the validation endpoint
+	second := newClient()
+	assert.Equal(t, validated, atomic.LoadInt32(&userCalls))
+	assert.Equal(t, "octocat", second.GetUsername())
+
+	// Once the TTL has passed the token is validated again
+	now = now.Add(time.Minute)
+	newClient()
+	assert.Equal(t, 2*validated, atomic.LoadInt32(&userCalls))
+
+	// An unauthorized response drops the token from the cache
+	_, err := client.GetRepository(context.Background(), "owner", "repo")
+	require.Error(t, err)
+	newClient()
+	assert.Equal(t, 3*validated, atomic.LoadInt32(&userCalls))
+}
+
+func TestValidatorCacheKeys(t *testing.T) {
+	cache := NewValidatorCache(0)
+	assert.Equal(t, DefaultValidatorCacheTTL, cache.ttl)
+
+	tok := &token.Token{Value: "ghp_one", Scope: "repo"}
+	cache.store("https://api.github.com", tok, "octocat")
+
+	_, ok := cache.lookup("https://ghe.example.com/api/v3", &token.Token{Value: "ghp_one"})
+	assert.False(t, ok, "entries are per API")
+	_, ok = cache.lookup("https://api.github.com", &token.Token{Value: "ghp_two"})
+	assert.False(t, ok, "entries are per token")
+	entry, ok := cache.lookup("https://api.github.com", &token.Token{Value: "ghp_one"})
+	assert.True(t, ok)
+	assert.Equal(t, "octocat", entry.login)
+
+	cache.Invalidate(&token.Token{Value: "ghp_one"})
+	_, ok = cache.lookup("https://api.github.com", &token.Token{Value: "ghp_one"})
+	assert.False(t, ok)
+
+	// A nil cache is a no-op
+	var none *ValidatorCache
+	none.store("https://api.github.com", tok, "octocat")
+	_, ok = none.lookup("https://api.github.com", tok)
+	assert.False(t, ok)
+	none.Invalidate(tok)
+}
+
+func TestValidatorCacheConcurrentUse(t *testing.T) {
+	cache := NewValidatorCache(time.Minute)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tok := &token.Token{Value: "ghp_concurrent", Scope: "repo"}
+			cache.store("https://api.github.com", tok, "octocat")
+			cache.lookup("https://api.github.com", &token.Token{Value: "ghp_concurrent"})
+			if i%5 == 0 {
+				cache.Invalidate(tok)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,53 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Comparison is the difference between two refs as reported by the
+// compare API
+type Comparison struct {
+	Status       string           `json:"status"` // ahead, behind, diverged or identical
+	AheadBy      int              `json:"ahead_by"`
+	BehindBy     int              `json:"behind_by"`
+	TotalCommits int              `json:"total_commits"`
+	Files        []ComparisonFile `json:"files"`
+}
+
+// ComparisonFile is a file changed between the compared refs
+type ComparisonFile struct {
+	Filename         string `json:"filename"`
+	PreviousFilename string `json:"previous_filename"` // Set for renames
+	Status           string `json:"status"`            // added, removed, modified, renamed, ...
+	Additions        int    `json:"additions"`
+	Deletions        int    `json:"deletions"`
+	Changes          int    `json:"changes"`
+	Patch            string `json:"patch"` // Omitted by GitHub for binary or very large diffs
+}
+
+// CompareCommits compares head with base, which may be branches, tags or
+// SHAs. A head in another fork of the repository is given as
+// "owner:branch".
+func (c *Client) CompareCommits(ctx context.Context, owner, repo, base, head string) (*Comparison, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/compare/%s...%s", c.baseURL, owner, repo, base, head)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.sendRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare commits: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var comparison Comparison
+	if err := json.NewDecoder(resp.Body).Decode(&comparison); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &comparison, nil
+}
@@ -0,0 +1,58 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareCommits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/compare/main...fork:feature":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{
+				"status": "diverged",
+				"ahead_by": 2,
+				"behind_by": 1,
+				"total_commits": 2,
+				"commits": [{"sha": "6dcb09b"}, {"sha": "7ac2f1e"}],
+				"files": [
+					{"filename": "README.md", "status": "modified", "additions": 3, "deletions": 1, "changes": 4, "patch": "@@ -1 +1,3 @@"},
+					{"filename": "docs/new.md", "previous_filename": "docs/old.md", "status": "renamed", "additions": 0, "deletions": 0, "changes": 0}
+				]
+			}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "Not Found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		token:      "test-token",
+		baseURL:    server.URL,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+
+	comparison, err := client.CompareCommits(context.Background(), "owner", "repo", "main", "fork:feature")
+	require.NoError(t, err)
+	assert.Equal(t, &Comparison{
+		Status:       "diverged",
+		AheadBy:      2,
+		BehindBy:     1,
+		TotalCommits: 2,
+		Files: []ComparisonFile{
+			{Filename: "README.md", Status: "modified", Additions: 3, Deletions: 1, Changes: 4, Patch: "@@ -1 +1,3 @@"},
+			{Filename: "docs/new.md", PreviousFilename: "docs/old.md", Status: "renamed"},
+		},
+	}, comparison)
+
+	_, err = client.CompareCommits(context.Background(), "owner", "repo", "main", "missing")
+	assert.ErrorContains(t, err, "failed to compare commits")
+}
@@ -0,0 +1,97 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// mediaTypeRaw asks the contents API for a file's bytes instead of JSON
+const mediaTypeRaw = "application/vnd.github.raw+json"
+
+// GetFileContent returns the decoded content of the file at path and its
+// blob SHA, which the contents API requires to update the file. An empty
+// ref reads the default branch. Files too large to be inlined by the
+// contents API are fetched again as raw content.
+func (c *Client) GetFileContent(ctx context.Context, owner, repo, path, ref string) ([]byte, string, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/contents/%s", c.baseURL, owner, repo, strings.TrimPrefix(path, "/"))
+	if ref != "" {
+		endpoint += "?" + url.Values{"ref": {ref}}.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.sendRequest(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get file content: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Directories are listed as an array of entries
+	var raw json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if strings.HasPrefix(string(raw), "[") {
+		return nil, "", fmt.Errorf("%s is not a file", path)
+	}
+
+	var file struct {
+		Type     string `json:"type"`
+		SHA      string `json:"sha"`
+		Encoding string `json:"encoding"`
+		Content  string `json:"content"`
+	}
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if file.Type != "file" {
+		return nil, "", fmt.Errorf("%s is not a file", path)
+	}
+
+	switch file.Encoding {
+	case "base64":
+		// The content is wrapped at 60 characters
+		content, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(file.Content, "\n", ""))
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to decode file content: %w", err)
+		}
+		return content, file.SHA, nil
+	case "none", "":
+		content, err := c.getRawFileContent(ctx, endpoint)
+		if err != nil {
+			return nil, "", err
+		}
+		return content, file.SHA, nil
+	default:
+		return nil, "", fmt.Errorf("unsupported content encoding %q", file.Encoding)
+	}
+}
+
+// getRawFileContent reads a file through the raw media type
+func (c *Client) getRawFileContent(ctx context.Context, endpoint string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", mediaTypeRaw)
+
+	resp, err := c.sendRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get raw file content: %w", err)
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file content: %w", err)
+	}
+	return content, nil
+}
@@ -0,0 +1,75 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetFileContent(t *testing.T) {
+	workflow := "name: CI\non: push\njobs:\n  build:\n    runs-on: ubuntu-latest\n    steps:\n      - uses: actions/checkout@v4\n"
+	encoded := base64.StdEncoding.EncodeToString([]byte(workflow))
+	// GitHub wraps base64 content at 60 characters
+	var wrapped string
+	for len(encoded) > 60 {
+		wrapped += encoded[:60] + "\n"
+		encoded = encoded[60:]
+	}
+	wrapped += encoded + "\n"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/contents/.github/workflows/ci.yml":
+			assert.Equal(t, "develop", r.URL.Query().Get("ref"))
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"type": "file", "encoding": "base64", "sha": "3d21ec53a331a6f037a91c368710b99387d012c1", "content": ` + jsonString(wrapped) + `}`))
+		case "/repos/owner/repo/contents/large.bin":
+			if r.Header.Get("Accept") == mediaTypeRaw {
+				w.Write([]byte("raw bytes"))
+				return
+			}
+			w.Write([]byte(`{"type": "file", "encoding": "none", "sha": "abc123", "content": ""}`))
+		case "/repos/owner/repo/contents/docs":
+			w.Write([]byte(`[{"type": "file", "name": "index.md"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "Not Found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		token:      "test-token",
+		baseURL:    server.URL,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+	ctx := context.Background()
+
+	content, sha, err := client.GetFileContent(ctx, "owner", "repo", ".github/workflows/ci.yml", "develop")
+	require.NoError(t, err)
+	assert.Equal(t, workflow, string(content))
+	assert.Equal(t, "3d21ec53a331a6f037a91c368710b99387d012c1", sha)
+
+	content, sha, err = client.GetFileContent(ctx, "owner", "repo", "large.bin", "")
+	require.NoError(t, err)
+	assert.Equal(t, "raw bytes", string(content))
+	assert.Equal(t, "abc123", sha)
+
+	_, _, err = client.GetFileContent(ctx, "owner", "repo", "docs", "")
+	assert.ErrorContains(t, err, "docs is not a file")
+
+	_, _, err = client.GetFileContent(ctx, "owner", "repo", "missing.txt", "")
+	assert.ErrorContains(t, err, "failed to get file content")
+}
+
+func jsonString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
@@ -0,0 +1,73 @@
+package github
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// redacted replaces credentials in debug output
+const redacted = "[REDACTED]"
+
+// WithDebug writes a line for every request and response to w: method,
+// URL, status, duration and the rate-limit headers. Credentials are never
+// written.
+func WithDebug(w io.Writer) ClientOption {
+	return func(c *Client) {
+		c.debug = w
+	}
+}
+
+// debugRequest logs an outgoing request
+func (c *Client) debugRequest(req *http.Request) {
+	if c.debug == nil {
+		return
+	}
+	fmt.Fprintf(c.debug, "> %s %s\n", req.Method, redactURL(req.URL))
+}
+
+// debugResponse logs the outcome of a request
+func (c *Client) debugResponse(req *http.Request, resp *http.Response, err error, d time.Duration) {
+	if c.debug == nil {
+		return
+	}
+	if err != nil {
+		msg := err.Error()
+		if c.token != "" {
+			msg = strings.ReplaceAll(msg, c.token, redacted)
+		}
+		fmt.Fprintf(c.debug, "< %s %s failed after %s: %s\n", req.Method, redactURL(req.URL), d.Round(time.Millisecond), msg)
+		return
+	}
+
+	line := fmt.Sprintf("< %s (%s)", resp.Status, d.Round(time.Millisecond))
+	for _, h := range []string{"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "Retry-After"} {
+		if v := resp.Header.Get(h); v != "" {
+			line += fmt.Sprintf(" %s=%s", h, v)
+		}
+	}
+	fmt.Fprintln(c.debug, line)
+}
+
+// redactURL renders u with any embedded credentials or token query
+// parameters masked
+func redactURL(u *url.URL) string {
+	clone := *u
+	if clone.User != nil {
+		clone.User = url.User(redacted)
+	}
+	if clone.RawQuery != "" {
+		q := clone.Query()
+		for key := range q {
+			lower := strings.ToLower(key)
+			if strings.Contains(lower, "token") || strings.Contains(lower, "secret") {
+				q.Set(key, redacted)
+			}
+		}
+		clone.RawQuery = q.Encode()
+	}
+	return clone.String()
+}
@@ -0,0 +1,89 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// dryRunSensitiveKeys are JSON keys whose values are redacted when a
+// planned request body is printed
+var dryRunSensitiveKeys = map[string]bool{"secret": true, "token": true, "password": true}
+
+// WithDryRun makes mutating requests (anything other than GET and HEAD)
+// print what would be sent to w and return a synthetic success instead of
+// calling the API. Reads still execute so lookups keep working.
+func WithDryRun(w io.Writer) ClientOption {
+	return func(c *Client) {
+		c.dryRun = w
+	}
+}
+
+// isMutating reports whether a request would change state on GitHub
+func isMutating(req *http.Request) bool {
+	return req.Method != http.MethodGet && req.Method != http.MethodHead
+}
+
+// planRequest prints a mutating request and returns the response it
+// pretends to have received: 201 for POST, 204 for DELETE and 200 otherwise
+func (c *Client) planRequest(req *http.Request) (*http.Response, error) {
+	fmt.Fprintf(c.dryRun, "[dry-run] %s %s\n", req.Method, redactURL(req.URL))
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		if len(body) > 0 {
+			fmt.Fprintf(c.dryRun, "%s\n", redactBody(body))
+		}
+	}
+
+	status := http.StatusOK
+	switch req.Method {
+	case http.MethodPost:
+		status = http.StatusCreated
+	case http.MethodDelete:
+		status = http.StatusNoContent
+	}
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode: status,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader("{}")),
+		Request:    req,
+	}, nil
+}
+
+// redactBody hides sensitive values in a JSON request body. Bodies that
+// are not JSON objects are printed as-is.
+func redactBody(body []byte) []byte {
+	var v map[string]interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return body
+	}
+	redactValues(v)
+	out, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return bytes.TrimSpace(out)
+}
+
+// redactValues replaces sensitive values in a decoded JSON object in place
+func redactValues(v map[string]interface{}) {
+	for k, val := range v {
+		if dryRunSensitiveKeys[strings.ToLower(k)] {
+			if s, ok := val.(string); ok && s != "" {
+				v[k] = redacted
+			}
+			continue
+		}
+		if nested, ok := val.(map[string]interface{}); ok {
+			redactValues(nested)
+		}
+	}
+}
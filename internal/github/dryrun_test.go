@@ -0,0 +1,55 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientDryRun(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"name": "repo", "default_branch": "main"}`))
+	}))
+	defer server.Close()
+
+	var plan bytes.Buffer
+	client := &Client{
+		token:   "test-token",
+		baseURL: server.URL,
+		httpClient: &http.Client{
+			Timeout: time.Second * 30,
+		},
+	}
+	WithDryRun(&plan)(client)
+
+	err := client.CreateRepository(context.Background(), RepoOptions{Name: "mirror", Private: true})
+	require.NoError(t, err)
+
+	hook, err := client.CreateWebhook(context.Background(), "owner", "mirror", WebhookConfig{
+		URL:    "https://sync.example.com/hook",
+		Secret: "s3cret-value",
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, hook)
+
+	assert.Empty(t, requests, "no mutating request may reach the API")
+	assert.Contains(t, plan.String(), "[dry-run] POST "+server.URL+"/user/repos")
+	assert.Contains(t, plan.String(), `"name":"mirror"`)
+	assert.Contains(t, plan.String(), "[dry-run] POST "+server.URL+"/repos/owner/mirror/hooks")
+	assert.NotContains(t, plan.String(), "s3cret-value")
+
+	// Reads still reach the API
+	branch, err := client.GetDefaultBranch(context.Background(), "owner/repo")
+	require.NoError(t, err)
+	assert.Equal(t, "main", branch)
+	assert.Equal(t, []string{"GET /repos/owner/repo"}, requests)
+}
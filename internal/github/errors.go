@@ -0,0 +1,105 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned for responses GitHub rejects with a 4xx or 5xx
+// status. Message and Errors are decoded from the JSON error body when it
+// has one; Body always holds the raw response for debugging.
+type APIError struct {
+	StatusCode       int
+	Status           string
+	Message          string
+	Errors           []FieldError
+	DocumentationURL string
+	Body             []byte
+}
+
+// FieldError is one entry of a validation error's errors list
+type FieldError struct {
+	Resource string `json:"resource"`
+	Field    string `json:"field"`
+	Code     string `json:"code"` // e.g. missing_field, invalid, already_exists or custom
+	Message  string `json:"message"`
+}
+
+// UnmarshalJSON accepts both error objects and the plain strings some
+// endpoints return in place of them
+func (e *FieldError) UnmarshalJSON(data []byte) error {
+	var message string
+	if err := json.Unmarshal(data, &message); err == nil {
+		*e = FieldError{Message: message}
+		return nil
+	}
+	type fieldError FieldError
+	return json.Unmarshal(data, (*fieldError)(e))
+}
+
+// String describes the error, falling back to its field and code when
+// GitHub gave no message
+func (e FieldError) String() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	parts := make([]string, 0, 3)
+	for _, s := range []string{e.Resource, e.Field, e.Code} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// newAPIError builds the error for a failed response from its status and body
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       body,
+	}
+	var parsed struct {
+		Message          string       `json:"message"`
+		Errors           []FieldError `json:"errors"`
+		DocumentationURL string       `json:"documentation_url"`
+	}
+	if json.Unmarshal(body, &parsed) == nil {
+		apiErr.Message = parsed.Message
+		apiErr.Errors = parsed.Errors
+		apiErr.DocumentationURL = parsed.DocumentationURL
+	}
+	return apiErr
+}
+
+// Error reports the status and message with any field errors, or the raw
+// body if it could not be parsed
+func (e *APIError) Error() string {
+	if e.Message == "" && len(e.Errors) == 0 {
+		return fmt.Sprintf("GitHub API error: %s: %s", e.Status, string(e.Body))
+	}
+	msg := fmt.Sprintf("GitHub API error: %s: %s", e.Status, e.Message)
+	if len(e.Errors) > 0 {
+		details := make([]string, len(e.Errors))
+		for i, fe := range e.Errors {
+			details[i] = fe.String()
+		}
+		msg += " (" + strings.Join(details, "; ") + ")"
+	}
+	return msg
+}
+
+// Details returns the message followed by the field errors, without the
+// status, for showing to users
+func (e *APIError) Details() []string {
+	var details []string
+	if e.Message != "" {
+		details = append(details, e.Message)
+	}
+	for _, fe := range e.Errors {
+		details = append(details, fe.String())
+	}
+	return details
+}
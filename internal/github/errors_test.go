@@ -0,0 +1,73 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIErrorValidationBody(t *testing.T) {
+	body := `{
+		"message": "Validation Failed",
+		"errors": [
+			{"resource": "PullRequest", "code": "custom", "message": "A pull request already exists for octocat:feature."},
+			{"resource": "PullRequest", "field": "base", "code": "invalid"},
+			"head sha can't be blank"
+		],
+		"documentation_url": "https://docs.github.com/rest/pulls/pulls#create-a-pull-request"
+	}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		token:      "test-token",
+		baseURL:    server.URL,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+
+	_, err := client.OpenPullRequest(context.Background(), PROptions{
+		Title: "Feature",
+		Head:  "octocat:feature",
+		Base:  "main",
+		Owner: "owner",
+		Repo:  "repo",
+	})
+	require.Error(t, err)
+
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusUnprocessableEntity, apiErr.StatusCode)
+	assert.Equal(t, "Validation Failed", apiErr.Message)
+	assert.Equal(t, "https://docs.github.com/rest/pulls/pulls#create-a-pull-request", apiErr.DocumentationURL)
+	assert.Equal(t, []FieldError{
+		{Resource: "PullRequest", Code: "custom", Message: "A pull request already exists for octocat:feature."},
+		{Resource: "PullRequest", Field: "base", Code: "invalid"},
+		{Message: "head sha can't be blank"},
+	}, apiErr.Errors)
+	assert.Equal(t, []string{
+		"Validation Failed",
+		"A pull request already exists for octocat:feature.",
+		"PullRequest base invalid",
+		"head sha can't be blank",
+	}, apiErr.Details())
+	assert.Equal(t, body, string(apiErr.Body))
+	assert.Contains(t, err.Error(), "422 Unprocessable Entity: Validation Failed (A pull request already exists for octocat:feature.; PullRequest base invalid; head sha can't be blank)")
+}
+
+func TestAPIErrorUnparsedBody(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusBadGateway, Status: "502 Bad Gateway"}
+	err := newAPIError(resp, []byte("<html>upstream error</html>"))
+
+	assert.Empty(t, err.Message)
+	assert.Empty(t, err.Details())
+	assert.Equal(t, "GitHub API error: 502 Bad Gateway: <html>upstream error</html>", err.Error())
+}
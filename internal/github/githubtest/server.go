@@ -0,0 +1,394 @@
+// Package githubtest provides an in-memory fake of the GitHub REST API for
+// tests. It serves the endpoints used by the github package's client, so
+// code built on it can be exercised end to end without the network.
+package githubtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/NicabarNimble/go-gittools/internal/github"
+)
+
+// DefaultLogin is the authenticated user reported by a FakeServer
+const DefaultLogin = "test-user"
+
+// defaultScopes satisfies the GitHub token validator
+const defaultScopes = "repo, workflow, admin:repo"
+
+// PullRequest is a pull request recorded by a FakeServer
+type PullRequest struct {
+	Number int
+	Owner  string
+	Repo   string
+	Title  string
+	Body   string
+	Head   string
+	Base   string
+}
+
+// FakeServer is an httptest server holding repositories, pull requests and
+// workflow runs in memory. It is safe for concurrent use.
+type FakeServer struct {
+	server *httptest.Server
+	login  string
+
+	mu    sync.Mutex
+	repos map[string]*github.Repository // keyed by "owner/repo"
+	pulls []PullRequest
+	runs  map[int64]*fakeRun
+	next  int64
+}
+
+// fakeRun is a workflow run and the repository and workflow it belongs to
+type fakeRun struct {
+	repo     string
+	workflow string
+	run      github.WorkflowRun
+	logs     []byte
+}
+
+// NewFakeServer starts a FakeServer. Callers must Close it.
+func NewFakeServer() *FakeServer {
+	f := &FakeServer{
+		login: DefaultLogin,
+		repos: make(map[string]*github.Repository),
+		runs:  make(map[int64]*fakeRun),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /user", f.handleUser)
+	mux.HandleFunc("POST /user/repos", f.handleCreateRepo)
+	mux.HandleFunc("GET /repos/{owner}/{repo}", f.handleGetRepo)
+	mux.HandleFunc("PATCH /repos/{owner}/{repo}", f.handleUpdateRepo)
+	mux.HandleFunc("DELETE /repos/{owner}/{repo}", f.handleDeleteRepo)
+	mux.HandleFunc("POST /repos/{owner}/{repo}/forks", f.handleCreateFork)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/pulls", f.handleListPulls)
+	mux.HandleFunc("POST /repos/{owner}/{repo}/pulls", f.handleCreatePull)
+	mux.HandleFunc("POST /repos/{owner}/{repo}/actions/workflows/{workflow}/dispatches", f.handleDispatch)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/actions/workflows/{workflow}/runs", f.handleListRuns)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/actions/runs/{id}", f.handleGetRun)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/actions/runs/{id}/logs", f.handleGetLogs)
+	f.server = httptest.NewServer(mux)
+
+	return f
+}
+
+// URL is the API base URL to pass to github.WithBaseURL
+func (f *FakeServer) URL() string {
+	return f.server.URL
+}
+
+// Close shuts the server down
+func (f *FakeServer) Close() {
+	f.server.Close()
+}
+
+// AddRepository creates a repository directly, e.g. to simulate one that
+// already exists
+func (f *FakeServer) AddRepository(owner, name string, private bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.addRepo(owner, name, private, false)
+}
+
+// Repository returns the repository with the given "owner/repo" name
+func (f *FakeServer) Repository(fullName string) (github.Repository, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, ok := f.repos[fullName]
+	if !ok {
+		return github.Repository{}, false
+	}
+	return *repo, true
+}
+
+// Repositories returns the full names of all repositories, sorted
+func (f *FakeServer) Repositories() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	names := make([]string, 0, len(f.repos))
+	for name := range f.repos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PullRequests returns the pull requests created so far
+func (f *FakeServer) PullRequests() []PullRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]PullRequest(nil), f.pulls...)
+}
+
+// CompleteRun marks a workflow run completed with the given conclusion and
+// sets the logs returned for it
+func (f *FakeServer) CompleteRun(id int64, conclusion string, logs []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r, ok := f.runs[id]
+	if !ok {
+		return fmt.Errorf("no workflow run %d", id)
+	}
+	r.run.Status = "completed"
+	r.run.Conclusion = conclusion
+	r.run.UpdatedAt = time.Now()
+	r.logs = logs
+	return nil
+}
+
+// addRepo records a repository; f.mu must be held
+func (f *FakeServer) addRepo(owner, name string, private, fork bool) *github.Repository {
+	repo := &github.Repository{
+		Name:          name,
+		FullName:      owner + "/" + name,
+		CloneURL:      fmt.Sprintf("https://github.com/%s/%s.git", owner, name),
+		DefaultBranch: "main",
+		Private:       private,
+		Fork:          fork,
+	}
+	f.repos[repo.FullName] = repo
+	return repo
+}
+
+func (f *FakeServer) handleUser(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-OAuth-Scopes", defaultScopes)
+	writeJSON(w, http.StatusOK, github.UserInfo{Login: f.login})
+}
+
+func (f *FakeServer) handleCreateRepo(w http.ResponseWriter, r *http.Request) {
+	var opts github.RepoOptions
+	if err := json.NewDecoder(r.Body).Decode(&opts); err != nil || opts.Name == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Repository creation failed.")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.repos[f.login+"/"+opts.Name]; ok {
+		writeError(w, http.StatusUnprocessableEntity, "Repository creation failed: name already exists on this account")
+		return
+	}
+	writeJSON(w, http.StatusCreated, f.addRepo(f.login, opts.Name, opts.Private, false))
+}
+
+func (f *FakeServer) handleGetRepo(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, ok := f.repos[r.PathValue("owner")+"/"+r.PathValue("repo")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, repo)
+}
+
+func (f *FakeServer) handleUpdateRepo(w http.ResponseWriter, r *http.Request) {
+	var patch github.RepoPatch
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo, ok := f.repos[r.PathValue("owner")+"/"+r.PathValue("repo")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	if patch.Private != nil {
+		repo.Private = *patch.Private
+	}
+	if patch.DefaultBranch != nil {
+		repo.DefaultBranch = *patch.DefaultBranch
+	}
+	writeJSON(w, http.StatusOK, repo)
+}
+
+func (f *FakeServer) handleDeleteRepo(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fullName := r.PathValue("owner") + "/" + r.PathValue("repo")
+	if _, ok := f.repos[fullName]; !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	delete(f.repos, fullName)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (f *FakeServer) handleCreateFork(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	source, ok := f.repos[r.PathValue("owner")+"/"+r.PathValue("repo")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusAccepted, f.addRepo(f.login, source.Name, source.Private, true))
+}
+
+func (f *FakeServer) handleCreatePull(w http.ResponseWriter, r *http.Request) {
+	var opts github.PROptions
+	if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.repos[r.PathValue("owner")+"/"+r.PathValue("repo")]; !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	pr := PullRequest{
+		Number: len(f.pulls) + 1,
+		Owner:  r.PathValue("owner"),
+		Repo:   r.PathValue("repo"),
+		Title:  opts.Title,
+		Body:   opts.Body,
+		Head:   opts.Head,
+		Base:   opts.Base,
+	}
+	f.pulls = append(f.pulls, pr)
+	writeJSON(w, http.StatusCreated, pr.apiPullRequest())
+}
+
+// handleListPulls lists recorded pull requests, which are all open, filtered
+// by the state, head and base query parameters
+func (f *FakeServer) handleListPulls(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	head, base := query.Get("head"), query.Get("base")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	pulls := []github.PullRequest{}
+	if query.Get("state") == "closed" {
+		writeJSON(w, http.StatusOK, pulls)
+		return
+	}
+	for _, pr := range f.pulls {
+		if pr.Owner != r.PathValue("owner") || pr.Repo != r.PathValue("repo") {
+			continue
+		}
+		if (head != "" && pr.Head != head) || (base != "" && pr.Base != base) {
+			continue
+		}
+		pulls = append(pulls, pr.apiPullRequest())
+	}
+	writeJSON(w, http.StatusOK, pulls)
+}
+
+// apiPullRequest returns pr as the API reports it
+func (pr PullRequest) apiPullRequest() github.PullRequest {
+	return github.PullRequest{
+		Number:  pr.Number,
+		Title:   pr.Title,
+		HTMLURL: fmt.Sprintf("https://github.com/%s/%s/pull/%d", pr.Owner, pr.Repo, pr.Number),
+	}
+}
+
+func (f *FakeServer) handleDispatch(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Ref string `json:"ref"`
+	}
+	json.NewDecoder(r.Body).Decode(&body)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	repo := r.PathValue("owner") + "/" + r.PathValue("repo")
+	if _, ok := f.repos[repo]; !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	f.next++
+	now := time.Now()
+	f.runs[f.next] = &fakeRun{
+		repo:     repo,
+		workflow: r.PathValue("workflow"),
+		run: github.WorkflowRun{
+			ID:         f.next,
+			Status:     "queued",
+			HeadBranch: body.Ref,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		},
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (f *FakeServer) handleListRuns(w http.ResponseWriter, r *http.Request) {
+	repo := r.PathValue("owner") + "/" + r.PathValue("repo")
+	workflow := r.PathValue("workflow")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	runs := []github.WorkflowRun{}
+	for _, fr := range f.runs {
+		if fr.repo == repo && fr.workflow == workflow {
+			runs = append(runs, fr.run)
+		}
+	}
+	// Newest first, as GitHub returns them
+	sort.Slice(runs, func(i, j int) bool { return runs[i].ID > runs[j].ID })
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"total_count":   len(runs),
+		"workflow_runs": runs,
+	})
+}
+
+func (f *FakeServer) handleGetRun(w http.ResponseWriter, r *http.Request) {
+	fr, ok := f.lookupRun(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, fr.run)
+}
+
+func (f *FakeServer) handleGetLogs(w http.ResponseWriter, r *http.Request) {
+	fr, ok := f.lookupRun(r)
+	if !ok || fr.run.Status != "completed" {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	w.WriteHeader(http.StatusOK)
+	w.Write(fr.logs)
+}
+
+// lookupRun finds the run named by the request's repository and run ID
+func (f *FakeServer) lookupRun(r *http.Request) (fakeRun, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return fakeRun{}, false
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fr, ok := f.runs[id]
+	if !ok || fr.repo != r.PathValue("owner")+"/"+r.PathValue("repo") {
+		return fakeRun{}, false
+	}
+	return *fr, true
+}
+
+// writeJSON writes v as a JSON response with the given status
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a GitHub-style error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"message": message})
+}
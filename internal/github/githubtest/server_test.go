@@ -0,0 +1,88 @@
+package githubtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NicabarNimble/go-gittools/internal/github"
+	"github.com/NicabarNimble/go-gittools/internal/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeServer(t *testing.T) {
+	fake := NewFakeServer()
+	defer fake.Close()
+	fake.AddRepository("org", "upstream", false)
+
+	ctx := context.Background()
+	tok := &token.Token{Value: "ghp_fake", ExpiresAt: time.Now().Add(time.Hour)}
+	client, err := github.NewClient(ctx, tok, github.WithBaseURL(fake.URL()))
+	require.NoError(t, err)
+	assert.Equal(t, DefaultLogin, client.GetUsername())
+
+	// Repositories
+	require.NoError(t, client.CreateRepository(ctx, github.RepoOptions{Name: "mirror", Private: true}))
+	err = client.CreateRepository(ctx, github.RepoOptions{Name: "mirror"})
+	assert.True(t, errors.Is(err, github.ErrRepositoryExists))
+
+	repo, err := client.GetRepository(ctx, DefaultLogin, "mirror")
+	require.NoError(t, err)
+	assert.True(t, repo.Private)
+
+	develop := "develop"
+	require.NoError(t, client.UpdateRepository(ctx, DefaultLogin, "mirror", github.RepoPatch{DefaultBranch: &develop}))
+	repo, err = client.GetRepository(ctx, DefaultLogin, "mirror")
+	require.NoError(t, err)
+	assert.Equal(t, "develop", repo.DefaultBranch)
+
+	// Forks and pull requests
+	require.NoError(t, client.CreateFork(ctx, "org/upstream"))
+	fork, ok := fake.Repository(DefaultLogin + "/upstream")
+	require.True(t, ok)
+	assert.True(t, fork.Fork)
+
+	require.NoError(t, client.CreatePullRequest(ctx, github.PROptions{
+		Owner: "org", Repo: "upstream", Title: "Sync", Head: DefaultLogin + ":main", Base: "main",
+	}))
+	assert.Equal(t, []PullRequest{{
+		Number: 1, Owner: "org", Repo: "upstream", Title: "Sync", Head: DefaultLogin + ":main", Base: "main",
+	}}, fake.PullRequests())
+
+	pulls, err := client.ListPullRequests(ctx, "org", "upstream", github.PRFilter{Head: DefaultLogin + ":main", Base: "main"})
+	require.NoError(t, err)
+	assert.Equal(t, []github.PullRequest{{
+		Number: 1, Title: "Sync", HTMLURL: "https://github.com/org/upstream/pull/1",
+	}}, pulls)
+	pulls, err = client.ListPullRequests(ctx, "org", "upstream", github.PRFilter{State: "closed"})
+	require.NoError(t, err)
+	assert.Empty(t, pulls)
+
+	// Workflow runs and logs
+	require.NoError(t, client.TriggerWorkflow(ctx, "org", "upstream", "sync.yml", nil))
+	runs, err := client.ListWorkflowRuns(ctx, "org", "upstream", "sync.yml")
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, "queued", runs[0].Status)
+
+	require.NoError(t, fake.CompleteRun(runs[0].ID, "success", []byte("log archive")))
+	run, err := client.GetWorkflowRun(ctx, "org", "upstream", runs[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "completed", run.Status)
+	assert.Equal(t, "success", run.Conclusion)
+
+	logs, err := client.GetWorkflowLogs(ctx, "org", "upstream", runs[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "log archive", string(logs))
+
+	assert.Equal(t, []string{"org/upstream", DefaultLogin + "/mirror", DefaultLogin + "/upstream"}, fake.Repositories())
+
+	// Deleting the fork
+	require.NoError(t, client.DeleteRepository(ctx, DefaultLogin, "upstream"))
+	exists, err := client.RepositoryExists(ctx, DefaultLogin, "upstream")
+	require.NoError(t, err)
+	assert.False(t, exists)
+	assert.Error(t, client.DeleteRepository(ctx, DefaultLogin, "upstream"))
+}
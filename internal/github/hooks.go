@@ -0,0 +1,104 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// WebhookConfig describes a repository webhook to create
+type WebhookConfig struct {
+	URL         string   // Payload URL that receives the events
+	ContentType string   // "json" or "form"; defaults to "json"
+	Secret      string   // Optional HMAC secret, never logged
+	Events      []string // Events that trigger the hook; defaults to "push"
+}
+
+// String describes the webhook without revealing its secret
+func (c WebhookConfig) String() string {
+	secret := ""
+	if c.Secret != "" {
+		secret = redacted
+	}
+	return fmt.Sprintf("{URL:%s ContentType:%s Secret:%s Events:%v}", c.URL, c.ContentType, secret, c.Events)
+}
+
+// Webhook is a repository webhook as returned by the GitHub API
+type Webhook struct {
+	ID     int64    `json:"id"`
+	Active bool     `json:"active"`
+	Events []string `json:"events"`
+	Config struct {
+		URL         string `json:"url"`
+		ContentType string `json:"content_type"`
+	} `json:"config"`
+}
+
+// CreateWebhook adds a webhook to a repository
+func (c *Client) CreateWebhook(ctx context.Context, owner, repo string, cfg WebhookConfig) (*Webhook, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("webhook URL is required")
+	}
+	contentType := cfg.ContentType
+	if contentType == "" {
+		contentType = "json"
+	}
+	events := cfg.Events
+	if len(events) == 0 {
+		events = []string{"push"}
+	}
+
+	body := map[string]interface{}{
+		"name":   "web",
+		"active": true,
+		"events": events,
+		"config": map[string]string{
+			"url":          cfg.URL,
+			"content_type": contentType,
+			"secret":       cfg.Secret,
+			"insecure_ssl": "0",
+		},
+	}
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/%s/hooks", c.baseURL, owner, repo)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.sendRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var hook Webhook
+	if err := json.NewDecoder(resp.Body).Decode(&hook); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &hook, nil
+}
+
+// DeleteWebhook removes a webhook from a repository
+func (c *Client) DeleteWebhook(ctx context.Context, owner, repo string, id int64) error {
+	url := fmt.Sprintf("%s/repos/%s/%s/hooks/%d", c.baseURL, owner, repo, id)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.sendRequest(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	resp.Body.Close()
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateWebhook(t *testing.T) {
+	var body struct {
+		Name   string            `json:"name"`
+		Events []string          `json:"events"`
+		Config map[string]string `json:"config"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/repos/owner/mirror/hooks", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 42, "active": true, "events": ["push", "release"], "config": {"url": "https://sync.example.com/hook", "content_type": "json", "secret": "********"}}`))
+	}))
+	defer server.Close()
+
+	var debug bytes.Buffer
+	client := &Client{
+		token:   "test-token",
+		baseURL: server.URL,
+		httpClient: &http.Client{
+			Timeout: time.Second * 30,
+		},
+	}
+	WithDebug(&debug)(client)
+
+	cfg := WebhookConfig{
+		URL:    "https://sync.example.com/hook",
+		Secret: "s3cret-value",
+		Events: []string{"push", "release"},
+	}
+	hook, err := client.CreateWebhook(context.Background(), "owner", "mirror", cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(42), hook.ID)
+	assert.Equal(t, []string{"push", "release"}, hook.Events)
+	assert.Equal(t, "https://sync.example.com/hook", hook.Config.URL)
+
+	assert.Equal(t, "web", body.Name)
+	assert.Equal(t, "json", body.Config["content_type"])
+	assert.Equal(t, "s3cret-value", body.Config["secret"])
+
+	assert.NotContains(t, debug.String(), "s3cret-value")
+	assert.NotContains(t, fmt.Sprint(cfg), "s3cret-value")
+	assert.True(t, strings.Contains(fmt.Sprint(cfg), redacted))
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method)
+		assert.Equal(t, "/repos/owner/mirror/hooks/42", r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		token:   "test-token",
+		baseURL: server.URL,
+		httpClient: &http.Client{
+			Timeout: time.Second * 30,
+		},
+	}
+
+	assert.NoError(t, client.DeleteWebhook(context.Background(), "owner", "mirror", 42))
+}
@@ -0,0 +1,47 @@
+package github
+
+import "time"
+
+// Metrics receives observability events from the client.
+// Implementations can forward them to Prometheus, StatsD, or any other
+// monitoring system without this package depending on it.
+// Methods may be called concurrently and should not block.
+type Metrics interface {
+	// IncRequest counts a completed request; status is 0 if no response was received
+	IncRequest(method string, status int)
+
+	// IncRetry counts a request that is being retried
+	IncRetry()
+
+	// ObserveLatency records how long a request took
+	ObserveLatency(method string, d time.Duration)
+
+	// IncRateLimited counts a response rejected by GitHub's rate limiter
+	IncRateLimited()
+}
+
+// noopMetrics is the default Metrics implementation and discards all events
+type noopMetrics struct{}
+
+func (noopMetrics) IncRequest(string, int)               {}
+func (noopMetrics) IncRetry()                            {}
+func (noopMetrics) ObserveLatency(string, time.Duration) {}
+func (noopMetrics) IncRateLimited()                      {}
+
+// ClientOption configures optional Client behavior
+type ClientOption func(*Client)
+
+// WithMetrics reports request counts, retries, latency and rate-limit hits to m
+func WithMetrics(m Metrics) ClientOption {
+	return func(c *Client) {
+		c.metrics = m
+	}
+}
+
+// getMetrics returns the configured Metrics or a no-op implementation
+func (c *Client) getMetrics() Metrics {
+	if c.metrics == nil {
+		return noopMetrics{}
+	}
+	return c.metrics
+}
@@ -0,0 +1,62 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Paginate fetches firstURL and every page after it by following the
+// rel="next" URL in each response's Link header. decode turns one page's
+// body into items, which are returned in page order.
+func Paginate[T any](ctx context.Context, c *Client, firstURL string, decode func([]byte) ([]T, error)) ([]T, error) {
+	var all []T
+	for url := firstURL; url != ""; {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		resp, err := c.sendRequest(req)
+		if err != nil {
+			return nil, err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+
+		items, err := decode(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		all = append(all, items...)
+
+		url = nextPageURL(resp.Header.Get("Link"))
+	}
+	return all, nil
+}
+
+// nextPageURL extracts the rel="next" URL from a Link header such as
+// `<https://api.github.com/...&page=2>; rel="next", <...>; rel="last"`
+func nextPageURL(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		sections := strings.Split(part, ";")
+		if len(sections) < 2 {
+			continue
+		}
+		target := strings.TrimSpace(sections[0])
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+		for _, param := range sections[1:] {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return target[1 : len(target)-1]
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListWorkflowRunsPaginates(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/owner/repo/actions/workflows/sync.yml/runs", r.URL.Path)
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s?per_page=100&page=2>; rel="next", <%s%s?per_page=100&page=2>; rel="last"`,
+				server.URL, r.URL.Path, server.URL, r.URL.Path))
+			w.Write([]byte(`{"workflow_runs": [{"id": 1}, {"id": 2}]}`))
+		case "2":
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s?per_page=100&page=1>; rel="first"`, server.URL, r.URL.Path))
+			w.Write([]byte(`{"workflow_runs": [{"id": 3}]}`))
+		default:
+			t.Errorf("unexpected page %q", r.URL.Query().Get("page"))
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		token:   "test-token",
+		baseURL: server.URL,
+		httpClient: &http.Client{
+			Timeout: time.Second * 30,
+		},
+	}
+
+	runs, err := client.ListWorkflowRuns(context.Background(), "owner", "repo", "sync.yml")
+	require.NoError(t, err)
+	require.Len(t, runs, 3)
+	for i, run := range runs {
+		assert.Equal(t, int64(i+1), run.ID)
+	}
+}
+
+func TestLatestWorkflowRun(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "1", r.URL.Query().Get("per_page"))
+		if r.URL.Path == "/repos/owner/idle/actions/workflows/sync.yml/runs" {
+			w.Write([]byte(`{"workflow_runs": []}`))
+			return
+		}
+		// The next page must not be followed
+		w.Header().Set("Link", `<http://example.invalid/runs?per_page=1&page=2>; rel="next"`)
+		w.Write([]byte(`{"workflow_runs": [{"id": 42, "status": "queued"}]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		token:      "test-token",
+		baseURL:    server.URL,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+
+	run, err := client.LatestWorkflowRun(context.Background(), "owner", "repo", "sync.yml")
+	require.NoError(t, err)
+	require.NotNil(t, run)
+	assert.Equal(t, int64(42), run.ID)
+	assert.Equal(t, 1, requests)
+
+	run, err = client.LatestWorkflowRun(context.Background(), "owner", "idle", "sync.yml")
+	require.NoError(t, err)
+	assert.Nil(t, run)
+}
+
+func TestNextPageURL(t *testing.T) {
+	tests := []struct {
+		link string
+		want string
+	}{
+		{`<https://api.github.com/x?page=2>; rel="next", <https://api.github.com/x?page=5>; rel="last"`, "https://api.github.com/x?page=2"},
+		{`<https://api.github.com/x?page=1>; rel="prev", <https://api.github.com/x?page=3>; rel="next"`, "https://api.github.com/x?page=3"},
+		{`<https://api.github.com/x?page=1>; rel="first"`, ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, nextPageURL(tt.link), tt.link)
+	}
+}
@@ -0,0 +1,174 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxSecondaryRetryAfter caps how long a secondary rate limit may pause a request
+	maxSecondaryRetryAfter = 2 * time.Minute
+
+	// DefaultMaxRateLimitWait is the longest WithWaitOnRateLimit blocks for
+	// a primary rate limit reset unless given a different cap
+	DefaultMaxRateLimitWait = 15 * time.Minute
+)
+
+// isRateLimited reports whether a response was rejected by the primary or
+// secondary rate limiter
+func isRateLimited(resp *http.Response) bool {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if resp.StatusCode != http.StatusForbidden {
+		return false
+	}
+	return resp.Header.Get("X-RateLimit-Remaining") == "0" || resp.Header.Get("Retry-After") != ""
+}
+
+// secondaryRetryAfter returns how long to wait before retrying a request
+// rejected by GitHub's secondary (abuse) rate limit. These responses carry
+// a Retry-After header in seconds, unlike primary limits which are reported
+// through X-RateLimit-Reset.
+func secondaryRetryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	delay := time.Duration(seconds) * time.Second
+	if delay > maxSecondaryRetryAfter {
+		delay = maxSecondaryRetryAfter
+	}
+	return delay, true
+}
+
+// WithWaitOnRateLimit makes requests rejected because the primary rate limit
+// is exhausted block until the limit resets and then retry once, instead of
+// failing. Resets further away than max fail immediately; a max of zero or
+// less uses DefaultMaxRateLimitWait. Waiting stops if the request's context
+// is cancelled.
+func WithWaitOnRateLimit(max time.Duration) ClientOption {
+	return func(c *Client) {
+		if max <= 0 {
+			max = DefaultMaxRateLimitWait
+		}
+		c.maxRateWait = max
+	}
+}
+
+// primaryRetryAfter returns how long to wait for the primary rate limit to
+// reset when waiting is enabled and resp was rejected by it. The reset time
+// comes from X-RateLimit-Reset, or from GetRateLimit if the header is missing.
+func (c *Client) primaryRetryAfter(req *http.Request, resp *http.Response) (time.Duration, bool) {
+	if c.maxRateWait <= 0 {
+		return 0, false
+	}
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return 0, false
+	}
+
+	var reset time.Time
+	if seconds, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		reset = time.Unix(seconds, 0)
+	} else if !strings.HasSuffix(req.URL.Path, "/rate_limit") {
+		limits, err := c.GetRateLimit(req.Context())
+		if err != nil {
+			return 0, false
+		}
+		reset = limits.Core.Reset
+	} else {
+		return 0, false
+	}
+
+	delay := time.Until(reset)
+	if delay < 0 {
+		delay = 0
+	}
+	if delay > c.maxRateWait {
+		return 0, false
+	}
+	return delay, true
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// RateLimitResource is the quota for one API resource
+type RateLimitResource struct {
+	Limit     int
+	Used      int
+	Remaining int
+	Reset     time.Time // When Remaining is restored to Limit
+}
+
+// UnmarshalJSON decodes a resource whose reset time is given in Unix seconds
+func (r *RateLimitResource) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Limit     int   `json:"limit"`
+		Used      int   `json:"used"`
+		Remaining int   `json:"remaining"`
+		Reset     int64 `json:"reset"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*r = RateLimitResource{
+		Limit:     raw.Limit,
+		Used:      raw.Used,
+		Remaining: raw.Remaining,
+		Reset:     time.Unix(raw.Reset, 0),
+	}
+	return nil
+}
+
+// RateLimit reports the quotas of the REST, search and GraphQL APIs
+type RateLimit struct {
+	Core    RateLimitResource `json:"core"`
+	Search  RateLimitResource `json:"search"`
+	GraphQL RateLimitResource `json:"graphql"`
+}
+
+// GetRateLimit returns the authenticated user's current API quotas.
+// Checking it does not count against the core limit.
+func (c *Client) GetRateLimit(ctx context.Context) (RateLimit, error) {
+	url := fmt.Sprintf("%s/rate_limit", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return RateLimit{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.sendRequest(req)
+	if err != nil {
+		return RateLimit{}, fmt.Errorf("failed to get rate limit: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Resources RateLimit `json:"resources"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return RateLimit{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return response.Resources, nil
+}
@@ -0,0 +1,140 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rate_limit", r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{
+			"resources": {
+				"core": {"limit": 5000, "used": 1, "remaining": 4999, "reset": 1691591363},
+				"search": {"limit": 30, "used": 12, "remaining": 18, "reset": 1691591091},
+				"graphql": {"limit": 5000, "used": 7, "remaining": 4993, "reset": 1691593228}
+			},
+			"rate": {"limit": 5000, "used": 1, "remaining": 4999, "reset": 1691591363}
+		}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		token:   "test-token",
+		baseURL: server.URL,
+		httpClient: &http.Client{
+			Timeout: time.Second * 30,
+		},
+	}
+
+	limits, err := client.GetRateLimit(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, RateLimitResource{Limit: 5000, Used: 1, Remaining: 4999, Reset: time.Unix(1691591363, 0)}, limits.Core)
+	assert.Equal(t, 18, limits.Search.Remaining)
+	assert.Equal(t, time.Unix(1691591091, 0), limits.Search.Reset)
+	assert.Equal(t, 4993, limits.GraphQL.Remaining)
+}
+
+func TestWaitOnRateLimit(t *testing.T) {
+	var requests int
+	reset := time.Now().Add(time.Second)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if time.Now().Before(reset) {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix()+1, 10))
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "API rate limit exceeded"}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"login": "testuser"}`))
+	}))
+	defer server.Close()
+
+	metrics := &capturingMetrics{}
+	client := &Client{
+		token:   "test-token",
+		baseURL: server.URL,
+		httpClient: &http.Client{
+			Timeout: time.Second * 30,
+		},
+	}
+	WithMetrics(metrics)(client)
+	WithWaitOnRateLimit(time.Minute)(client)
+
+	info, err := client.GetUserInfo(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "testuser", info.Login)
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, 1, metrics.retries)
+}
+
+func TestWaitOnRateLimitCap(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message": "API rate limit exceeded"}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		token:   "test-token",
+		baseURL: server.URL,
+		httpClient: &http.Client{
+			Timeout: time.Second * 30,
+		},
+	}
+	WithWaitOnRateLimit(time.Minute)(client)
+
+	start := time.Now()
+	_, err := client.GetUserInfo(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 1, requests)
+	assert.Less(t, time.Since(start), time.Second, "resets beyond the cap must fail without waiting")
+}
+
+func TestWaitOnRateLimitFromRateLimitEndpoint(t *testing.T) {
+	var userRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rate_limit" {
+			w.Write([]byte(`{"resources": {"core": {"limit": 5000, "remaining": 0, "reset": ` +
+				strconv.FormatInt(time.Now().Unix(), 10) + `}}}`))
+			return
+		}
+		userRequests++
+		if userRequests == 1 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"login": "testuser"}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		token:   "test-token",
+		baseURL: server.URL,
+		httpClient: &http.Client{
+			Timeout: time.Second * 30,
+		},
+	}
+	WithWaitOnRateLimit(0)(client)
+	assert.Equal(t, DefaultMaxRateLimitWait, client.maxRateWait)
+
+	_, err := client.GetUserInfo(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, userRequests)
+}
@@ -0,0 +1,271 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrRepositoryExists is returned by CreateRepository when the
+// authenticated user already has a repository with the requested name
+var ErrRepositoryExists = errors.New("repository already exists")
+
+// Repository describes an existing repository
+type Repository struct {
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	SizeKB        int64  `json:"size"` // Reported by GitHub in KiB
+	CloneURL      string `json:"clone_url"`
+	DefaultBranch string `json:"default_branch"`
+	Private       bool   `json:"private"`
+	Fork          bool   `json:"fork"`
+}
+
+// SizeBytes returns the repository size in bytes, suitable for
+// git.CloneOptions.EstimatedSize
+func (r *Repository) SizeBytes() int64 {
+	return r.SizeKB * 1024
+}
+
+// GetRepository returns the size, clone URL and settings of a repository
+func (c *Client) GetRepository(ctx context.Context, owner, repo string) (*Repository, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s", c.baseURL, owner, repo)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.sendRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var repository Repository
+	if err := json.NewDecoder(resp.Body).Decode(&repository); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &repository, nil
+}
+
+// RepositoryExists reports whether a repository exists and is visible to
+// the authenticated user
+func (c *Client) RepositoryExists(ctx context.Context, owner, repo string) (bool, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s", c.baseURL, owner, repo)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.sendRequest(req)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get repository: %w", err)
+	}
+	resp.Body.Close()
+
+	return true, nil
+}
+
+// DeleteRepository permanently deletes a repository. The token needs the
+// delete_repo scope.
+func (c *Client) DeleteRepository(ctx context.Context, owner, repo string) error {
+	url := fmt.Sprintf("%s/repos/%s/%s", c.baseURL, owner, repo)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.sendRequest(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete repository: %w", err)
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// RepoResultStatus classifies the outcome of creating one repository
+type RepoResultStatus string
+
+const (
+	RepoCreated RepoResultStatus = "created"
+	RepoSkipped RepoResultStatus = "skipped" // the repository already existed
+	RepoFailed  RepoResultStatus = "failed"
+)
+
+// RepoResult reports what happened to one repository in a batch
+type RepoResult struct {
+	Name   string
+	Status RepoResultStatus
+	Err    error // set for skipped and failed repositories
+}
+
+// CreateRepositories creates each repository in turn. A repository that
+// already exists is reported as skipped and other failures as failed; neither
+// stops the batch. Results are returned in the order of opts.
+func (c *Client) CreateRepositories(ctx context.Context, opts []RepoOptions) []RepoResult {
+	results := make([]RepoResult, 0, len(opts))
+	for _, o := range opts {
+		result := RepoResult{Name: o.Name, Status: RepoCreated}
+		if err := ctx.Err(); err != nil {
+			result.Status, result.Err = RepoFailed, err
+		} else if err := c.CreateRepository(ctx, o); err != nil {
+			result.Status, result.Err = RepoFailed, err
+			if errors.Is(err, ErrRepositoryExists) {
+				result.Status = RepoSkipped
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// RepoPatch lists repository settings to change with UpdateRepository.
+// Nil fields are left unchanged.
+type RepoPatch struct {
+	Private       *bool   `json:"private,omitempty"`
+	Description   *string `json:"description,omitempty"`
+	DefaultBranch *string `json:"default_branch,omitempty"`
+	Archived      *bool   `json:"archived,omitempty"`
+}
+
+// UpdateRepository changes the settings of an existing repository
+func (c *Client) UpdateRepository(ctx context.Context, owner, repo string, patch RepoPatch) error {
+	url := fmt.Sprintf("%s/repos/%s/%s", c.baseURL, owner, repo)
+	jsonBody, err := json.Marshal(patch)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewReader(jsonBody))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.sendRequest(req)
+	if err != nil {
+		return fmt.Errorf("failed to update repository: %w", err)
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// ArchiveRepository makes a repository read-only. Pushes to an archived
+// repository are rejected until it is unarchived.
+func (c *Client) ArchiveRepository(ctx context.Context, owner, repo string) error {
+	archived := true
+	if err := c.UpdateRepository(ctx, owner, repo, RepoPatch{Archived: &archived}); err != nil {
+		return fmt.Errorf("failed to archive repository: %w", err)
+	}
+	return nil
+}
+
+// UnarchiveRepository makes an archived repository writable again
+func (c *Client) UnarchiveRepository(ctx context.Context, owner, repo string) error {
+	archived := false
+	if err := c.UpdateRepository(ctx, owner, repo, RepoPatch{Archived: &archived}); err != nil {
+		return fmt.Errorf("failed to unarchive repository: %w", err)
+	}
+	return nil
+}
+
+// ReplaceTopics sets the repository's topics to exactly the given list.
+// An empty list removes all topics.
+func (c *Client) ReplaceTopics(ctx context.Context, owner, repo string, topics []string) error {
+	url := fmt.Sprintf("%s/repos/%s/%s/topics", c.baseURL, owner, repo)
+	if topics == nil {
+		topics = []string{}
+	}
+	jsonBody, err := json.Marshal(struct {
+		Names []string `json:"names"`
+	}{Names: topics})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewReader(jsonBody))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.sendRequest(req)
+	if err != nil {
+		return fmt.Errorf("failed to replace topics: %w", err)
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// collaboratorPermissions lists the repository roles accepted by AddCollaborator
+var collaboratorPermissions = map[string]bool{
+	"pull":     true,
+	"triage":   true,
+	"push":     true,
+	"maintain": true,
+	"admin":    true,
+}
+
+// AddCollaborator grants username the given permission on a repository.
+// It reports true when GitHub sent the user an invitation and false when
+// they were already a collaborator, in which case their permission is
+// updated. An empty permission defaults to "push".
+func (c *Client) AddCollaborator(ctx context.Context, owner, repo, username, permission string) (invited bool, err error) {
+	if username == "" {
+		return false, fmt.Errorf("collaborator username is required")
+	}
+	if permission == "" {
+		permission = "push"
+	}
+	if !collaboratorPermissions[permission] {
+		return false, fmt.Errorf("invalid permission %q: must be one of pull, triage, push, maintain, admin", permission)
+	}
+
+	jsonBody, err := json.Marshal(map[string]string{"permission": permission})
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/%s/collaborators/%s", c.baseURL, owner, repo, username)
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewReader(jsonBody))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.sendRequest(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to add collaborator: %w", err)
+	}
+	resp.Body.Close()
+
+	// 201 means an invitation was created; 204 means the user already had access
+	return resp.StatusCode == http.StatusCreated, nil
+}
+
+// isRepositoryExists reports whether a failed create request was rejected
+// because the name is taken. GitHub answers 422 with a validation message
+// such as "name already exists on this account".
+func isRepositoryExists(resp *http.Response, err error) bool {
+	if resp == nil || resp.StatusCode != http.StatusUnprocessableEntity {
+		return false
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, detail := range apiErr.Details() {
+		if strings.Contains(detail, "already exists") {
+			return true
+		}
+	}
+	return false
+}
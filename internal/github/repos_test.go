@@ -0,0 +1,304 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateRepositories(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/user/repos", r.URL.Path)
+
+		var opts RepoOptions
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&opts))
+		switch opts.Name {
+		case "existing":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"message": "Repository creation failed.", "errors": [{"resource": "Repository", "code": "custom", "field": "name", "message": "name already exists on this account"}]}`))
+		case "forbidden":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "Resource not accessible by integration"}`))
+		default:
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"name": "` + opts.Name + `"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		token:   "test-token",
+		baseURL: server.URL,
+		httpClient: &http.Client{
+			Timeout: time.Second * 30,
+		},
+	}
+
+	results := client.CreateRepositories(context.Background(), []RepoOptions{
+		{Name: "mirror-a", Private: true},
+		{Name: "existing", Private: true},
+		{Name: "forbidden", Private: true},
+		{Name: "mirror-b", Private: true},
+	})
+
+	require.Len(t, results, 4)
+	assert.Equal(t, RepoResult{Name: "mirror-a", Status: RepoCreated}, results[0])
+	assert.Equal(t, RepoSkipped, results[1].Status)
+	assert.True(t, errors.Is(results[1].Err, ErrRepositoryExists))
+	assert.Equal(t, RepoFailed, results[2].Status)
+	assert.False(t, errors.Is(results[2].Err, ErrRepositoryExists))
+	assert.Equal(t, RepoResult{Name: "mirror-b", Status: RepoCreated}, results[3])
+}
+
+func TestCreateRepositoriesCanceled(t *testing.T) {
+	client := &Client{
+		token:      "test-token",
+		baseURL:    "http://127.0.0.1:0",
+		httpClient: &http.Client{},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := client.CreateRepositories(ctx, []RepoOptions{{Name: "a"}, {Name: "b"}})
+	require.Len(t, results, 2)
+	for _, r := range results {
+		assert.Equal(t, RepoFailed, r.Status)
+		assert.True(t, errors.Is(r.Err, context.Canceled))
+	}
+}
+
+func TestUpdateRepositoryVisibility(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PATCH", r.Method)
+		assert.Equal(t, "/repos/owner/mirror", r.URL.Path)
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"name": "mirror", "private": false}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		token:   "test-token",
+		baseURL: server.URL,
+		httpClient: &http.Client{
+			Timeout: time.Second * 30,
+		},
+	}
+
+	private := false
+	err := client.UpdateRepository(context.Background(), "owner", "mirror", RepoPatch{Private: &private})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"private": false}`, body)
+}
+
+func TestReplaceTopics(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		assert.Equal(t, "/repos/owner/mirror/topics", r.URL.Path)
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"names": ["mirror", "go"]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		token:   "test-token",
+		baseURL: server.URL,
+		httpClient: &http.Client{
+			Timeout: time.Second * 30,
+		},
+	}
+
+	err := client.ReplaceTopics(context.Background(), "owner", "mirror", []string{"mirror", "go"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"names": ["mirror", "go"]}`, body)
+
+	err = client.ReplaceTopics(context.Background(), "owner", "mirror", nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"names": []}`, body)
+}
+
+func TestArchiveRepository(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PATCH", r.Method)
+		assert.Equal(t, "/repos/owner/mirror", r.URL.Path)
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"name": "mirror"}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		token:   "test-token",
+		baseURL: server.URL,
+		httpClient: &http.Client{
+			Timeout: time.Second * 30,
+		},
+	}
+
+	require.NoError(t, client.ArchiveRepository(context.Background(), "owner", "mirror"))
+	require.NoError(t, client.UnarchiveRepository(context.Background(), "owner", "mirror"))
+	require.Len(t, bodies, 2)
+	assert.JSONEq(t, `{"archived": true}`, bodies[0])
+	assert.JSONEq(t, `{"archived": false}`, bodies[1])
+}
+
+func TestAddCollaborator(t *testing.T) {
+	var permission string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		permission = body["permission"]
+
+		switch r.URL.Path {
+		case "/repos/owner/mirror/collaborators/newuser":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 1, "permissions": "write"}`))
+		case "/repos/owner/mirror/collaborators/member":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		token:   "test-token",
+		baseURL: server.URL,
+		httpClient: &http.Client{
+			Timeout: time.Second * 30,
+		},
+	}
+
+	invited, err := client.AddCollaborator(context.Background(), "owner", "mirror", "newuser", "maintain")
+	require.NoError(t, err)
+	assert.True(t, invited)
+	assert.Equal(t, "maintain", permission)
+
+	invited, err = client.AddCollaborator(context.Background(), "owner", "mirror", "member", "")
+	require.NoError(t, err)
+	assert.False(t, invited)
+	assert.Equal(t, "push", permission)
+
+	_, err = client.AddCollaborator(context.Background(), "owner", "mirror", "member", "write")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid permission "write"`)
+}
+
+func TestGetRepository(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/repos/octocat/Hello-World", r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{
+			"id": 1296269,
+			"name": "Hello-World",
+			"full_name": "octocat/Hello-World",
+			"private": false,
+			"fork": true,
+			"clone_url": "https://github.com/octocat/Hello-World.git",
+			"ssh_url": "git@github.com:octocat/Hello-World.git",
+			"size": 108,
+			"default_branch": "master"
+		}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		token:   "test-token",
+		baseURL: server.URL,
+		httpClient: &http.Client{
+			Timeout: time.Second * 30,
+		},
+	}
+
+	repo, err := client.GetRepository(context.Background(), "octocat", "Hello-World")
+	require.NoError(t, err)
+	assert.Equal(t, &Repository{
+		Name:          "Hello-World",
+		FullName:      "octocat/Hello-World",
+		SizeKB:        108,
+		CloneURL:      "https://github.com/octocat/Hello-World.git",
+		DefaultBranch: "master",
+		Private:       false,
+		Fork:          true,
+	}, repo)
+	assert.Equal(t, int64(108*1024), repo.SizeBytes())
+}
+
+func TestRepositoryExists(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		switch r.URL.Path {
+		case "/repos/owner/present":
+			w.Write([]byte(`{"name": "present"}`))
+		case "/repos/owner/missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "Not Found"}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		token:      "test-token",
+		baseURL:    server.URL,
+		httpClient: &http.Client{Timeout: time.Second * 30},
+	}
+
+	exists, err := client.RepositoryExists(context.Background(), "owner", "present")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = client.RepositoryExists(context.Background(), "owner", "missing")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	_, err = client.RepositoryExists(context.Background(), "owner", "broken")
+	assert.ErrorContains(t, err, "failed to get repository")
+}
+
+func TestDeleteRepository(t *testing.T) {
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method)
+		if r.URL.Path == "/repos/owner/forbidden" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "Must have admin rights to Repository."}`))
+			return
+		}
+		deleted = append(deleted, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		token:      "test-token",
+		baseURL:    server.URL,
+		httpClient: &http.Client{Timeout: time.Second * 30},
+	}
+
+	require.NoError(t, client.DeleteRepository(context.Background(), "owner", "fork"))
+	assert.Equal(t, []string{"/repos/owner/fork"}, deleted)
+
+	err := client.DeleteRepository(context.Background(), "owner", "forbidden")
+	assert.ErrorContains(t, err, "failed to delete repository")
+}
@@ -0,0 +1,71 @@
+package github
+
+import (
+	"sync"
+	"time"
+)
+
+// RetryBudget bounds how many retries the clients sharing it may make
+// within a window. It is a token bucket holding up to max retries that
+// refills at max per window; once it is empty, requests that would be
+// retried fail with their original response instead. It is safe for
+// concurrent use.
+type RetryBudget struct {
+	max    float64
+	window time.Duration
+	now    func() time.Time // replaced in tests
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewRetryBudget creates a full budget allowing max retries per window
+func NewRetryBudget(max int, window time.Duration) *RetryBudget {
+	if max < 0 {
+		max = 0
+	}
+	b := &RetryBudget{
+		max:    float64(max),
+		window: window,
+		now:    time.Now,
+		tokens: float64(max),
+	}
+	b.last = b.now()
+	return b
+}
+
+// WithRetryBudget makes the client spend a token from budget before every
+// retry and skip the retry once the budget is exhausted. Share one budget
+// between clients to bound their retries together.
+func WithRetryBudget(budget *RetryBudget) ClientOption {
+	return func(c *Client) {
+		c.retryBudget = budget
+	}
+}
+
+// Allow takes one retry from the budget, reporting false if none is left.
+// A nil budget always allows.
+func (b *RetryBudget) Allow() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if b.window > 0 {
+		elapsed := now.Sub(b.last)
+		b.tokens += b.max * float64(elapsed) / float64(b.window)
+		if b.tokens > b.max {
+			b.tokens = b.max
+		}
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,72 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryBudgetExhausted(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message": "You have exceeded a secondary rate limit"}`))
+	}))
+	defer server.Close()
+
+	metrics := &capturingMetrics{}
+	client := &Client{
+		token:      "test-token",
+		baseURL:    server.URL,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+	WithMetrics(metrics)(client)
+	WithRetryBudget(NewRetryBudget(2, time.Hour))(client)
+
+	// Each of the first two calls spends one retry
+	for i := 0; i < 2; i++ {
+		_, err := client.GetUserInfo(context.Background())
+		assert.Error(t, err)
+	}
+	assert.Equal(t, int32(4), atomic.LoadInt32(&requests))
+	assert.Equal(t, 2, metrics.retries)
+
+	// With the budget exhausted, later calls fail without retrying
+	for i := 0; i < 3; i++ {
+		_, err := client.GetUserInfo(context.Background())
+		assert.Error(t, err)
+	}
+	assert.Equal(t, int32(7), atomic.LoadInt32(&requests))
+	assert.Equal(t, 2, metrics.retries)
+}
+
+func TestRetryBudgetRefills(t *testing.T) {
+	now := time.Unix(0, 0)
+	budget := NewRetryBudget(2, time.Minute)
+	budget.now = func() time.Time { return now }
+	budget.last = now
+
+	assert.True(t, budget.Allow())
+	assert.True(t, budget.Allow())
+	assert.False(t, budget.Allow())
+
+	now = now.Add(30 * time.Second)
+	assert.True(t, budget.Allow())
+	assert.False(t, budget.Allow())
+
+	// Refilling never exceeds the budget's size
+	now = now.Add(time.Hour)
+	assert.True(t, budget.Allow())
+	assert.True(t, budget.Allow())
+	assert.False(t, budget.Allow())
+
+	var nilBudget *RetryBudget
+	assert.True(t, nilBudget.Allow())
+}
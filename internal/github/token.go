@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/NicabarNimble/go-gittools/internal/token"
 )
@@ -17,9 +18,27 @@ const (
 	ScopeWorkflow  = "workflow"
 )
 
+// requiredScopes lists the classic OAuth scopes a token must carry
+var requiredScopes = []string{ScopeRepo, ScopeWorkflow}
+
+// fineGrainedPrefix identifies fine-grained personal access tokens, which do
+// not report classic OAuth scopes in the X-OAuth-Scopes header
+const fineGrainedPrefix = "github_pat_"
+
+// accessProbePath is the endpoint used to confirm a fine-grained token can
+// reach repository resources
+const accessProbePath = "/user/repos?per_page=1"
+
 // TokenValidator implements token.Validator for GitHub tokens
 type TokenValidator struct {
-	baseURL string
+	baseURL  string
+	required []string // nil means requiredScopes
+}
+
+func init() {
+	token.RegisterValidator(token.ProviderGitHub, func() token.Validator {
+		return NewTokenValidator()
+	})
 }
 
 // NewTokenValidator creates a new GitHub token validator
@@ -29,6 +48,25 @@ func NewTokenValidator() *TokenValidator {
 	}
 }
 
+// NewTokenValidatorWithScopes creates a GitHub token validator that requires
+// only the given classic OAuth scopes, for tools that need less access than
+// the default set. An empty list falls back to the default scopes.
+func NewTokenValidatorWithScopes(required []string) *TokenValidator {
+	v := NewTokenValidator()
+	if len(required) > 0 {
+		v.required = append([]string(nil), required...)
+	}
+	return v
+}
+
+// requiredScopes returns the scopes this validator checks for
+func (v *TokenValidator) requiredScopes() []string {
+	if len(v.required) > 0 {
+		return v.required
+	}
+	return requiredScopes
+}
+
 // Validate checks if a token is valid for GitHub Actions operations
 func (v *TokenValidator) Validate(ctx context.Context, t *token.Token) error {
 	if t.Value == "" {
@@ -44,6 +82,15 @@ func (v *TokenValidator) Validate(ctx context.Context, t *token.Token) error {
 		return fmt.Errorf("token verification failed: %w", err)
 	}
 
+	// Fine-grained tokens carry permissions instead of scopes, so confirm
+	// access by probing an endpoint rather than checking the scopes header
+	if IsFineGrained(t.Value) {
+		if err := v.probeAccess(ctx, t); err != nil {
+			return fmt.Errorf("token access check failed: %w", err)
+		}
+		return nil
+	}
+
 	// Check if required scopes are present
 	if err := v.validateScopes(t.Scope); err != nil {
 		return fmt.Errorf("invalid token scope: %w", err)
@@ -52,48 +99,68 @@ func (v *TokenValidator) Validate(ctx context.Context, t *token.Token) error {
 	return nil
 }
 
-// validateScopes checks if the token has the required scopes
-func (v *TokenValidator) validateScopes(scope string) error {
-	if scope == "" {
-		return fmt.Errorf("no scopes provided")
+// ScopeStatus verifies the token with the GitHub API and reports which required
+// scopes are present. Unlike Validate, missing scopes are not treated as an error,
+// so callers can render the full scope status even for fully-scoped tokens.
+func (v *TokenValidator) ScopeStatus(ctx context.Context, t *token.Token) (map[string]bool, error) {
+	if t.Value == "" {
+		return nil, token.ErrTokenInvalid
 	}
 
-	scopes := strings.Split(scope, ",")
-	// Trim spaces from each scope
-	for i, s := range scopes {
-		scopes[i] = strings.TrimSpace(s)
-	}
-	required := map[string]bool{
-		ScopeRepo:     false,
-		ScopeWorkflow: false,
+	if err := v.verifyToken(ctx, t); err != nil {
+		return nil, fmt.Errorf("token verification failed: %w", err)
 	}
 
-	for _, s := range scopes {
-		if _, ok := required[s]; ok {
-			required[s] = true
-		}
+	return scopeStatus(t.Scope, v.requiredScopes()), nil
+}
+
+// validateScopes checks if the token has the required scopes
+func (v *TokenValidator) validateScopes(scope string) error {
+	if scope == "" {
+		return fmt.Errorf("no scopes provided")
 	}
 
 	// Return detailed scope status
+	required := v.requiredScopes()
+	status := scopeStatus(scope, required)
 	var missingScopes []string
-	scopeStatus := make(map[string]bool)
-	for scope, present := range required {
-		scopeStatus[scope] = present
-		if !present {
-			missingScopes = append(missingScopes, scope)
+	for _, s := range required {
+		if !status[s] {
+			missingScopes = append(missingScopes, s)
 		}
 	}
 
 	if len(missingScopes) > 0 {
 		return &token.ScopeError{
 			Missing: missingScopes,
-			Status:  scopeStatus,
+			Status:  status,
 		}
 	}
 
 	return nil
 }
 
+// scopeStatus maps each required scope to whether it appears in the scope
+// list. GitHub reports scopes comma-separated while stored tokens use spaces,
+// so both separators are accepted.
+func scopeStatus(scope string, required []string) map[string]bool {
+	status := make(map[string]bool, len(required))
+	for _, s := range required {
+		status[s] = false
+	}
+
+	fields := strings.FieldsFunc(scope, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+	for _, s := range fields {
+		if _, ok := status[s]; ok {
+			status[s] = true
+		}
+	}
+
+	return status
+}
+
 // verifyToken makes a test API call to verify the token and get its scopes
 func (v *TokenValidator) verifyToken(ctx context.Context, t *token.Token) error {
 	req, err := http.NewRequestWithContext(ctx, "GET", v.baseURL+"/user", nil)
@@ -124,12 +191,14 @@ func (v *TokenValidator) verifyToken(ctx context.Context, t *token.Token) error
 
 	// Get scopes from response header
 	scopes := resp.Header.Get("X-OAuth-Scopes")
-	if scopes == "" {
+	if scopes == "" && !IsFineGrained(t.Value) {
 		return fmt.Errorf("no scopes found in token")
 	}
 
 	// Update token scope with actual scopes from GitHub
-	t.Scope = scopes
+	if scopes != "" {
+		t.Scope = scopes
+	}
 
 	// Get expiration from response header
 	if expStr := resp.Header.Get("GitHub-Authentication-Token-Expiration"); expStr != "" {
@@ -143,3 +212,34 @@ func (v *TokenValidator) verifyToken(ctx context.Context, t *token.Token) error
 
 	return nil
 }
+
+// probeAccess makes a request to a representative repository endpoint to
+// confirm the token has access beyond basic authentication
+func (v *TokenValidator) probeAccess(ctx context.Context, t *token.Token) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", v.baseURL+accessProbePath, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+t.Value)
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("User-Agent", userAgent)
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("repository access denied: status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// IsFineGrained reports whether a token value is a fine-grained personal access token
+func IsFineGrained(value string) bool {
+	return strings.HasPrefix(value, fineGrainedPrefix)
+}
@@ -2,6 +2,7 @@ package github
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -113,6 +114,32 @@ func TestTokenValidator_Validate(t *testing.T) {
 	}
 }
 
+func TestTokenValidator_PartialScopeIs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-OAuth-Scopes", "repo")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"login": "testuser"}`))
+	}))
+	defer server.Close()
+
+	v := &TokenValidator{baseURL: server.URL}
+	tok := token.Token{
+		Value:     "limited_token",
+		ExpiresAt: time.Now().Add(24 * time.Hour),
+	}
+
+	err := v.Validate(context.Background(), &tok)
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, token.ErrScopeMissing))
+
+	var scopeErr *token.ScopeError
+	if assert.True(t, errors.As(err, &scopeErr)) {
+		assert.Equal(t, []string{ScopeWorkflow}, scopeErr.Missing)
+		assert.True(t, scopeErr.Status[ScopeRepo])
+		assert.False(t, scopeErr.Status[ScopeWorkflow])
+	}
+}
+
 func TestTokenValidator_ValidateScopes(t *testing.T) {
 	v := NewTokenValidator()
 
@@ -161,3 +188,175 @@ func TestTokenValidator_ValidateScopes(t *testing.T) {
 		})
 	}
 }
+
+func TestNewTokenValidatorWithScopes(t *testing.T) {
+	v := NewTokenValidatorWithScopes([]string{ScopeRepo})
+
+	tests := []struct {
+		name      string
+		scope     string
+		wantError bool
+		errorMsg  string
+	}{
+		{
+			name:      "repo only",
+			scope:     "repo",
+			wantError: false,
+		},
+		{
+			name:      "comma-separated header scopes",
+			scope:     "repo, read:org",
+			wantError: false,
+		},
+		{
+			name:      "missing repo scope",
+			scope:     "workflow",
+			wantError: true,
+			errorMsg:  "missing required scopes: repo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.validateScopes(tt.scope)
+
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, token.ErrScopeMissing))
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	// ScopeStatus only reports the reduced set
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-OAuth-Scopes", "repo")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	v.baseURL = server.URL
+
+	status, err := v.ScopeStatus(context.Background(), &token.Token{Value: "repo_token"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{ScopeRepo: true}, status)
+
+	// An empty list keeps the default scopes
+	assert.Equal(t, requiredScopes, NewTokenValidatorWithScopes(nil).requiredScopes())
+}
+
+func TestTokenValidator_FineGrained(t *testing.T) {
+	tests := []struct {
+		name        string
+		probeStatus int
+		wantError   bool
+		errorMsg    string
+	}{
+		{
+			name:        "scopes header absent with repository access",
+			probeStatus: http.StatusOK,
+			wantError:   false,
+		},
+		{
+			name:        "scopes header absent without repository access",
+			probeStatus: http.StatusForbidden,
+			wantError:   true,
+			errorMsg:    "token access check failed: repository access denied: status 403",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/user":
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(`{"login": "testuser"}`))
+				case "/user/repos":
+					w.WriteHeader(tt.probeStatus)
+					w.Write([]byte(`[]`))
+				default:
+					t.Fatalf("unexpected request to %s", r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			v := &TokenValidator{
+				baseURL: server.URL,
+			}
+
+			tok := token.Token{
+				Value:     "github_pat_11ABCDEFG",
+				ExpiresAt: time.Now().Add(24 * time.Hour),
+			}
+
+			err := v.Validate(context.Background(), &tok)
+
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestIsFineGrained(t *testing.T) {
+	assert.True(t, IsFineGrained("github_pat_11ABCDEFG"))
+	assert.False(t, IsFineGrained("ghp_abcdefg"))
+	assert.False(t, IsFineGrained(""))
+}
+
+func TestTokenValidator_ScopeStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		scopes     string
+		wantStatus map[string]bool
+	}{
+		{
+			name:   "fully scoped token",
+			scopes: "repo, workflow, admin:repo",
+			wantStatus: map[string]bool{
+				ScopeRepo:     true,
+				ScopeWorkflow: true,
+			},
+		},
+		{
+			name:   "partially scoped token",
+			scopes: "repo",
+			wantStatus: map[string]bool{
+				ScopeRepo:     true,
+				ScopeWorkflow: false,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-OAuth-Scopes", tt.scopes)
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"login": "testuser"}`))
+			}))
+			defer server.Close()
+
+			v := &TokenValidator{
+				baseURL: server.URL,
+			}
+
+			tok := token.Token{Value: "ghp_test"}
+			status, err := v.ScopeStatus(context.Background(), &tok)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, status)
+		})
+	}
+}
+
+func TestValidatorForGitHub(t *testing.T) {
+	v, err := token.ValidatorFor(token.ProviderGitHub)
+	assert.NoError(t, err)
+	assert.IsType(t, &TokenValidator{}, v)
+}
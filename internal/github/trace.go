@@ -0,0 +1,74 @@
+package github
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"time"
+)
+
+// RequestTiming breaks down where time was spent during a single request.
+// Phases that did not happen (e.g. DNS and connect on a reused connection)
+// are left at zero.
+type RequestTiming struct {
+	Method    string
+	URL       string
+	DNS       time.Duration
+	Connect   time.Duration
+	TLS       time.Duration
+	FirstByte time.Duration // Time from request start to the first response byte
+	Reused    bool          // Whether an idle connection was reused
+}
+
+// TraceFunc receives the timing breakdown of each request
+type TraceFunc func(RequestTiming)
+
+// WithTracing attaches an httptrace.ClientTrace to every request and reports
+// the collected timings to fn once the response headers have been received
+func WithTracing(fn TraceFunc) ClientOption {
+	return func(c *Client) {
+		c.trace = fn
+	}
+}
+
+// requestTracer collects timings for a single request
+type requestTracer struct {
+	timing                        RequestTiming
+	start, dnsStart, connectStart time.Time
+	tlsStart                      time.Time
+}
+
+// withTrace returns req with a ClientTrace attached to its context
+func (t *requestTracer) withTrace(req *http.Request) *http.Request {
+	t.timing.Method = req.Method
+	t.timing.URL = req.URL.String()
+	t.start = time.Now()
+
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.timing.Reused = info.Reused
+		},
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.timing.DNS = time.Since(t.dnsStart)
+		},
+		ConnectStart: func(string, string) {
+			t.connectStart = time.Now()
+		},
+		ConnectDone: func(string, string, error) {
+			t.timing.Connect = time.Since(t.connectStart)
+		},
+		TLSHandshakeStart: func() {
+			t.tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.timing.TLS = time.Since(t.tlsStart)
+		},
+		GotFirstResponseByte: func() {
+			t.timing.FirstByte = time.Since(t.start)
+		},
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+}
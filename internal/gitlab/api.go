@@ -0,0 +1,307 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/NicabarNimble/go-gittools/internal/token"
+)
+
+// UserInfo represents GitLab user information
+type UserInfo struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+}
+
+// Client handles GitLab API operations
+type Client struct {
+	httpClient *http.Client
+	token      string
+	baseURL    string // Allow custom base URL for testing and self-managed instances
+	username   string // Cached username after validation
+}
+
+// Project represents a GitLab project
+type Project struct {
+	ID                int64  `json:"id"`
+	Name              string `json:"name"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	DefaultBranch     string `json:"default_branch"`
+	HTTPURLToRepo     string `json:"http_url_to_repo"`
+	WebURL            string `json:"web_url"`
+}
+
+// ProjectOptions represents options for project creation
+type ProjectOptions struct {
+	Name        string `json:"name"`
+	Path        string `json:"path,omitempty"`
+	Description string `json:"description,omitempty"`
+	Visibility  string `json:"visibility,omitempty"` // private, internal or public
+	NamespaceID int64  `json:"namespace_id,omitempty"`
+}
+
+// MROptions represents options for merge request operations
+type MROptions struct {
+	Project         string `json:"-"` // Source project path (group/project), used for routing
+	SourceBranch    string `json:"source_branch"`
+	TargetBranch    string `json:"target_branch"`
+	Title           string `json:"title"`
+	Description     string `json:"description,omitempty"`
+	TargetProjectID int64  `json:"target_project_id,omitempty"` // Set when merging from a fork
+	Labels          string `json:"labels,omitempty"`            // Comma-separated label names
+}
+
+// MergeRequest represents a GitLab merge request
+type MergeRequest struct {
+	ID     int64  `json:"id"`
+	IID    int64  `json:"iid"`
+	Title  string `json:"title"`
+	State  string `json:"state"`
+	WebURL string `json:"web_url"`
+}
+
+// ClientOption configures optional Client behavior
+type ClientOption func(*Client)
+
+// WithBaseURL points the client at a self-managed GitLab instance's API,
+// e.g. "https://gitlab.example.com/api/v4"
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// NewClient creates a new GitLab API client with token validation
+func NewClient(ctx context.Context, t *token.Token, opts ...ClientOption) (*Client, error) {
+	client := &Client{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		token:      t.Value,
+		baseURL:    apiBaseURL,
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	validator := &TokenValidator{baseURL: client.baseURL}
+	if err := validator.Validate(ctx, t); err != nil {
+		return nil, fmt.Errorf("token validation failed: %w", err)
+	}
+
+	// Get and cache username during client creation
+	userInfo, err := client.GetUserInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user info: %w", err)
+	}
+	client.username = userInfo.Username
+
+	return client, nil
+}
+
+// GetUserInfo retrieves authenticated user information
+func (c *Client) GetUserInfo(ctx context.Context) (*UserInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/user", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var userInfo UserInfo
+	if err := c.doJSON(req, &userInfo); err != nil {
+		return nil, fmt.Errorf("failed to get user info: %w", err)
+	}
+	return &userInfo, nil
+}
+
+// GetUsername returns the cached username
+func (c *Client) GetUsername() string {
+	return c.username
+}
+
+// GetProject retrieves the project at projectPath (group/project)
+func (c *Client) GetProject(ctx context.Context, projectPath string) (*Project, error) {
+	if err := validateProjectPath(projectPath); err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/projects/%s", c.baseURL, url.PathEscape(projectPath))
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var project Project
+	if err := c.doJSON(req, &project); err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+	return &project, nil
+}
+
+// CreateProject creates a new project in the user's namespace, or in
+// opts.NamespaceID if set
+func (c *Client) CreateProject(ctx context.Context, opts ProjectOptions) (*Project, error) {
+	jsonBody, err := json.Marshal(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/projects", bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var project Project
+	if err := c.doJSON(req, &project); err != nil {
+		return nil, fmt.Errorf("failed to create project: %w", err)
+	}
+	return &project, nil
+}
+
+// CreateFork forks the project at projectPath (group/project) into the
+// user's namespace
+func (c *Client) CreateFork(ctx context.Context, projectPath string) (*Project, error) {
+	if err := validateProjectPath(projectPath); err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/projects/%s/fork", c.baseURL, url.PathEscape(projectPath))
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var project Project
+	if err := c.doJSON(req, &project); err != nil {
+		return nil, fmt.Errorf("failed to create fork: %w", err)
+	}
+	return &project, nil
+}
+
+// ProjectExists reports whether the project at projectPath (group/project)
+// exists and is visible to the authenticated user
+func (c *Client) ProjectExists(ctx context.Context, projectPath string) (bool, error) {
+	if err := validateProjectPath(projectPath); err != nil {
+		return false, err
+	}
+
+	endpoint := fmt.Sprintf("%s/projects/%s", c.baseURL, url.PathEscape(projectPath))
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.sendRequest(req)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get project: %w", err)
+	}
+	resp.Body.Close()
+	return true, nil
+}
+
+// DeleteProject deletes the project at projectPath (group/project). GitLab
+// may schedule the deletion rather than remove the project immediately.
+func (c *Client) DeleteProject(ctx context.Context, projectPath string) error {
+	if err := validateProjectPath(projectPath); err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("%s/projects/%s", c.baseURL, url.PathEscape(projectPath))
+	req, err := http.NewRequestWithContext(ctx, "DELETE", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.sendRequest(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete project: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// CreateMergeRequest opens a merge request from opts.SourceBranch of
+// opts.Project into opts.TargetBranch
+func (c *Client) CreateMergeRequest(ctx context.Context, opts MROptions) (*MergeRequest, error) {
+	if err := validateProjectPath(opts.Project); err != nil {
+		return nil, err
+	}
+
+	jsonBody, err := json.Marshal(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/projects/%s/merge_requests", c.baseURL, url.PathEscape(opts.Project))
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var mr MergeRequest
+	if err := c.doJSON(req, &mr); err != nil {
+		return nil, fmt.Errorf("failed to create merge request: %w", err)
+	}
+	return &mr, nil
+}
+
+// doJSON sends req and decodes a successful JSON response into v
+func (c *Client) doJSON(req *http.Request, v interface{}) error {
+	resp, err := c.sendRequest(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// sendRequest sends an HTTP request with the necessary headers
+func (c *Client) sendRequest(req *http.Request) (*http.Response, error) {
+	req.Header.Set("PRIVATE-TOKEN", c.token)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	if req.Body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return resp, fmt.Errorf("GitLab API error: %s: %s", resp.Status, string(body))
+	}
+
+	return resp, nil
+}
+
+// validateProjectPath checks that a project path has the group/project form.
+// Nested groups (group/subgroup/project) are allowed.
+func validateProjectPath(projectPath string) error {
+	parts := strings.Split(projectPath, "/")
+	if len(parts) < 2 {
+		return fmt.Errorf("invalid project path: %s (expected group/project)", projectPath)
+	}
+	for _, p := range parts {
+		if p == "" {
+			return fmt.Errorf("invalid project path: %s (expected group/project)", projectPath)
+		}
+	}
+	return nil
+}
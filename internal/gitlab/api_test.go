@@ -0,0 +1,252 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client that talks to server
+func newTestClient(server *httptest.Server) *Client {
+	return &Client{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		token:      "test-token",
+		baseURL:    server.URL,
+	}
+}
+
+func TestCreateProject(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusCode  int
+		response    string
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:       "successful project creation",
+			statusCode: http.StatusCreated,
+			response:   `{"id": 42, "name": "mirror", "path_with_namespace": "user/mirror", "default_branch": "main"}`,
+		},
+		{
+			name:        "project already exists",
+			statusCode:  http.StatusBadRequest,
+			response:    `{"message": {"name": ["has already been taken"]}}`,
+			wantErr:     true,
+			errContains: "failed to create project",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/projects" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				if r.Header.Get("PRIVATE-TOKEN") != "test-token" {
+					t.Errorf("expected PRIVATE-TOKEN header, got %q", r.Header.Get("PRIVATE-TOKEN"))
+				}
+
+				var body map[string]interface{}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Fatalf("failed to decode request body: %v", err)
+				}
+				if body["name"] != "mirror" || body["visibility"] != "private" {
+					t.Errorf("unexpected request body: %v", body)
+				}
+
+				w.WriteHeader(tt.statusCode)
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			project, err := newTestClient(server).CreateProject(context.Background(), ProjectOptions{
+				Name:       "mirror",
+				Visibility: "private",
+			})
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("expected error containing %q, got %v", tt.errContains, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if project.ID != 42 || project.PathWithNamespace != "user/mirror" {
+				t.Errorf("unexpected project: %+v", project)
+			}
+		})
+	}
+}
+
+func TestCreateFork(t *testing.T) {
+	tests := []struct {
+		name        string
+		project     string
+		statusCode  int
+		response    string
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:       "successful fork",
+			project:    "group/sub/project",
+			statusCode: http.StatusCreated,
+			response:   `{"id": 7, "path_with_namespace": "user/project"}`,
+		},
+		{
+			name:        "invalid project path",
+			project:     "project",
+			wantErr:     true,
+			errContains: "invalid project path",
+		},
+		{
+			name:        "server error",
+			project:     "group/project",
+			statusCode:  http.StatusInternalServerError,
+			response:    `{"message": "500 Internal Server Error"}`,
+			wantErr:     true,
+			errContains: "failed to create fork",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				want := "/projects/" + strings.ReplaceAll(tt.project, "/", "%2F") + "/fork"
+				if r.URL.EscapedPath() != want {
+					t.Errorf("expected path %s, got %s", want, r.URL.EscapedPath())
+				}
+				w.WriteHeader(tt.statusCode)
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			project, err := newTestClient(server).CreateFork(context.Background(), tt.project)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("expected error containing %q, got %v", tt.errContains, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if project.PathWithNamespace != "user/project" {
+				t.Errorf("unexpected fork: %+v", project)
+			}
+		})
+	}
+}
+
+func TestCreateMergeRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusCode  int
+		response    string
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:       "successful merge request",
+			statusCode: http.StatusCreated,
+			response:   `{"id": 100, "iid": 3, "title": "Sync upstream", "state": "opened"}`,
+		},
+		{
+			name:        "conflict",
+			statusCode:  http.StatusConflict,
+			response:    `{"message": ["Another open merge request already exists for this source branch"]}`,
+			wantErr:     true,
+			errContains: "failed to create merge request",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.EscapedPath() != "/projects/group%2Fproject/merge_requests" {
+					t.Errorf("unexpected path %s", r.URL.EscapedPath())
+				}
+
+				var body map[string]interface{}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Fatalf("failed to decode request body: %v", err)
+				}
+				if body["source_branch"] != "feature" || body["target_branch"] != "main" || body["title"] != "Sync upstream" {
+					t.Errorf("unexpected request body: %v", body)
+				}
+
+				w.WriteHeader(tt.statusCode)
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			mr, err := newTestClient(server).CreateMergeRequest(context.Background(), MROptions{
+				Project:      "group/project",
+				SourceBranch: "feature",
+				TargetBranch: "main",
+				Title:        "Sync upstream",
+			})
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("expected error containing %q, got %v", tt.errContains, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if mr.IID != 3 || mr.State != "opened" {
+				t.Errorf("unexpected merge request: %+v", mr)
+			}
+		})
+	}
+}
+
+func TestDeleteProject(t *testing.T) {
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.EscapedPath() == "/projects/user%2Fproject":
+			w.Write([]byte(`{"id": 7, "path_with_namespace": "user/project"}`))
+		case r.Method == http.MethodGet:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "404 Project Not Found"}`))
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, r.URL.EscapedPath())
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"message": "202 Accepted"}`))
+		default:
+			t.Errorf("unexpected %s request to %s", r.Method, r.URL.EscapedPath())
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+	ctx := context.Background()
+
+	exists, err := client.ProjectExists(ctx, "user/project")
+	if err != nil || !exists {
+		t.Fatalf("ProjectExists(user/project) = %v, %v; want true, nil", exists, err)
+	}
+	exists, err = client.ProjectExists(ctx, "user/missing")
+	if err != nil || exists {
+		t.Fatalf("ProjectExists(user/missing) = %v, %v; want false, nil", exists, err)
+	}
+
+	if err := client.DeleteProject(ctx, "user/project"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0] != "/projects/user%2Fproject" {
+		t.Errorf("unexpected deletions: %v", deleted)
+	}
+
+	if err := client.DeleteProject(ctx, "project"); err == nil || !strings.Contains(err.Error(), "invalid project path") {
+		t.Errorf("expected invalid project path error, got %v", err)
+	}
+}
@@ -20,6 +20,12 @@ type TokenValidator struct {
 	baseURL string
 }
 
+func init() {
+	token.RegisterValidator(token.ProviderGitLab, func() token.Validator {
+		return NewTokenValidator()
+	})
+}
+
 // NewTokenValidator creates a new GitLab token validator
 func NewTokenValidator() *TokenValidator {
 	return &TokenValidator{
@@ -92,3 +92,13 @@ func TestTokenValidator_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatorForGitLab(t *testing.T) {
+	v, err := token.ValidatorFor(token.ProviderGitLab)
+	if err != nil {
+		t.Fatalf("ValidatorFor() unexpected error: %v", err)
+	}
+	if _, ok := v.(*TokenValidator); !ok {
+		t.Errorf("ValidatorFor() returned %T, want *TokenValidator", v)
+	}
+}
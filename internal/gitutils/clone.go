@@ -3,14 +3,12 @@ package gitutils
 import (
 	"context"
 	"fmt"
-	"io"
 	"net/url"
 	"os"
-	"os/exec"
-	"regexp"
 	"strings"
 	"time"
 
+	"github.com/NicabarNimble/go-gittools/internal/git"
 	"github.com/NicabarNimble/go-gittools/internal/github"
 	"github.com/NicabarNimble/go-gittools/internal/token"
 )
@@ -23,77 +21,20 @@ type CloneOptions struct {
 	Verbose    bool
 	Token      string
 	CustomName string // Optional: custom repository name
-}
 
-// progressWriter wraps an io.Writer to provide custom output formatting
-type progressWriter struct {
-	prefix string
-	w      io.Writer
-}
+	// SyncDefaultBranch sets the target repository's default branch to the
+	// source's after pushing. GitHub otherwise keeps whichever branch
+	// arrived first.
+	SyncDefaultBranch bool
 
-func newProgressWriter(prefix string, w io.Writer) *progressWriter {
-	return &progressWriter{prefix: prefix, w: w}
+	// GitHubOptions configure the GitHub client used to create the target
+	// repository, e.g. github.WithBaseURL for GitHub Enterprise
+	GitHubOptions []github.ClientOption
 }
 
-var (
-	// Match lines like:
-	// Receiving objects:  67% (35484/52960), 236.76 MiB | 78.92 MiB/s
-	// Receiving objects: 100% (52960/52960), 298.63 MiB | 81.39 MiB/s, done.
-	progressRegex = regexp.MustCompile(`(?:Receiving objects|Resolving deltas):\s*(\d+)%\s*\((\d+)/(\d+)\)(?:,\s*([\d.]+)\s*([^|]+)\|\s*([\d.]+)\s*([^,\n]+))?`)
-	// Match completion lines like:
-	// Receiving objects: 100% (52960/52960), 298.63 MiB | 81.39 MiB/s, done.
-	completionRegex = regexp.MustCompile(`(?:Receiving objects|Resolving deltas):\s*100%.*,\s*([\d.]+)\s*([^|]+).*done`)
-)
-
-func (pw *progressWriter) Write(p []byte) (n int, err error) {
-	lines := strings.Split(string(p), "\n")
-	for _, line := range lines {
-		if line == "" {
-			continue
-		}
-		if strings.HasPrefix(line, "remote: ") {
-			line = strings.TrimPrefix(line, "remote: ")
-		}
-
-		// Skip the "Cloning into" message as we have our own header
-		if strings.HasPrefix(line, "Cloning into") {
-			continue
-		}
-
-		// Check for completion message first
-		if matches := completionRegex.FindStringSubmatch(line); matches != nil {
-			size := matches[1]
-			unit := strings.TrimSpace(matches[2])
-			fmt.Fprintf(pw.w, "%s100%% (Total size: %s %s)\n", pw.prefix, size, unit)
-			continue
-		}
-
-		// Handle progress lines
-		if matches := progressRegex.FindStringSubmatch(line); matches != nil {
-			percentage := matches[1]
-			current := matches[2]
-			total := matches[3]
-
-			// If we have size information (matches[4] through matches[7])
-			if len(matches) > 4 && matches[4] != "" {
-				size := matches[4]
-				sizeUnit := strings.TrimSpace(matches[5])
-				speed := matches[6]
-				speedUnit := strings.TrimSpace(matches[7])
-				fmt.Fprintf(pw.w, "%s%s%% (%s/%s) Size: %s %s, Speed: %s %s\n",
-					pw.prefix, percentage, current, total, size, sizeUnit, speed, speedUnit)
-			} else {
-				fmt.Fprintf(pw.w, "%s%s%% (%s/%s)\n",
-					pw.prefix, percentage, current, total)
-			}
-			continue
-		}
-
-		// For all other lines, just prefix them
-		fmt.Fprintf(pw.w, "%s%s\n", pw.prefix, line)
-	}
-	return len(p), nil
-}
+// workflowPaths are removed from the whole history of the clone so no
+// source workflow can run in, or be recovered from, the target
+var workflowPaths = []string{".github/workflows"}
 
 // extractRepoInfo extracts owner and repo name from a GitHub URL
 func extractRepoInfo(repoURL string) (owner string, name string, err error) {
@@ -212,7 +153,7 @@ func CloneRepository(opts CloneOptions) error {
 		return fmt.Errorf("failed to create token: %w", err)
 	}
 
-	ghClient, err := github.NewClient(context.Background(), t)
+	ghClient, err := newGitHubClient(context.Background(), t, opts.GitHubOptions...)
 	if err != nil {
 		return fmt.Errorf("failed to create GitHub client: %w", err)
 	}
@@ -243,7 +184,7 @@ func CloneRepository(opts CloneOptions) error {
 		if strings.Contains(strings.ToLower(err.Error()), "already exists") {
 			fmt.Printf("\n⚠️  Repository already exists at %s\n", opts.TargetURL)
 			fmt.Printf("   For automated syncing, use gitsync with this repository\n")
-			os.Exit(2) // Exit code 2 indicates repository exists
+			osExit(2) // Exit code 2 indicates repository exists
 		}
 		return fmt.Errorf("failed to create target repository: %w", err)
 	}
@@ -255,46 +196,29 @@ func CloneRepository(opts CloneOptions) error {
 	}
 	defer os.RemoveAll(tempDir)
 
-	// Clone source repository
-	if err := runGitCommand(tempDir, "clone", opts.SourceURL, "."); err != nil {
+	// Clone source repository without its workflow files
+	fmt.Printf("\n📦 Cloning repository and removing workflow files for security...\n")
+	if err := cloneRepository(git.CloneOptions{
+		SourceURL:    opts.SourceURL,
+		WorkingDir:   tempDir,
+		Token:        opts.Token,
+		ExcludePaths: workflowPaths,
+	}); err != nil {
 		return fmt.Errorf("failed to clone source repository: %w", err)
 	}
 
-	// Add target remote
-	if err := runGitCommand(tempDir, "remote", "add", "target", opts.TargetURL); err != nil {
-		return fmt.Errorf("failed to add target remote: %w", err)
-	}
-
-	// Set up authentication for the target repository
-	targetWithAuth := strings.Replace(opts.TargetURL, "https://", fmt.Sprintf("https://%s@", opts.Token), 1)
-	if err := runGitCommand(tempDir, "remote", "set-url", "target", targetWithAuth); err != nil {
-		return fmt.Errorf("failed to set authenticated remote URL: %w", err)
-	}
-
-	// Configure git user for commits
-	if err := runGitCommand(tempDir, "config", "user.name", "go-gitclone"); err != nil {
-		return fmt.Errorf("failed to configure git user name: %w", err)
-	}
-	if err := runGitCommand(tempDir, "config", "user.email", "go-gitclone@github.com"); err != nil {
-		return fmt.Errorf("failed to configure git user email: %w", err)
-	}
-
-	fmt.Printf("\n🔒 Removing workflow files for security...\n")
-	// Remove workflow files before pushing
-	if err := runGitCommand(tempDir, "rm", "-rf", ".github/workflows"); err != nil {
-		// Ignore error if workflows directory doesn't exist
-		if !strings.Contains(err.Error(), "pathspec '.github/workflows' did not match any files") {
-			return fmt.Errorf("failed to remove workflow files: %w", err)
+	// Push to target repository (without force flag)
+	fmt.Printf("\n📤 Pushing to target repository...\n")
+	pushOpts := git.CloneOptions{
+		TargetURL: opts.TargetURL,
+		Token:     opts.Token,
+	}
+	if opts.SyncDefaultBranch {
+		pushOpts.SetDefaultBranch = func(branch string) error {
+			return ghClient.UpdateRepository(context.Background(), ghClient.GetUsername(), targetName, github.RepoPatch{DefaultBranch: &branch})
 		}
 	}
-
-	// Commit the removal of workflow files if any were removed
-	if err := runGitCommand(tempDir, "commit", "-m", "Remove workflow files for security", "--allow-empty"); err != nil {
-		return fmt.Errorf("failed to commit workflow removal: %w", err)
-	}
-
-	// Push to target repository (without force flag)
-	if err := runGitCommand(tempDir, "push", "-u", "target", "--all"); err != nil {
+	if err := pushRepository(tempDir, pushOpts); err != nil {
 		return fmt.Errorf("failed to push to target repository: %w", err)
 	}
 
@@ -304,38 +228,8 @@ func CloneRepository(opts CloneOptions) error {
 
 // For testing purposes
 var (
-	runGitCommand = defaultRunGitCommand
-	osExit       = os.Exit
+	osExit          = os.Exit
+	newGitHubClient = github.NewClient
+	cloneRepository = git.CloneRepository
+	pushRepository  = git.PushRepository
 )
-
-func defaultRunGitCommand(dir string, args ...string) error {
-	cmd := exec.Command("git", args...)
-	cmd.Dir = dir
-
-	// Special handling for different git commands
-	switch args[0] {
-	case "clone":
-		fmt.Printf("\n📦 Cloning repository...\n")
-		cmd.Stdout = newProgressWriter("   ", os.Stdout)
-		cmd.Stderr = newProgressWriter("   ", os.Stderr)
-	case "rm":
-		// Suppress output for rm command
-		cmd.Stdout = io.Discard
-		cmd.Stderr = io.Discard
-	case "push":
-		fmt.Printf("\n📤 Pushing to target repository...\n")
-		cmd.Stdout = newProgressWriter("   ", os.Stdout)
-		cmd.Stderr = newProgressWriter("   ", os.Stderr)
-	default:
-		// For other commands, discard output unless there's an error
-		cmd.Stdout = io.Discard
-		cmd.Stderr = io.Discard
-	}
-
-	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=", "GIT_CREDENTIAL_HELPER=")
-
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("git command failed: %w", err)
-	}
-	return nil
-}
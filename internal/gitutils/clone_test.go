@@ -1,17 +1,26 @@
 package gitutils
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/NicabarNimble/go-gittools/internal/git"
 	"github.com/NicabarNimble/go-gittools/internal/github"
+	"github.com/NicabarNimble/go-gittools/internal/github/githubtest"
+	"github.com/NicabarNimble/go-gittools/internal/token"
 )
 
 // Store original functions
 var (
-	originalRunGitCommand = runGitCommand
-	originalOsExit       = osExit
+	originalOsExit          = osExit
+	originalNewGitHubClient = newGitHubClient
+	originalCloneRepository = cloneRepository
+	originalPushRepository  = pushRepository
 )
 
 type mockGitCommand struct {
@@ -38,7 +47,8 @@ func (m *mockGitHubClient) CreateRepository(_ github.RepoOptions) error {
 func TestCloneRepository(t *testing.T) {
 	// Restore original functions after test
 	defer func() {
-		runGitCommand = originalRunGitCommand
+		cloneRepository = originalCloneRepository
+		pushRepository = originalPushRepository
 		osExit = originalOsExit
 	}()
 
@@ -92,8 +102,9 @@ func TestCloneRepository(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Set up mocks
-			mock := &mockGitCommand{}
-			runGitCommand = mock.run
+			mock := &mockDelegate{mockGitCommand: &mockGitCommand{}}
+			cloneRepository = mock.cloneRepository
+			pushRepository = mock.pushRepository
 
 			// Create a channel to capture exit code
 			exitCodeChan := make(chan int, 1)
@@ -138,16 +149,201 @@ func TestCloneRepository(t *testing.T) {
 			}
 
 			// Check if force flag was used in push command
-			if !tt.repoExists {
-				for _, cmd := range mock.commands {
-					if strings.HasPrefix(cmd, "push") {
-						hasForce := strings.Contains(cmd, "--force")
-						if hasForce != tt.wantPushForce {
-							t.Errorf("push command force flag = %v, want %v", hasForce, tt.wantPushForce)
-						}
-					}
-				}
+			if !tt.repoExists && mock.push.Force != tt.wantPushForce {
+				t.Errorf("push force flag = %v, want %v", mock.push.Force, tt.wantPushForce)
 			}
 		})
 	}
 }
+
+// mockDelegate records the internal/git calls made by CloneRepository
+// alongside the git commands run directly
+type mockDelegate struct {
+	*mockGitCommand
+	clone git.CloneOptions
+	push  git.CloneOptions
+}
+
+func (m *mockDelegate) cloneRepository(opts git.CloneOptions) error {
+	m.clone = opts
+	m.commands = append(m.commands, "[clone] "+opts.SourceURL)
+	return nil
+}
+
+func (m *mockDelegate) pushRepository(dir string, opts git.CloneOptions) error {
+	m.push = opts
+	m.commands = append(m.commands, "[push] "+opts.TargetURL)
+	return nil
+}
+
+func TestCloneRepositoryWithFakeServer(t *testing.T) {
+	defer func() {
+		newGitHubClient = originalNewGitHubClient
+		cloneRepository = originalCloneRepository
+		pushRepository = originalPushRepository
+	}()
+
+	fake := githubtest.NewFakeServer()
+	defer fake.Close()
+	newGitHubClient = func(ctx context.Context, t *token.Token, opts ...github.ClientOption) (*github.Client, error) {
+		return github.NewClient(ctx, t, append(opts, github.WithBaseURL(fake.URL()))...)
+	}
+
+	mock := &mockDelegate{mockGitCommand: &mockGitCommand{}}
+	cloneRepository = mock.cloneRepository
+	pushRepository = mock.pushRepository
+
+	err := CloneRepository(CloneOptions{
+		SourceURL: "https://github.com/source/repo.git",
+		Token:     "ghp_test-token",
+	})
+	if err != nil {
+		t.Fatalf("CloneRepository() unexpected error: %v", err)
+	}
+
+	repo, ok := fake.Repository(githubtest.DefaultLogin + "/private-repo")
+	if !ok {
+		t.Fatalf("expected private-repo to be created, got %v", fake.Repositories())
+	}
+	if !repo.Private {
+		t.Error("expected the created repository to be private")
+	}
+
+	wantTarget := "https://github.com/" + githubtest.DefaultLogin + "/private-repo.git"
+	wantCommands := []string{
+		"[clone] https://github.com/source/repo.git",
+		"[push] " + wantTarget,
+	}
+	if strings.Join(mock.commands, "\n") != strings.Join(wantCommands, "\n") {
+		t.Errorf("git commands =\n%s\nwant\n%s", strings.Join(mock.commands, "\n"), strings.Join(wantCommands, "\n"))
+	}
+	if mock.clone.Token != "ghp_test-token" || mock.push.Token != "ghp_test-token" {
+		t.Errorf("expected the token to be passed to clone and push, got %q and %q", mock.clone.Token, mock.push.Token)
+	}
+	if mock.clone.WorkingDir == "" {
+		t.Error("expected the source to be cloned into a working directory")
+	}
+	if strings.Join(mock.clone.ExcludePaths, ",") != ".github/workflows" {
+		t.Errorf("clone excluded %q, want .github/workflows", mock.clone.ExcludePaths)
+	}
+}
+
+func TestCloneRepositoryDelegatesToGit(t *testing.T) {
+	defer func() {
+		newGitHubClient = originalNewGitHubClient
+	}()
+
+	fake := githubtest.NewFakeServer()
+	defer fake.Close()
+	newGitHubClient = func(ctx context.Context, t *token.Token, opts ...github.ClientOption) (*github.Client, error) {
+		return github.NewClient(ctx, t, append(opts, github.WithBaseURL(fake.URL()))...)
+	}
+
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	// Source repository with a workflow that must not reach the target
+	base := t.TempDir()
+	work := filepath.Join(base, "work")
+	runGit(t, base, "init", "-b", "main", work)
+	writeFile(t, filepath.Join(work, "README.md"), "hello\n")
+	writeFile(t, filepath.Join(work, ".github", "workflows", "ci.yml"), "name: CI\n")
+	runGit(t, work, "add", "-A")
+	runGit(t, work, "commit", "-m", "Initial commit")
+
+	source := filepath.Join(base, "source.git")
+	target := filepath.Join(base, "target.git")
+	runGit(t, base, "clone", "--bare", work, source)
+	runGit(t, base, "init", "--bare", target)
+
+	err := CloneRepository(CloneOptions{
+		SourceURL: "file://" + source,
+		TargetURL: "file://" + target,
+		Token:     "ghp_test-token",
+	})
+	if err != nil {
+		t.Fatalf("CloneRepository() unexpected error: %v", err)
+	}
+
+	files := runGit(t, target, "ls-tree", "-r", "--name-only", "main")
+	if strings.TrimSpace(files) != "README.md" {
+		t.Errorf("target files = %q, want only README.md", files)
+	}
+	if leaked := runGit(t, target, "log", "--all", "--format=%H", "--", ".github/workflows"); leaked != "" {
+		t.Errorf("target history still contains workflow files in %s", leaked)
+	}
+}
+
+func TestCloneRepositorySyncDefaultBranch(t *testing.T) {
+	defer func() {
+		newGitHubClient = originalNewGitHubClient
+	}()
+
+	fake := githubtest.NewFakeServer()
+	defer fake.Close()
+	newGitHubClient = func(ctx context.Context, t *token.Token, opts ...github.ClientOption) (*github.Client, error) {
+		return github.NewClient(ctx, t, append(opts, github.WithBaseURL(fake.URL()))...)
+	}
+
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	// The source's default branch is not the fake's initial "main"
+	base := t.TempDir()
+	work := filepath.Join(base, "work")
+	runGit(t, base, "init", "-b", "develop", work)
+	writeFile(t, filepath.Join(work, "README.md"), "hello\n")
+	runGit(t, work, "add", "-A")
+	runGit(t, work, "commit", "-m", "Initial commit")
+
+	source := filepath.Join(base, "source.git")
+	target := filepath.Join(base, "target.git")
+	runGit(t, base, "clone", "--bare", work, source)
+	runGit(t, base, "init", "--bare", target)
+
+	err := CloneRepository(CloneOptions{
+		SourceURL:         "file://" + source,
+		TargetURL:         "file://" + target,
+		Token:             "ghp_test-token",
+		CustomName:        "mirror",
+		SyncDefaultBranch: true,
+	})
+	if err != nil {
+		t.Fatalf("CloneRepository() unexpected error: %v", err)
+	}
+
+	repo, ok := fake.Repository(githubtest.DefaultLogin + "/mirror")
+	if !ok {
+		t.Fatalf("expected mirror to be created, got %v", fake.Repositories())
+	}
+	if repo.DefaultBranch != "develop" {
+		t.Errorf("default branch = %q, want develop", repo.DefaultBranch)
+	}
+}
+
+// runGit runs git in dir and returns its output, failing the test on error
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return string(out)
+}
+
+// writeFile creates path and its parent directories with content
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,240 @@
+package hosting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/NicabarNimble/go-gittools/internal/github"
+)
+
+// GitHub adapts a github.Client to the Provider interface
+type GitHub struct {
+	client *github.Client
+}
+
+var (
+	_ Provider               = (*GitHub)(nil)
+	_ SizeReporter           = (*GitHub)(nil)
+	_ ChangeRequestFinder    = (*GitHub)(nil)
+	_ ChangeRequestAnnotator = (*GitHub)(nil)
+	_ ChangeRequestWatcher   = (*GitHub)(nil)
+	_ RepositoryDeleter      = (*GitHub)(nil)
+)
+
+// NewGitHub returns a Provider backed by client
+func NewGitHub(client *github.Client) *GitHub {
+	return &GitHub{client: client}
+}
+
+// CreateRepository creates a repository for the authenticated user
+func (g *GitHub) CreateRepository(ctx context.Context, opts RepoOptions) error {
+	return g.client.CreateRepository(ctx, github.RepoOptions{
+		Name:        opts.Name,
+		Description: opts.Description,
+		Private:     opts.Private,
+	})
+}
+
+// CreateFork forks an owner/repo repository
+func (g *GitHub) CreateFork(ctx context.Context, repoPath string) error {
+	return g.client.CreateFork(ctx, repoPath)
+}
+
+// CreatePullOrMergeRequest opens a pull request on the target repository.
+// Reviewers and labels are applied separately by AnnotateChangeRequest.
+func (g *GitHub) CreatePullOrMergeRequest(ctx context.Context, opts ChangeRequestOptions) (string, error) {
+	targetOwner, targetRepo, head, err := pullRequestHead(opts)
+	if err != nil {
+		return "", err
+	}
+
+	pr, err := g.client.OpenPullRequest(ctx, github.PROptions{
+		Owner: targetOwner,
+		Repo:  targetRepo,
+		Base:  opts.TargetBranch,
+		Head:  head,
+		Title: opts.Title,
+		Body:  opts.Body,
+		Draft: opts.Draft,
+	})
+	if err != nil {
+		return "", err
+	}
+	return pr.HTMLURL, nil
+}
+
+// AnnotateChangeRequest requests reviewers and adds labels to the pull
+// request with the given web URL. Both are attempted even if one fails.
+func (g *GitHub) AnnotateChangeRequest(ctx context.Context, webURL string, opts ChangeRequestOptions) error {
+	owner, repo, number, err := parsePullRequestURL(webURL)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	users, teams := splitReviewers(opts.Reviewers)
+	if err := g.client.RequestReviewers(ctx, owner, repo, number, users, teams); err != nil {
+		errs = append(errs, err)
+	}
+	if err := g.client.AddLabels(ctx, owner, repo, number, opts.Labels); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// splitReviewers separates "org/team" reviewers, returned as team slugs,
+// from individual users
+func splitReviewers(reviewers []string) (users, teams []string) {
+	for _, r := range reviewers {
+		if _, team, ok := strings.Cut(r, "/"); ok {
+			teams = append(teams, team)
+		} else {
+			users = append(users, r)
+		}
+	}
+	return users, teams
+}
+
+// FindChangeRequest returns the web URL of the first open pull request from
+// the source branch into the target branch
+func (g *GitHub) FindChangeRequest(ctx context.Context, opts ChangeRequestOptions) (string, error) {
+	targetOwner, targetRepo, head, err := pullRequestHead(opts)
+	if err != nil {
+		return "", err
+	}
+	// The head filter ignores bare branch names, which would match every
+	// open pull request; a bare head is owned by the target's owner
+	if !strings.Contains(head, ":") {
+		head = targetOwner + ":" + head
+	}
+
+	pulls, err := g.client.ListPullRequests(ctx, targetOwner, targetRepo, github.PRFilter{
+		State: "open",
+		Head:  head,
+		Base:  opts.TargetBranch,
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(pulls) == 0 {
+		return "", nil
+	}
+	return pulls[0].HTMLURL, nil
+}
+
+// ChangeRequestState returns the state of the pull request with the given
+// web URL, such as https://github.com/owner/repo/pull/7
+func (g *GitHub) ChangeRequestState(ctx context.Context, webURL string) (ChangeRequestState, error) {
+	owner, repo, number, err := parsePullRequestURL(webURL)
+	if err != nil {
+		return "", err
+	}
+
+	pr, err := g.client.GetPullRequest(ctx, owner, repo, number)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case pr.Merged:
+		return ChangeRequestMerged, nil
+	case pr.State == "closed":
+		return ChangeRequestClosed, nil
+	default:
+		return ChangeRequestOpen, nil
+	}
+}
+
+// parsePullRequestURL splits a pull request web URL of the form
+// https://host/owner/repo/pull/number
+func parsePullRequestURL(webURL string) (owner, repo string, number int, err error) {
+	parsed, err := url.Parse(webURL)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("invalid pull request URL %q: %w", webURL, err)
+	}
+	parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(parts) != 4 || parts[2] != "pull" {
+		return "", "", 0, fmt.Errorf("invalid pull request URL %q: expected /owner/repo/pull/number", webURL)
+	}
+	number, err = strconv.Atoi(parts[3])
+	if err != nil {
+		return "", "", 0, fmt.Errorf("invalid pull request URL %q: %w", webURL, err)
+	}
+	return parts[0], parts[1], number, nil
+}
+
+// pullRequestHead splits the target repository of opts and returns the
+// head to open a pull request from. The "owner:branch" form is only used
+// across owners, i.e. from a fork; a source owned by the target's owner
+// gives just the branch.
+func pullRequestHead(opts ChangeRequestOptions) (targetOwner, targetRepo, head string, err error) {
+	targetOwner, targetRepo, err = github.ParseRepo(opts.TargetRepo)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	head = opts.SourceBranch
+	if opts.SourceRepo != "" {
+		sourceOwner, _, err := github.ParseRepo(opts.SourceRepo)
+		if err != nil {
+			return "", "", "", err
+		}
+		if !strings.EqualFold(sourceOwner, targetOwner) {
+			head = fmt.Sprintf("%s:%s", sourceOwner, opts.SourceBranch)
+		}
+	}
+	return targetOwner, targetRepo, head, nil
+}
+
+// GetDefaultBranch returns the default branch of an owner/repo repository
+func (g *GitHub) GetDefaultBranch(ctx context.Context, repoPath string) (string, error) {
+	return g.client.GetDefaultBranch(ctx, repoPath)
+}
+
+// RepositorySize returns the size of an owner/repo repository in bytes
+func (g *GitHub) RepositorySize(ctx context.Context, repoPath string) (int64, error) {
+	owner, repo, err := github.ParseRepo(repoPath)
+	if err != nil {
+		return 0, err
+	}
+	repository, err := g.client.GetRepository(ctx, owner, repo)
+	if err != nil {
+		return 0, err
+	}
+	return repository.SizeBytes(), nil
+}
+
+// ForkPath returns the owner/repo path of the authenticated user's fork of
+// an owner/repo repository
+func (g *GitHub) ForkPath(ctx context.Context, repoPath string) (string, error) {
+	_, repo, err := github.ParseRepo(repoPath)
+	if err != nil {
+		return "", err
+	}
+	user, err := g.client.GetUserInfo(ctx)
+	if err != nil {
+		return "", err
+	}
+	return user.Login + "/" + repo, nil
+}
+
+// RepositoryExists reports whether an owner/repo repository exists
+func (g *GitHub) RepositoryExists(ctx context.Context, repoPath string) (bool, error) {
+	owner, repo, err := github.ParseRepo(repoPath)
+	if err != nil {
+		return false, err
+	}
+	return g.client.RepositoryExists(ctx, owner, repo)
+}
+
+// DeleteRepository deletes an owner/repo repository
+func (g *GitHub) DeleteRepository(ctx context.Context, repoPath string) error {
+	owner, repo, err := github.ParseRepo(repoPath)
+	if err != nil {
+		return err
+	}
+	return g.client.DeleteRepository(ctx, owner, repo)
+}
@@ -0,0 +1,154 @@
+package hosting
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NicabarNimble/go-gittools/internal/github"
+	"github.com/NicabarNimble/go-gittools/internal/token"
+)
+
+func TestPullRequestHead(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     ChangeRequestOptions
+		wantHead string
+		wantErr  bool
+	}{
+		{
+			name:     "cross-owner fork",
+			opts:     ChangeRequestOptions{SourceRepo: "user/public-fork", SourceBranch: "feature", TargetRepo: "org/private-repo"},
+			wantHead: "user:feature",
+		},
+		{
+			name:     "same repository",
+			opts:     ChangeRequestOptions{SourceRepo: "org/repo", SourceBranch: "feature", TargetRepo: "org/repo"},
+			wantHead: "feature",
+		},
+		{
+			name:     "same owner, different repository",
+			opts:     ChangeRequestOptions{SourceRepo: "Org/public-repo", SourceBranch: "feature", TargetRepo: "org/private-repo"},
+			wantHead: "feature",
+		},
+		{
+			name:     "no source repository",
+			opts:     ChangeRequestOptions{SourceBranch: "feature", TargetRepo: "org/repo"},
+			wantHead: "feature",
+		},
+		{
+			name:    "invalid source repository",
+			opts:    ChangeRequestOptions{SourceRepo: "fork", SourceBranch: "feature", TargetRepo: "org/repo"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owner, repo, head, err := pullRequestHead(tt.opts)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if head != tt.wantHead {
+				t.Errorf("head = %q, want %q", head, tt.wantHead)
+			}
+			if owner+"/"+repo != tt.opts.TargetRepo {
+				t.Errorf("target = %s/%s, want %s", owner, repo, tt.opts.TargetRepo)
+			}
+		})
+	}
+}
+
+func TestParsePullRequestURL(t *testing.T) {
+	owner, repo, number, err := parsePullRequestURL("https://github.example.com/org/repo/pull/42")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if owner != "org" || repo != "repo" || number != 42 {
+		t.Errorf("parsePullRequestURL() = %s, %s, %d; want org, repo, 42", owner, repo, number)
+	}
+
+	for _, invalid := range []string{
+		"https://github.com/org/repo",
+		"https://github.com/org/repo/issues/42",
+		"https://github.com/org/repo/pull/latest",
+	} {
+		if _, _, _, err := parsePullRequestURL(invalid); err == nil {
+			t.Errorf("parsePullRequestURL(%q) expected an error", invalid)
+		}
+	}
+}
+
+func TestFindChangeRequestQualifiesHead(t *testing.T) {
+	var heads []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/org/private-repo/pulls" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		heads = append(heads, r.URL.Query().Get("head"))
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	tok, err := token.NewToken("ghp_test", time.Time{}, "repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := github.NewClient(context.Background(), tok, github.WithBaseURL(server.URL), github.WithSkipValidation(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := NewGitHub(client)
+	for _, source := range []string{"org/public-repo", "user/public-fork", ""} {
+		url, err := provider.FindChangeRequest(context.Background(), ChangeRequestOptions{
+			SourceRepo:   source,
+			SourceBranch: "feature",
+			TargetRepo:   "org/private-repo",
+			TargetBranch: "main",
+		})
+		if err != nil || url != "" {
+			t.Fatalf("FindChangeRequest(%q) = %q, %v; want no pull request", source, url, err)
+		}
+	}
+
+	want := []string{"org:feature", "user:feature", "org:feature"}
+	if strings.Join(heads, ",") != strings.Join(want, ",") {
+		t.Errorf("head filters = %q, want %q", heads, want)
+	}
+}
+
+func TestForkPathUsesAuthenticatedUser(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/user" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"login": "octocat"}`))
+	}))
+	defer server.Close()
+
+	tok, err := token.NewToken("ghp_test", time.Time{}, "repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := github.NewClient(context.Background(), tok, github.WithBaseURL(server.URL), github.WithSkipValidation(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := NewGitHub(client).ForkPath(context.Background(), "org/private-repo")
+	if err != nil || got != "octocat/private-repo" {
+		t.Errorf("ForkPath() = %q, %v; want %q", got, err, "octocat/private-repo")
+	}
+}
@@ -0,0 +1,112 @@
+package hosting
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/NicabarNimble/go-gittools/internal/gitlab"
+)
+
+// GitLab adapts a gitlab.Client to the Provider interface
+type GitLab struct {
+	client *gitlab.Client
+}
+
+var (
+	_ Provider          = (*GitLab)(nil)
+	_ RepositoryDeleter = (*GitLab)(nil)
+)
+
+// NewGitLab returns a Provider backed by client
+func NewGitLab(client *gitlab.Client) *GitLab {
+	return &GitLab{client: client}
+}
+
+// CreateRepository creates a project for the authenticated user
+func (g *GitLab) CreateRepository(ctx context.Context, opts RepoOptions) error {
+	visibility := "public"
+	if opts.Private {
+		visibility = "private"
+	}
+	_, err := g.client.CreateProject(ctx, gitlab.ProjectOptions{
+		Name:        opts.Name,
+		Description: opts.Description,
+		Visibility:  visibility,
+	})
+	return err
+}
+
+// CreateFork forks a group/project project
+func (g *GitLab) CreateFork(ctx context.Context, repoPath string) error {
+	_, err := g.client.CreateFork(ctx, repoPath)
+	return err
+}
+
+// CreatePullOrMergeRequest opens a merge request from the source project.
+// Merge requests across projects are addressed by the target's numeric ID,
+// which is looked up first. GitLab marks drafts by a "Draft:" title prefix.
+func (g *GitLab) CreatePullOrMergeRequest(ctx context.Context, opts ChangeRequestOptions) (string, error) {
+	source := opts.SourceRepo
+	if source == "" {
+		source = opts.TargetRepo
+	}
+
+	title := opts.Title
+	if opts.Draft && !strings.HasPrefix(title, "Draft:") {
+		title = "Draft: " + title
+	}
+
+	mrOpts := gitlab.MROptions{
+		Project:      source,
+		SourceBranch: opts.SourceBranch,
+		TargetBranch: opts.TargetBranch,
+		Title:        title,
+		Description:  opts.Body,
+		Labels:       strings.Join(opts.Labels, ","),
+	}
+
+	if opts.TargetRepo != source {
+		target, err := g.client.GetProject(ctx, opts.TargetRepo)
+		if err != nil {
+			return "", fmt.Errorf("failed to look up target project: %w", err)
+		}
+		mrOpts.TargetProjectID = target.ID
+	}
+
+	mr, err := g.client.CreateMergeRequest(ctx, mrOpts)
+	if err != nil {
+		return "", err
+	}
+	return mr.WebURL, nil
+}
+
+// GetDefaultBranch returns the default branch of a group/project project
+func (g *GitLab) GetDefaultBranch(ctx context.Context, repoPath string) (string, error) {
+	project, err := g.client.GetProject(ctx, repoPath)
+	if err != nil {
+		return "", err
+	}
+	return project.DefaultBranch, nil
+}
+
+// ForkPath returns the username/project path of the authenticated user's
+// fork of a group/project project
+func (g *GitLab) ForkPath(ctx context.Context, repoPath string) (string, error) {
+	user, err := g.client.GetUserInfo(ctx)
+	if err != nil {
+		return "", err
+	}
+	return user.Username + "/" + path.Base(repoPath), nil
+}
+
+// RepositoryExists reports whether a group/project project exists
+func (g *GitLab) RepositoryExists(ctx context.Context, repoPath string) (bool, error) {
+	return g.client.ProjectExists(ctx, repoPath)
+}
+
+// DeleteRepository deletes a group/project project
+func (g *GitLab) DeleteRepository(ctx context.Context, repoPath string) error {
+	return g.client.DeleteProject(ctx, repoPath)
+}
@@ -0,0 +1,109 @@
+// Package hosting defines a provider-agnostic interface for repository
+// hosting operations, with adapters for the GitHub and GitLab clients.
+// Callers such as gitpublish can create repositories, forks and pull or
+// merge requests without switching on the provider.
+package hosting
+
+import "context"
+
+// RepoOptions describes a repository to create
+type RepoOptions struct {
+	Name        string
+	Description string
+	Private     bool
+}
+
+// ChangeRequestOptions describes a pull request (GitHub) or merge request
+// (GitLab). Repository paths are "owner/repo" on GitHub and
+// "group/project" on GitLab.
+type ChangeRequestOptions struct {
+	SourceRepo   string // Repository the changes come from, e.g. a fork
+	SourceBranch string
+	TargetRepo   string // Repository the changes are merged into
+	TargetBranch string
+	Title        string
+	Body         string
+	Draft        bool // Open as a draft that must be marked ready for review
+	// Reviewers are usernames, or "org/team" for GitHub teams, asked to
+	// review the request. GitLab ignores them. Providers implementing
+	// ChangeRequestAnnotator apply Reviewers and Labels with
+	// AnnotateChangeRequest rather than when creating the request.
+	Reviewers []string
+	Labels    []string
+}
+
+// Provider is implemented by repository hosting services
+type Provider interface {
+	// CreateRepository creates a repository owned by the authenticated user
+	CreateRepository(ctx context.Context, opts RepoOptions) error
+
+	// CreateFork forks repoPath into the authenticated user's namespace
+	CreateFork(ctx context.Context, repoPath string) error
+
+	// CreatePullOrMergeRequest opens a pull or merge request and returns
+	// its web URL, which is empty if the service did not report one
+	CreatePullOrMergeRequest(ctx context.Context, opts ChangeRequestOptions) (string, error)
+
+	// GetDefaultBranch returns the default branch of repoPath
+	GetDefaultBranch(ctx context.Context, repoPath string) (string, error)
+}
+
+// SizeReporter is implemented by providers that can report a repository's
+// size before it is cloned, so callers can check for free disk space
+type SizeReporter interface {
+	// RepositorySize returns the size of repoPath in bytes
+	RepositorySize(ctx context.Context, repoPath string) (int64, error)
+}
+
+// ChangeRequestFinder is implemented by providers that can look up open
+// pull or merge requests, so callers re-running after a failure can reuse
+// one instead of opening a duplicate
+type ChangeRequestFinder interface {
+	// FindChangeRequest returns the web URL of an open pull or merge request
+	// from opts' source branch into its target branch, or "" if there is none
+	FindChangeRequest(ctx context.Context, opts ChangeRequestOptions) (string, error)
+}
+
+// ChangeRequestAnnotator is implemented by providers that add reviewers
+// and labels to a pull or merge request after it is open. Keeping this
+// apart from creation lets callers treat a failure, such as requesting a
+// review from the request's author, as a warning about a request that
+// exists, and annotate requests they reuse.
+type ChangeRequestAnnotator interface {
+	// AnnotateChangeRequest requests reviews from opts.Reviewers and adds
+	// opts.Labels to the request with the given web URL
+	AnnotateChangeRequest(ctx context.Context, webURL string, opts ChangeRequestOptions) error
+}
+
+// ChangeRequestState is the state of a pull or merge request
+type ChangeRequestState string
+
+const (
+	ChangeRequestOpen   ChangeRequestState = "open"
+	ChangeRequestMerged ChangeRequestState = "merged"
+	ChangeRequestClosed ChangeRequestState = "closed" // closed without merging
+)
+
+// ChangeRequestWatcher is implemented by providers that can report the
+// state of a pull or merge request, so callers can wait for it to merge
+type ChangeRequestWatcher interface {
+	// ChangeRequestState returns the state of the pull or merge request
+	// with the given web URL
+	ChangeRequestState(ctx context.Context, webURL string) (ChangeRequestState, error)
+}
+
+// RepositoryDeleter is implemented by providers that can delete
+// repositories, so callers can undo repositories they created. Checking
+// RepositoryExists first tells whether a repository was created by the
+// caller or already existed.
+type RepositoryDeleter interface {
+	// ForkPath returns the path CreateFork gives the fork of repoPath in
+	// the authenticated user's namespace
+	ForkPath(ctx context.Context, repoPath string) (string, error)
+
+	// RepositoryExists reports whether repoPath exists
+	RepositoryExists(ctx context.Context, repoPath string) (bool, error)
+
+	// DeleteRepository permanently deletes repoPath
+	DeleteRepository(ctx context.Context, repoPath string) error
+}
@@ -0,0 +1,149 @@
+// Package notify sends alerts when a repository sync fails. Senders
+// implement Notifier so new channels can be added without changing callers.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"strings"
+
+	"github.com/NicabarNimble/go-gittools/internal/config"
+)
+
+// Notifier delivers a notification with a subject and body
+type Notifier interface {
+	Notify(ctx context.Context, subject, body string) error
+}
+
+// Multi sends each notification through every Notifier in the list,
+// continuing past failures and returning them joined
+type Multi []Notifier
+
+// Notify sends the notification through all notifiers
+func (m Multi) Notify(ctx context.Context, subject, body string) error {
+	var errs []error
+	for _, n := range m {
+		if err := n.Notify(ctx, subject, body); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SMTPNotifier sends notifications by email. From and To keep any display
+// name for the message headers; the SMTP envelope uses the bare addresses.
+type SMTPNotifier struct {
+	Addr string // SMTP server as host:port
+	From *mail.Address
+	To   []*mail.Address
+	Auth smtp.Auth // Optional; nil sends without authentication
+}
+
+// sendMail delivers the message; replaced in tests
+var sendMail = smtp.SendMail
+
+// NewSMTPNotifier creates an email notifier from the sync error handling
+// config. Credentials are read from GIT_SMTP_USERNAME and GIT_SMTP_PASSWORD
+// when set.
+func NewSMTPNotifier(cfg config.ErrorConfig) (*SMTPNotifier, error) {
+	if cfg.SMTPAddr == "" {
+		return nil, fmt.Errorf("smtp_addr is required for email notifications")
+	}
+	if cfg.NotifyEmail == "" {
+		return nil, fmt.Errorf("notify_email is required for email notifications")
+	}
+
+	to, err := mail.ParseAddress(cfg.NotifyEmail)
+	if err != nil {
+		return nil, fmt.Errorf("invalid notify_email %q: %w", cfg.NotifyEmail, err)
+	}
+	from := to
+	if cfg.SMTPFrom != "" {
+		if from, err = mail.ParseAddress(cfg.SMTPFrom); err != nil {
+			return nil, fmt.Errorf("invalid smtp_from %q: %w", cfg.SMTPFrom, err)
+		}
+	}
+
+	n := &SMTPNotifier{
+		Addr: cfg.SMTPAddr,
+		From: from,
+		To:   []*mail.Address{to},
+	}
+	if user := os.Getenv("GIT_SMTP_USERNAME"); user != "" {
+		host := cfg.SMTPAddr
+		if i := strings.LastIndex(host, ":"); i >= 0 {
+			host = host[:i]
+		}
+		n.Auth = smtp.PlainAuth("", user, os.Getenv("GIT_SMTP_PASSWORD"), host)
+	}
+	return n, nil
+}
+
+// Notify sends the notification as a plain-text email
+func (n *SMTPNotifier) Notify(ctx context.Context, subject, body string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	headerTo := make([]string, len(n.To))
+	rcpt := make([]string, len(n.To))
+	for i, addr := range n.To {
+		headerTo[i] = addr.String()
+		rcpt[i] = addr.Address
+	}
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		n.From, strings.Join(headerTo, ", "), subject, body)
+	if err := sendMail(n.Addr, n.Auth, n.From.Address, rcpt, []byte(msg)); err != nil {
+		return fmt.Errorf("failed to send email notification: %w", err)
+	}
+	return nil
+}
+
+// warnings receives notices about notifiers that cannot be set up. It is a
+// variable so it can be mocked in tests.
+var warnings io.Writer = os.Stderr
+
+// FromConfig returns the notifiers configured by cfg: email when
+// notify_email is set and a webhook when webhook_url is set. It returns nil
+// if notifications are disabled. Configs written before smtp_addr existed
+// have no mail server, so email is skipped with a warning rather than
+// failing the sync.
+func FromConfig(cfg config.ErrorConfig) (Notifier, error) {
+	if !cfg.Notify {
+		return nil, nil
+	}
+
+	var notifiers Multi
+	skipped := false
+	if cfg.NotifyEmail != "" {
+		if cfg.SMTPAddr == "" {
+			fmt.Fprintf(warnings, "Warning: smtp_addr is not set, so no email is sent to %s\n", cfg.NotifyEmail)
+			skipped = true
+		} else {
+			n, err := NewSMTPNotifier(cfg)
+			if err != nil {
+				return nil, err
+			}
+			notifiers = append(notifiers, n)
+		}
+	}
+	if cfg.WebhookURL != "" {
+		notifiers = append(notifiers, NewWebhookNotifier(cfg.WebhookURL))
+	}
+
+	switch len(notifiers) {
+	case 0:
+		if skipped {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("notify_email or webhook_url is required when notifications are enabled")
+	case 1:
+		return notifiers[0], nil
+	}
+	return notifiers, nil
+}
@@ -0,0 +1,154 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"github.com/NicabarNimble/go-gittools/internal/config"
+)
+
+func TestSMTPNotifier(t *testing.T) {
+	defer func(orig func(string, smtp.Auth, string, []string, []byte) error) { sendMail = orig }(sendMail)
+
+	var gotAddr, gotFrom string
+	var gotTo []string
+	var gotMsg string
+	sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotFrom, gotTo, gotMsg = addr, from, to, string(msg)
+		return nil
+	}
+
+	n, err := NewSMTPNotifier(config.ErrorConfig{
+		Notify:      true,
+		NotifyEmail: "ops@example.com",
+		SMTPAddr:    "smtp.example.com:587",
+		SMTPFrom:    "gitsync@example.com",
+	})
+	if err != nil {
+		t.Fatalf("NewSMTPNotifier() error = %v", err)
+	}
+
+	if err := n.Notify(context.Background(), "sync failed", "run #1 failed"); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	if gotAddr != "smtp.example.com:587" || gotFrom != "gitsync@example.com" {
+		t.Errorf("sendMail called with addr %q from %q", gotAddr, gotFrom)
+	}
+	if len(gotTo) != 1 || gotTo[0] != "ops@example.com" {
+		t.Errorf("sendMail called with recipients %v", gotTo)
+	}
+	if !strings.Contains(gotMsg, "Subject: sync failed\r\n") || !strings.Contains(gotMsg, "run #1 failed") {
+		t.Errorf("unexpected message: %q", gotMsg)
+	}
+}
+
+func TestSMTPNotifierDisplayNames(t *testing.T) {
+	defer func(orig func(string, smtp.Auth, string, []string, []byte) error) { sendMail = orig }(sendMail)
+
+	var gotFrom string
+	var gotTo []string
+	var gotMsg string
+	sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotFrom, gotTo, gotMsg = from, to, string(msg)
+		return nil
+	}
+
+	n, err := NewSMTPNotifier(config.ErrorConfig{
+		Notify:      true,
+		NotifyEmail: "Ops Team <ops@example.com>",
+		SMTPAddr:    "smtp.example.com:587",
+		SMTPFrom:    "Gitsync <gitsync@example.com>",
+	})
+	if err != nil {
+		t.Fatalf("NewSMTPNotifier() error = %v", err)
+	}
+	if err := n.Notify(context.Background(), "sync failed", "run #1 failed"); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	// The envelope takes bare addresses; the headers keep the names
+	if gotFrom != "gitsync@example.com" || len(gotTo) != 1 || gotTo[0] != "ops@example.com" {
+		t.Errorf("sendMail called from %q to %v, want bare addresses", gotFrom, gotTo)
+	}
+	if !strings.Contains(gotMsg, "From: \"Gitsync\" <gitsync@example.com>\r\n") ||
+		!strings.Contains(gotMsg, "To: \"Ops Team\" <ops@example.com>\r\n") {
+		t.Errorf("headers lost the display names: %q", gotMsg)
+	}
+
+	if _, err := NewSMTPNotifier(config.ErrorConfig{NotifyEmail: "not an address", SMTPAddr: "localhost:25"}); err == nil {
+		t.Error("NewSMTPNotifier() with an invalid notify_email expected error")
+	}
+}
+
+func TestFromConfig(t *testing.T) {
+	n, err := FromConfig(config.ErrorConfig{Notify: false, NotifyEmail: "ops@example.com"})
+	if err != nil || n != nil {
+		t.Errorf("FromConfig() with notifications disabled = %v, %v; want nil, nil", n, err)
+	}
+
+	// Email without a mail server is skipped with a warning instead of
+	// failing configs that passed validation
+	var warned bytes.Buffer
+	defer func(orig io.Writer) { warnings = orig }(warnings)
+	warnings = &warned
+	n, err = FromConfig(config.ErrorConfig{Notify: true, NotifyEmail: "ops@example.com"})
+	if err != nil || n != nil {
+		t.Errorf("FromConfig() without smtp_addr = %v, %v; want nil, nil", n, err)
+	}
+	if !strings.Contains(warned.String(), "smtp_addr is not set") {
+		t.Errorf("FromConfig() without smtp_addr warned %q", warned.String())
+	}
+
+	n, err = FromConfig(config.ErrorConfig{Notify: true, NotifyEmail: "ops@example.com", WebhookURL: "https://hooks.example.com/T000/B000"})
+	if err != nil {
+		t.Fatalf("FromConfig() without smtp_addr and with webhook error = %v", err)
+	}
+	if _, ok := n.(*WebhookNotifier); !ok {
+		t.Errorf("FromConfig() without smtp_addr and with webhook = %T, want *WebhookNotifier", n)
+	}
+
+	if _, err := FromConfig(config.ErrorConfig{Notify: true}); err == nil {
+		t.Error("FromConfig() without any channel expected error")
+	}
+
+	n, err = FromConfig(config.ErrorConfig{Notify: true, NotifyEmail: "ops@example.com", SMTPAddr: "localhost:25"})
+	if err != nil {
+		t.Fatalf("FromConfig() error = %v", err)
+	}
+	if _, ok := n.(*SMTPNotifier); !ok {
+		t.Errorf("FromConfig() = %T, want *SMTPNotifier", n)
+	}
+}
+
+func TestFromConfigEmailAndWebhook(t *testing.T) {
+	n, err := FromConfig(config.ErrorConfig{
+		Notify:      true,
+		NotifyEmail: "ops@example.com",
+		SMTPAddr:    "localhost:25",
+		WebhookURL:  "https://hooks.example.com/T000/B000",
+	})
+	if err != nil {
+		t.Fatalf("FromConfig() error = %v", err)
+	}
+
+	multi, ok := n.(Multi)
+	if !ok || len(multi) != 2 {
+		t.Fatalf("FromConfig() = %#v, want email and webhook notifiers", n)
+	}
+	if _, ok := multi[1].(*WebhookNotifier); !ok {
+		t.Errorf("second notifier = %T, want *WebhookNotifier", multi[1])
+	}
+
+	n, err = FromConfig(config.ErrorConfig{Notify: true, WebhookURL: "https://hooks.example.com/T000/B000"})
+	if err != nil {
+		t.Fatalf("FromConfig() webhook only error = %v", err)
+	}
+	if _, ok := n.(*WebhookNotifier); !ok {
+		t.Errorf("FromConfig() webhook only = %T, want *WebhookNotifier", n)
+	}
+}
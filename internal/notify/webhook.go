@@ -0,0 +1,63 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// WebhookNotifier posts notifications as JSON to a URL. The payload's
+// "text" field makes it directly usable with Slack incoming webhooks.
+type WebhookNotifier struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+// webhookPayload is the JSON body sent to the webhook
+type webhookPayload struct {
+	Text    string `json:"text"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// NewWebhookNotifier creates a notifier that posts to url
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		URL:        url,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Notify posts the notification to the webhook URL
+func (n *WebhookNotifier) Notify(ctx context.Context, subject, body string) error {
+	payload, err := json.Marshal(webhookPayload{
+		Text:    fmt.Sprintf("*%s*\n%s", subject, body),
+		Subject: subject,
+		Body:    body,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", n.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, string(respBody))
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhookNotifier(t *testing.T) {
+	var got map[string]string
+	var contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		contentType = r.Header.Get("Content-Type")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := NewWebhookNotifier(server.URL)
+	err := n.Notify(context.Background(), "gitsync: sync failed for owner/repo", "Workflow run #42 for owner/repo failed: workflow failed with conclusion: failure")
+	if err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	want := map[string]string{
+		"text":    "*gitsync: sync failed for owner/repo*\nWorkflow run #42 for owner/repo failed: workflow failed with conclusion: failure",
+		"subject": "gitsync: sync failed for owner/repo",
+		"body":    "Workflow run #42 for owner/repo failed: workflow failed with conclusion: failure",
+	}
+	if contentType != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", contentType)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("payload[%q] = %q, want %q", k, got[k], v)
+		}
+	}
+}
+
+func TestWebhookNotifierError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("no_service"))
+	}))
+	defer server.Close()
+
+	err := NewWebhookNotifier(server.URL).Notify(context.Background(), "subject", "body")
+	if err == nil {
+		t.Fatal("Notify() expected error for 404 response")
+	}
+}
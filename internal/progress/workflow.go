@@ -3,6 +3,9 @@ package progress
 import (
 	"fmt"
 	"io"
+	"os"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -16,6 +19,18 @@ const (
 	WorkflowFailed     WorkflowStatus = "failed"
 )
 
+// ParseWorkflowStatus converts s into a WorkflowStatus, ignoring case and
+// surrounding whitespace
+func ParseWorkflowStatus(s string) (WorkflowStatus, error) {
+	status := WorkflowStatus(strings.ToLower(strings.TrimSpace(s)))
+	switch status {
+	case WorkflowQueued, WorkflowInProgress, WorkflowCompleted, WorkflowFailed:
+		return status, nil
+	}
+	return "", fmt.Errorf("invalid workflow status %q: must be one of %s, %s, %s, %s",
+		s, WorkflowQueued, WorkflowInProgress, WorkflowCompleted, WorkflowFailed)
+}
+
 // WorkflowOperation represents a GitHub Actions workflow operation
 type WorkflowOperation struct {
 	*Operation
@@ -26,17 +41,60 @@ type WorkflowOperation struct {
 	LogCallback func(string) // Callback for handling log lines
 }
 
+// spinnerFrames are cycled through while a workflow is in progress
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+// defaultSpinnerInterval is how often the in-progress line is redrawn
+const defaultSpinnerInterval = 200 * time.Millisecond
+
 // WorkflowTracker provides tracking specifically for GitHub Actions workflows
 type WorkflowTracker struct {
 	*ConsoleTracker
 	currentWorkflow *WorkflowOperation
+
+	out             io.Writer
+	tty             bool // Redraw lines in place with a spinner
+	spinnerInterval time.Duration
+
+	mu          sync.Mutex
+	stopSpinner chan struct{}
+	spinnerDone chan struct{}
 }
 
-// NewWorkflowTracker creates a new tracker for GitHub Actions workflows
+// NewWorkflowTracker creates a new tracker for GitHub Actions workflows.
+// Output goes to stdout; the spinner is only shown when stdout is a terminal.
 func NewWorkflowTracker() *WorkflowTracker {
 	return &WorkflowTracker{
-		ConsoleTracker: NewConsoleTracker(),
+		ConsoleTracker:  NewConsoleTracker(),
+		out:             os.Stdout,
+		tty:             isTerminal(os.Stdout),
+		spinnerInterval: defaultSpinnerInterval,
+	}
+}
+
+// SetOutput redirects workflow status output to w. When tty is false,
+// statuses are written as plain lines without a spinner or cursor control.
+func (t *WorkflowTracker) SetOutput(w io.Writer, tty bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.out = w
+	t.tty = tty
+}
+
+// isTerminal reports whether f is attached to a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
 	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// printf writes to the tracker's output while holding its lock
+func (t *WorkflowTracker) printf(format string, args ...interface{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fmt.Fprintf(t.out, format, args...)
 }
 
 // StartWorkflow begins tracking a new workflow operation
@@ -48,7 +106,7 @@ func (t *WorkflowTracker) StartWorkflow(name string, workflowID, runID int64) *W
 		RunID:      runID,
 		Status:     WorkflowQueued,
 	}
-	fmt.Printf("Starting workflow %s (Run ID: %d)\n", name, runID)
+	t.printf("Starting workflow %s (Run ID: %d)\n", name, runID)
 	return t.currentWorkflow
 }
 
@@ -58,19 +116,67 @@ func (t *WorkflowTracker) UpdateWorkflowStatus(status WorkflowStatus) {
 		return
 	}
 
+	t.stopSpinning()
 	t.currentWorkflow.Status = status
-	statusStr := string(status)
-	
+	startTime := t.currentWorkflow.StartTime
+
 	switch status {
 	case WorkflowCompleted:
-		duration := time.Since(t.currentWorkflow.StartTime)
-		fmt.Printf("\nWorkflow completed successfully (took %v)\n", duration)
+		duration := time.Since(startTime)
+		t.printf("\nWorkflow completed successfully (took %v)\n", duration)
 	case WorkflowFailed:
-		duration := time.Since(t.currentWorkflow.StartTime)
-		fmt.Printf("\nWorkflow failed (after %v)\n", duration)
+		duration := time.Since(startTime)
+		t.printf("\nWorkflow failed (after %v)\n", duration)
+	case WorkflowInProgress:
+		if t.tty {
+			t.startSpinning(startTime)
+		} else {
+			t.printf("Workflow status: %s (elapsed %v)\n", status, time.Since(startTime).Round(time.Second))
+		}
 	default:
-		fmt.Printf("\rWorkflow status: %s", statusStr)
+		if t.tty {
+			t.printf("\r\033[KWorkflow status: %s", status)
+		} else {
+			t.printf("Workflow status: %s\n", status)
+		}
+	}
+}
+
+// startSpinning redraws the in-progress line with a spinner and the elapsed
+// time until stopSpinning is called
+func (t *WorkflowTracker) startSpinning(startTime time.Time) {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	t.stopSpinner = stop
+	t.spinnerDone = done
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(t.spinnerInterval)
+		defer ticker.Stop()
+
+		for frame := 0; ; frame++ {
+			t.printf("\r\033[K%s Workflow status: %s (elapsed %v)",
+				spinnerFrames[frame%len(spinnerFrames)], WorkflowInProgress,
+				time.Since(startTime).Round(time.Second))
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// stopSpinning stops the spinner, if running, and waits for its last redraw
+func (t *WorkflowTracker) stopSpinning() {
+	if t.stopSpinner == nil {
+		return
 	}
+	close(t.stopSpinner)
+	<-t.spinnerDone
+	t.stopSpinner = nil
+	t.spinnerDone = nil
 }
 
 // SetLogStream sets up log streaming for the current workflow
@@ -92,6 +198,7 @@ func (t *WorkflowTracker) WorkflowError(err error) {
 	if t.currentWorkflow == nil {
 		return
 	}
+	t.stopSpinning()
 	t.currentWorkflow.Status = WorkflowFailed
-	fmt.Printf("\nWorkflow error: %v\n", err)
+	t.printf("\nWorkflow error: %v\n", err)
 }
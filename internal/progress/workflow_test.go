@@ -3,6 +3,7 @@ package progress
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 )
@@ -111,3 +112,77 @@ func TestWorkflowDuration(t *testing.T) {
 		t.Error("Expected workflow duration to be at least 10ms")
 	}
 }
+
+func TestParseWorkflowStatus(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    WorkflowStatus
+		wantErr bool
+	}{
+		{input: "queued", want: WorkflowQueued},
+		{input: "in_progress", want: WorkflowInProgress},
+		{input: "completed", want: WorkflowCompleted},
+		{input: "failed", want: WorkflowFailed},
+		{input: " Completed ", want: WorkflowCompleted},
+		{input: "invalid", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseWorkflowStatus(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseWorkflowStatus(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseWorkflowStatus(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkflowTrackerNonTTYOutput(t *testing.T) {
+	tracker := NewWorkflowTracker()
+	var buf bytes.Buffer
+	tracker.SetOutput(&buf, false)
+
+	tracker.StartWorkflow("plain-test", 1, 1)
+	tracker.UpdateWorkflowStatus(WorkflowQueued)
+	tracker.UpdateWorkflowStatus(WorkflowInProgress)
+	tracker.UpdateWorkflowStatus(WorkflowCompleted)
+
+	out := buf.String()
+	if strings.ContainsAny(out, "\x1b\r") {
+		t.Errorf("Expected no escape codes or carriage returns in non-TTY output, got %q", out)
+	}
+	if !strings.Contains(out, "Workflow status: in_progress (elapsed ") {
+		t.Errorf("Expected elapsed time line in output, got %q", out)
+	}
+}
+
+func TestWorkflowTrackerSpinner(t *testing.T) {
+	tracker := NewWorkflowTracker()
+	var buf bytes.Buffer
+	tracker.SetOutput(&buf, true)
+	tracker.spinnerInterval = time.Millisecond
+
+	tracker.StartWorkflow("spinner-test", 1, 1)
+	tracker.UpdateWorkflowStatus(WorkflowInProgress)
+	time.Sleep(20 * time.Millisecond)
+	tracker.UpdateWorkflowStatus(WorkflowCompleted)
+
+	out := buf.String()
+	if strings.Count(out, "\r\x1b[K") < 2 {
+		t.Errorf("Expected the in-progress line to be redrawn in place, got %q", out)
+	}
+	if !strings.Contains(out, "Workflow completed successfully") {
+		t.Errorf("Expected completion message, got %q", out)
+	}
+
+	// No redraws may happen after the workflow completes
+	before := buf.Len()
+	time.Sleep(5 * time.Millisecond)
+	if buf.Len() != before {
+		t.Error("Expected spinner to stop after completion")
+	}
+}
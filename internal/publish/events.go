@@ -0,0 +1,83 @@
+// Package publish defines the structured events emitted while publishing a
+// private repository to a public fork, so callers can report progress
+// without scraping stdout.
+package publish
+
+import (
+	"fmt"
+	"io"
+)
+
+// EventType identifies a step of the publish pipeline
+type EventType string
+
+const (
+	// ForkCreated is emitted after the public fork has been created
+	ForkCreated EventType = "fork_created"
+	// Pushed is emitted after the repository has been pushed to the fork
+	Pushed EventType = "pushed"
+	// PRCreated is emitted after a pull or merge request has been opened
+	PRCreated EventType = "pr_created"
+	// PRReused is emitted instead of PRCreated when an open pull or merge
+	// request for the branch already exists, e.g. when re-running after a
+	// failure
+	PRReused EventType = "pr_reused"
+	// PRMerged is emitted when a pull or merge request that was waited on
+	// has been merged
+	PRMerged EventType = "pr_merged"
+	// UpToDate is emitted instead of Pushed when the fork's branch already
+	// matches the source, in which case no pull request is opened either
+	UpToDate EventType = "up_to_date"
+	// ForkDeleted is emitted when a fork created by a failed run has been
+	// deleted again
+	ForkDeleted EventType = "fork_deleted"
+)
+
+// Event describes a completed step of the publish pipeline
+type Event struct {
+	Type EventType
+	// Source is the repository the step read from
+	Source string
+	// Target is the repository the step wrote to
+	Target string
+	// Title is the pull or merge request title for PRCreated events
+	Title string
+	// URL is the web URL of the pull or merge request for PRCreated,
+	// PRReused and PRMerged events, empty if the service did not report one
+	URL string
+}
+
+// String returns a human-readable description of the event
+func (e Event) String() string {
+	switch e.Type {
+	case ForkCreated:
+		return fmt.Sprintf("Created fork of %s", e.Source)
+	case Pushed:
+		return fmt.Sprintf("Successfully published %s to %s", e.Source, e.Target)
+	case PRMerged:
+		return fmt.Sprintf("Pull request merged: %s", e.URL)
+	case UpToDate:
+		return fmt.Sprintf("Already up to date: nothing to publish from %s to %s", e.Source, e.Target)
+	case PRCreated:
+		if e.URL != "" {
+			return fmt.Sprintf("Successfully created pull request: %s (%s)", e.Title, e.URL)
+		}
+		return fmt.Sprintf("Successfully created pull request: %s", e.Title)
+	case PRReused:
+		return fmt.Sprintf("Reusing open pull request: %s", e.URL)
+	case ForkDeleted:
+		return fmt.Sprintf("Rolled back: deleted fork %s", e.Source)
+	default:
+		return string(e.Type)
+	}
+}
+
+// Handler receives events as the publish pipeline progresses
+type Handler func(Event)
+
+// Printer returns a Handler that writes each event to w on its own line
+func Printer(w io.Writer) Handler {
+	return func(e Event) {
+		fmt.Fprintln(w, e.String())
+	}
+}
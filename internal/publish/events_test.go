@@ -0,0 +1,75 @@
+package publish
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEventString(t *testing.T) {
+	tests := []struct {
+		name  string
+		event Event
+		want  string
+	}{
+		{
+			name:  "fork created",
+			event: Event{Type: ForkCreated, Source: "owner/repo"},
+			want:  "Created fork of owner/repo",
+		},
+		{
+			name:  "pushed",
+			event: Event{Type: Pushed, Source: "https://github.com/owner/private", Target: "https://github.com/fork/public"},
+			want:  "Successfully published https://github.com/owner/private to https://github.com/fork/public",
+		},
+		{
+			name:  "pr merged",
+			event: Event{Type: PRMerged, URL: "https://github.com/owner/repo/pull/1"},
+			want:  "Pull request merged: https://github.com/owner/repo/pull/1",
+		},
+		{
+			name:  "up to date",
+			event: Event{Type: UpToDate, Source: "https://github.com/owner/private", Target: "https://github.com/fork/public"},
+			want:  "Already up to date: nothing to publish from https://github.com/owner/private to https://github.com/fork/public",
+		},
+		{
+			name:  "pr created with URL",
+			event: Event{Type: PRCreated, Title: "Release", URL: "https://github.com/owner/repo/pull/1"},
+			want:  "Successfully created pull request: Release (https://github.com/owner/repo/pull/1)",
+		},
+		{
+			name:  "pr reused",
+			event: Event{Type: PRReused, URL: "https://github.com/owner/repo/pull/1"},
+			want:  "Reusing open pull request: https://github.com/owner/repo/pull/1",
+		},
+		{
+			name:  "fork deleted",
+			event: Event{Type: ForkDeleted, Source: "user/public-fork"},
+			want:  "Rolled back: deleted fork user/public-fork",
+		},
+		{
+			name:  "pr created without URL",
+			event: Event{Type: PRCreated, Title: "Release"},
+			want:  "Successfully created pull request: Release",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.event.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrinter(t *testing.T) {
+	var buf bytes.Buffer
+	handler := Printer(&buf)
+	handler(Event{Type: ForkCreated, Source: "owner/repo"})
+	handler(Event{Type: PRCreated, Title: "Release"})
+
+	want := "Created fork of owner/repo\nSuccessfully created pull request: Release\n"
+	if buf.String() != want {
+		t.Errorf("Printer wrote %q, want %q", buf.String(), want)
+	}
+}
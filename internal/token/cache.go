@@ -0,0 +1,103 @@
+package token
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// CachingStorage decorates a Storage by memoizing Retrieve results for a TTL.
+// It is useful in front of backends where reads are relatively expensive,
+// such as EnvStorage which parses JSON on every Retrieve. Store, Delete and
+// CompareAndSwap invalidate the cached entry for their key.
+type CachingStorage struct {
+	inner   Storage
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	now     func() time.Time // Clock, replaceable in tests
+}
+
+// cacheEntry is a cached token and the time the cache entry expires
+type cacheEntry struct {
+	token     Token
+	expiresAt time.Time
+}
+
+// NewCachingStorage wraps inner with a read cache that keeps tokens for ttl
+func NewCachingStorage(inner Storage, ttl time.Duration) *CachingStorage {
+	return &CachingStorage{
+		inner:   inner,
+		ttl:     ttl,
+		entries: make(map[string]cacheEntry),
+		now:     time.Now,
+	}
+}
+
+// Store implements Storage.Store
+func (c *CachingStorage) Store(ctx context.Context, key string, token Token) error {
+	defer c.invalidate(key)
+	return c.inner.Store(ctx, key, token)
+}
+
+// Retrieve implements Storage.Retrieve
+func (c *CachingStorage) Retrieve(ctx context.Context, key string) (Token, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+
+	if ok && c.now().Before(entry.expiresAt) {
+		// The token may expire while it sits in the cache
+		if IsExpired(entry.token) {
+			c.invalidate(key)
+			return Token{}, ErrTokenExpired
+		}
+		return entry.token, nil
+	}
+
+	token, err := c.inner.Retrieve(ctx, key)
+	if err != nil {
+		c.invalidate(key)
+		return Token{}, err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = cacheEntry{token: token, expiresAt: c.now().Add(c.ttl)}
+	c.mu.Unlock()
+
+	return token, nil
+}
+
+// CompareAndSwap implements CompareAndSwapper by delegating to the inner storage
+func (c *CachingStorage) CompareAndSwap(ctx context.Context, key string, old, new Token) (bool, error) {
+	defer c.invalidate(key)
+	return CompareAndSwap(ctx, c.inner, key, old, new)
+}
+
+// Delete implements Storage.Delete
+func (c *CachingStorage) Delete(ctx context.Context, key string) error {
+	defer c.invalidate(key)
+	return c.inner.Delete(ctx, key)
+}
+
+// List implements Storage.List
+func (c *CachingStorage) List(ctx context.Context) ([]string, error) {
+	return c.inner.List(ctx)
+}
+
+// Close implements Storage.Close
+func (c *CachingStorage) Close(ctx context.Context) error {
+	c.mu.Lock()
+	c.entries = make(map[string]cacheEntry)
+	c.mu.Unlock()
+
+	return c.inner.Close(ctx)
+}
+
+// invalidate removes the cached entry for a key
+func (c *CachingStorage) invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+}
@@ -0,0 +1,97 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingStorage counts Retrieve calls reaching the wrapped storage
+type countingStorage struct {
+	*MemoryStorage
+	retrieves int32
+}
+
+func (s *countingStorage) Retrieve(ctx context.Context, key string) (Token, error) {
+	atomic.AddInt32(&s.retrieves, 1)
+	return s.MemoryStorage.Retrieve(ctx, key)
+}
+
+func TestCachingStorage_Retrieve(t *testing.T) {
+	inner := &countingStorage{MemoryStorage: NewMemoryStorage()}
+	storage := NewCachingStorage(inner, time.Minute)
+	ctx := context.Background()
+	defer storage.Close(ctx)
+
+	now := time.Now()
+	storage.now = func() time.Time { return now }
+
+	if err := storage.Store(ctx, "GITHUB", Token{Value: "cached-token"}); err != nil {
+		t.Fatalf("Failed to store token: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		token, err := storage.Retrieve(ctx, "GITHUB")
+		if err != nil {
+			t.Fatalf("Failed to retrieve token: %v", err)
+		}
+		if token.Value != "cached-token" {
+			t.Errorf("Retrieve() value = %q, want %q", token.Value, "cached-token")
+		}
+	}
+	if got := atomic.LoadInt32(&inner.retrieves); got != 1 {
+		t.Errorf("inner Retrieve calls within TTL = %d, want 1", got)
+	}
+
+	// Advancing past the TTL forces a fresh read
+	now = now.Add(2 * time.Minute)
+	if _, err := storage.Retrieve(ctx, "GITHUB"); err != nil {
+		t.Fatalf("Failed to retrieve token: %v", err)
+	}
+	if got := atomic.LoadInt32(&inner.retrieves); got != 2 {
+		t.Errorf("inner Retrieve calls after TTL = %d, want 2", got)
+	}
+}
+
+func TestCachingStorage_Invalidation(t *testing.T) {
+	inner := &countingStorage{MemoryStorage: NewMemoryStorage()}
+	storage := NewCachingStorage(inner, time.Minute)
+	ctx := context.Background()
+	defer storage.Close(ctx)
+
+	if err := storage.Store(ctx, "GITHUB", Token{Value: "old-token"}); err != nil {
+		t.Fatalf("Failed to store token: %v", err)
+	}
+	if _, err := storage.Retrieve(ctx, "GITHUB"); err != nil {
+		t.Fatalf("Failed to retrieve token: %v", err)
+	}
+
+	t.Run("Store invalidates", func(t *testing.T) {
+		if err := storage.Store(ctx, "GITHUB", Token{Value: "new-token"}); err != nil {
+			t.Fatalf("Failed to store token: %v", err)
+		}
+
+		token, err := storage.Retrieve(ctx, "GITHUB")
+		if err != nil {
+			t.Fatalf("Failed to retrieve token: %v", err)
+		}
+		if token.Value != "new-token" {
+			t.Errorf("Retrieve() after Store = %q, want %q", token.Value, "new-token")
+		}
+		if got := atomic.LoadInt32(&inner.retrieves); got != 2 {
+			t.Errorf("inner Retrieve calls = %d, want 2", got)
+		}
+	})
+
+	t.Run("Delete invalidates", func(t *testing.T) {
+		if err := storage.Delete(ctx, "GITHUB"); err != nil {
+			t.Fatalf("Failed to delete token: %v", err)
+		}
+
+		if _, err := storage.Retrieve(ctx, "GITHUB"); !errors.Is(err, ErrTokenNotFound) {
+			t.Errorf("Retrieve() after Delete error = %v, want ErrTokenNotFound", err)
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package token
+
+import (
+	"context"
+	"errors"
+)
+
+// CompositeStorage chains several backends, e.g. environment variables,
+// then a file, then the OS keyring. Retrieve returns the first token found;
+// Store writes to the first backend that accepts it.
+type CompositeStorage struct {
+	backends []Storage
+}
+
+// NewCompositeStorage creates a storage that consults backends in order
+func NewCompositeStorage(backends ...Storage) *CompositeStorage {
+	return &CompositeStorage{backends: backends}
+}
+
+// skippable reports whether a backend error means "try the next backend"
+func skippable(err error) bool {
+	return errors.Is(err, ErrTokenNotFound) ||
+		errors.Is(err, ErrStorageUnavailable) ||
+		errors.Is(err, ErrReadOnly)
+}
+
+// Store implements Storage.Store by writing to the first writable backend.
+// EnvStorage is skipped: it only sets a variable in this process, so a
+// token stored there would be lost on exit.
+func (c *CompositeStorage) Store(ctx context.Context, key string, token Token) error {
+	for _, b := range c.backends {
+		if _, ok := b.(*EnvStorage); ok {
+			continue
+		}
+		err := b.Store(ctx, key, token)
+		if err == nil || !skippable(err) {
+			return err
+		}
+	}
+	return ErrReadOnly
+}
+
+// Retrieve implements Storage.Retrieve. Backends that do not have the token
+// or are unavailable are skipped; any other error is returned as is.
+func (c *CompositeStorage) Retrieve(ctx context.Context, key string) (Token, error) {
+	for _, b := range c.backends {
+		token, err := b.Retrieve(ctx, key)
+		if err == nil || !skippable(err) {
+			return token, err
+		}
+	}
+	return Token{}, ErrTokenNotFound
+}
+
+// Delete implements Storage.Delete by removing the key from every writable
+// backend, so a token deleted from one backend is not served by the next
+func (c *CompositeStorage) Delete(ctx context.Context, key string) error {
+	for _, b := range c.backends {
+		if err := b.Delete(ctx, key); err != nil && !skippable(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// List implements Storage.List, returning the union of all backends' keys
+func (c *CompositeStorage) List(ctx context.Context) ([]string, error) {
+	seen := make(map[string]bool)
+	keys := []string{}
+	for _, b := range c.backends {
+		bkeys, err := b.List(ctx)
+		if err != nil {
+			if skippable(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, k := range bkeys {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	return keys, nil
+}
+
+// Close implements Storage.Close, closing every backend
+func (c *CompositeStorage) Close(ctx context.Context) error {
+	var errs []error
+	for _, b := range c.backends {
+		if err := b.Close(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
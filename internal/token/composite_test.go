@@ -0,0 +1,104 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+)
+
+// readOnlyStorage wraps a Storage and rejects writes
+type readOnlyStorage struct {
+	Storage
+}
+
+func (r readOnlyStorage) Store(context.Context, string, Token) error { return ErrReadOnly }
+func (r readOnlyStorage) Delete(context.Context, string) error       { return ErrReadOnly }
+
+func TestCompositeStorageRetrieveOrder(t *testing.T) {
+	ctx := context.Background()
+	first := NewMemoryStorage()
+	second := NewMemoryStorage()
+	first.Store(ctx, "github", Token{Value: "from-first"})
+	second.Store(ctx, "github", Token{Value: "from-second"})
+	second.Store(ctx, "gitlab", Token{Value: "gitlab-second"})
+
+	c := NewCompositeStorage(first, second)
+
+	got, err := c.Retrieve(ctx, "github")
+	if err != nil || got.Value != "from-first" {
+		t.Errorf("Retrieve(github) = %q, %v; want first backend's token", got.Value, err)
+	}
+
+	got, err = c.Retrieve(ctx, "gitlab")
+	if err != nil || got.Value != "gitlab-second" {
+		t.Errorf("Retrieve(gitlab) = %q, %v; want fallback to second backend", got.Value, err)
+	}
+
+	if _, err := c.Retrieve(ctx, "azure"); !errors.Is(err, ErrTokenNotFound) {
+		t.Errorf("Retrieve(azure) error = %v, want ErrTokenNotFound", err)
+	}
+}
+
+func TestCompositeStorageStoreTarget(t *testing.T) {
+	ctx := context.Background()
+	env := NewMemoryStorage()
+	file := NewMemoryStorage()
+
+	c := NewCompositeStorage(readOnlyStorage{env}, file)
+	if err := c.Store(ctx, "github", Token{Value: "stored"}); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+
+	if _, err := env.Retrieve(ctx, "github"); !errors.Is(err, ErrTokenNotFound) {
+		t.Error("Store() wrote to the read-only backend")
+	}
+	if got, err := file.Retrieve(ctx, "github"); err != nil || got.Value != "stored" {
+		t.Errorf("first writable backend has %q, %v; want stored token", got.Value, err)
+	}
+
+	// With no writable backend the store fails
+	if err := NewCompositeStorage(readOnlyStorage{env}).Store(ctx, "github", Token{Value: "x"}); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Store() with only read-only backends error = %v, want ErrReadOnly", err)
+	}
+}
+
+func TestCompositeStorageStoreSkipsEnv(t *testing.T) {
+	ctx := context.Background()
+	env := NewEnvStorage()
+	t.Setenv(env.FormatEnvKey("github"), "")
+	keyring := NewMemoryStorage()
+
+	c := NewCompositeStorage(env, keyring)
+	if err := c.Store(ctx, "github", Token{Value: "ghp_stored"}); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+	if _, err := env.Retrieve(ctx, "github"); !errors.Is(err, ErrTokenNotFound) {
+		t.Errorf("Store() wrote to the process environment, Retrieve() error = %v", err)
+	}
+	if got, err := keyring.Retrieve(ctx, "github"); err != nil || got.Value != "ghp_stored" {
+		t.Errorf("persistent backend has %q, %v; want stored token", got.Value, err)
+	}
+
+	if err := NewCompositeStorage(env).Store(ctx, "github", Token{Value: "ghp_stored"}); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Store() with only environment storage error = %v, want ErrReadOnly", err)
+	}
+}
+
+func TestCompositeStorageList(t *testing.T) {
+	ctx := context.Background()
+	first := NewMemoryStorage()
+	second := NewMemoryStorage()
+	first.Store(ctx, "github", Token{Value: "a"})
+	second.Store(ctx, "github", Token{Value: "b"})
+	second.Store(ctx, "gitlab", Token{Value: "c"})
+
+	keys, err := NewCompositeStorage(first, second).List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	sort.Strings(keys)
+	if len(keys) != 2 || keys[0] != "github" || keys[1] != "gitlab" {
+		t.Errorf("List() = %v, want [github gitlab]", keys)
+	}
+}
@@ -26,6 +26,31 @@ func DetectProvider(tokenValue string) Provider {
 	}
 }
 
+// Key returns the storage key for a provider's token
+// An empty account selects the provider's default token, so
+// Key(ProviderGitHub, "") is "GITHUB" and Key(ProviderGitHub, "work") is "GITHUB_WORK"
+func Key(provider Provider, account string) string {
+	if account == "" {
+		return string(provider)
+	}
+	return string(provider) + "_" + strings.ToUpper(account)
+}
+
+// ParseKey splits a storage key into its provider and account name
+// Keys that don't start with a known provider return an empty provider
+func ParseKey(key string) (Provider, string) {
+	key = strings.ToUpper(key)
+	for _, provider := range []Provider{ProviderGitHub, ProviderGitLab} {
+		if key == string(provider) {
+			return provider, ""
+		}
+		if strings.HasPrefix(key, string(provider)+"_") {
+			return provider, strings.TrimPrefix(key, string(provider)+"_")
+		}
+	}
+	return "", ""
+}
+
 // ScopeError represents a token scope validation error with detailed status
 type ScopeError struct {
 	Missing []string         // List of missing required scopes
@@ -35,3 +60,9 @@ type ScopeError struct {
 func (e *ScopeError) Error() string {
 	return fmt.Sprintf("missing required scopes: %s", strings.Join(e.Missing, ", "))
 }
+
+// Unwrap returns ErrScopeMissing so callers can match any scope error with
+// errors.Is and still use errors.As when they need the per-scope status
+func (e *ScopeError) Unwrap() error {
+	return ErrScopeMissing
+}
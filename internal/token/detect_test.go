@@ -1,6 +1,10 @@
 package token
 
-import "testing"
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
 
 func TestDetectProvider(t *testing.T) {
 	tests := []struct {
@@ -44,3 +48,23 @@ func TestDetectProvider(t *testing.T) {
 		})
 	}
 }
+
+func TestScopeErrorIs(t *testing.T) {
+	scopeErr := &ScopeError{
+		Missing: []string{"workflow"},
+		Status:  map[string]bool{"repo": true, "workflow": false},
+	}
+	err := fmt.Errorf("invalid token scope: %w", scopeErr)
+
+	if !errors.Is(err, ErrScopeMissing) {
+		t.Errorf("errors.Is(%v, ErrScopeMissing) = false, want true", err)
+	}
+
+	var got *ScopeError
+	if !errors.As(err, &got) {
+		t.Fatalf("errors.As(%v, *ScopeError) = false, want true", err)
+	}
+	if got.Status["repo"] != true || got.Status["workflow"] != false {
+		t.Errorf("ScopeError.Status = %v, want repo present and workflow missing", got.Status)
+	}
+}
@@ -23,6 +23,10 @@ const (
 //   export GIT_TOKEN_GITHUB='{"Value":"ghp_abc...","Scope":"repo,workflow"}'
 //   export GIT_TOKEN_GITLAB='{"Value":"glpat_xyz...","Scope":"api"}'
 //
+// Multiple accounts per provider use named keys (see Key):
+//   export GIT_TOKEN_GITHUB_WORK='{"Value":"ghp_work..."}'
+//   export GIT_TOKEN_GITHUB_PERSONAL='{"Value":"ghp_personal..."}'
+//
 // In Docker:
 //   docker run -e GIT_TOKEN_GITHUB='{"Value":"..."}'
 //
@@ -109,6 +113,23 @@ func (e *EnvStorage) List(ctx context.Context) ([]string, error) {
 	return keys, nil
 }
 
+// ListAccounts returns the account names stored for a provider
+// The provider's default token is reported as an empty account name
+func (e *EnvStorage) ListAccounts(ctx context.Context, provider Provider) ([]string, error) {
+	keys, err := e.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts []string
+	for _, key := range keys {
+		if p, account := ParseKey(key); p == provider {
+			accounts = append(accounts, account)
+		}
+	}
+	return accounts, nil
+}
+
 // FormatEnvKey converts a token key into an environment variable name
 // This is exported to allow users to predict and verify environment variable names
 func (e *EnvStorage) FormatEnvKey(key string) string {
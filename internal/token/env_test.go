@@ -210,3 +210,79 @@ func TestEnvStorage(t *testing.T) {
 		}
 	})
 }
+
+func TestEnvStorage_NamedAccounts(t *testing.T) {
+	storage := NewEnvStorage()
+	ctx := context.Background()
+
+	workKey := Key(ProviderGitHub, "work")
+	personalKey := Key(ProviderGitHub, "personal")
+	defer storage.Delete(ctx, workKey)
+	defer storage.Delete(ctx, personalKey)
+
+	if workKey != "GITHUB_WORK" {
+		t.Errorf("Key() = %q, want %q", workKey, "GITHUB_WORK")
+	}
+	if env := storage.FormatEnvKey(personalKey); env != "GIT_TOKEN_GITHUB_PERSONAL" {
+		t.Errorf("FormatEnvKey() = %q, want %q", env, "GIT_TOKEN_GITHUB_PERSONAL")
+	}
+
+	if err := storage.Store(ctx, workKey, Token{Value: "ghp_work"}); err != nil {
+		t.Fatalf("Failed to store work token: %v", err)
+	}
+	if err := storage.Store(ctx, personalKey, Token{Value: "ghp_personal"}); err != nil {
+		t.Fatalf("Failed to store personal token: %v", err)
+	}
+
+	work, err := storage.Retrieve(ctx, workKey)
+	if err != nil {
+		t.Fatalf("Failed to retrieve work token: %v", err)
+	}
+	if work.Value != "ghp_work" {
+		t.Errorf("work token = %q, want %q", work.Value, "ghp_work")
+	}
+
+	personal, err := storage.Retrieve(ctx, personalKey)
+	if err != nil {
+		t.Fatalf("Failed to retrieve personal token: %v", err)
+	}
+	if personal.Value != "ghp_personal" {
+		t.Errorf("personal token = %q, want %q", personal.Value, "ghp_personal")
+	}
+
+	accounts, err := storage.ListAccounts(ctx, ProviderGitHub)
+	if err != nil {
+		t.Fatalf("Failed to list accounts: %v", err)
+	}
+	found := make(map[string]bool)
+	for _, account := range accounts {
+		found[account] = true
+	}
+	if !found["WORK"] || !found["PERSONAL"] {
+		t.Errorf("ListAccounts() = %v, want WORK and PERSONAL", accounts)
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		key          string
+		wantProvider Provider
+		wantAccount  string
+	}{
+		{key: "GITHUB", wantProvider: ProviderGitHub, wantAccount: ""},
+		{key: "GITHUB_WORK", wantProvider: ProviderGitHub, wantAccount: "WORK"},
+		{key: "gitlab_personal", wantProvider: ProviderGitLab, wantAccount: "PERSONAL"},
+		{key: "GITHUBX", wantProvider: "", wantAccount: ""},
+		{key: "OTHER", wantProvider: "", wantAccount: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			provider, account := ParseKey(tt.key)
+			if provider != tt.wantProvider || account != tt.wantAccount {
+				t.Errorf("ParseKey(%q) = (%q, %q), want (%q, %q)",
+					tt.key, provider, account, tt.wantProvider, tt.wantAccount)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+//go:build keyring
+
+package token
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/zalando/go-keyring"
+)
+
+const (
+	// DefaultKeyringService is the service name tokens are stored under
+	DefaultKeyringService = "go-gittools"
+
+	// keyringIndexKey holds the list of stored keys, since OS keyrings
+	// do not support enumerating entries
+	keyringIndexKey = "__index__"
+)
+
+// KeyringStorage implements Storage using the operating system keyring
+// (macOS Keychain, Windows Credential Manager, or Secret Service on Linux).
+// It is only built with the keyring build tag, keeping headless and
+// container builds free of system keyring dependencies:
+//
+//	go build -tags keyring ./...
+//
+// If the OS keyring cannot be reached, operations return ErrStorageUnavailable.
+type KeyringStorage struct {
+	service string
+	mu      sync.Mutex // Serializes index updates
+}
+
+// NewKeyringStorage creates a new keyring-backed token storage
+// An empty service name uses DefaultKeyringService
+func NewKeyringStorage(service string) *KeyringStorage {
+	if service == "" {
+		service = DefaultKeyringService
+	}
+	return &KeyringStorage{service: service}
+}
+
+// Store implements Storage.Store
+func (k *KeyringStorage) Store(_ context.Context, key string, token Token) error {
+	if !IsValid(token) {
+		return ErrTokenInvalid
+	}
+
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to marshal token: %w", err)
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if err := keyring.Set(k.service, key, string(data)); err != nil {
+		return fmt.Errorf("%w: %v", ErrStorageUnavailable, err)
+	}
+
+	return k.updateIndex(func(keys map[string]bool) { keys[key] = true })
+}
+
+// Retrieve implements Storage.Retrieve
+func (k *KeyringStorage) Retrieve(_ context.Context, key string) (Token, error) {
+	data, err := keyring.Get(k.service, key)
+	if err != nil {
+		if errors.Is(err, keyring.ErrNotFound) {
+			return Token{}, ErrTokenNotFound
+		}
+		return Token{}, fmt.Errorf("%w: %v", ErrStorageUnavailable, err)
+	}
+
+	var token Token
+	if err := json.Unmarshal([]byte(data), &token); err != nil {
+		return Token{}, fmt.Errorf("failed to unmarshal token: %w", err)
+	}
+
+	if !IsValid(token) {
+		return Token{}, ErrTokenInvalid
+	}
+
+	if !token.ExpiresAt.IsZero() && time.Now().After(token.ExpiresAt) {
+		return Token{}, ErrTokenExpired
+	}
+
+	return token, nil
+}
+
+// Delete implements Storage.Delete
+func (k *KeyringStorage) Delete(_ context.Context, key string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if err := keyring.Delete(k.service, key); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return fmt.Errorf("%w: %v", ErrStorageUnavailable, err)
+	}
+
+	return k.updateIndex(func(keys map[string]bool) { delete(keys, key) })
+}
+
+// List implements Storage.List
+func (k *KeyringStorage) List(_ context.Context) ([]string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	keys, err := k.readIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]string, 0, len(keys))
+	for key := range keys {
+		list = append(list, key)
+	}
+	sort.Strings(list)
+	return list, nil
+}
+
+// Close implements Storage.Close
+func (k *KeyringStorage) Close(_ context.Context) error {
+	// Nothing to clean up; tokens persist in the OS keyring
+	return nil
+}
+
+// readIndex loads the set of stored keys
+// The caller must hold k.mu
+func (k *KeyringStorage) readIndex() (map[string]bool, error) {
+	keys := make(map[string]bool)
+
+	data, err := keyring.Get(k.service, keyringIndexKey)
+	if err != nil {
+		if errors.Is(err, keyring.ErrNotFound) {
+			return keys, nil
+		}
+		return nil, fmt.Errorf("%w: %v", ErrStorageUnavailable, err)
+	}
+
+	var list []string
+	if err := json.Unmarshal([]byte(data), &list); err != nil {
+		return nil, fmt.Errorf("failed to parse keyring index: %w", err)
+	}
+	for _, key := range list {
+		keys[key] = true
+	}
+	return keys, nil
+}
+
+// updateIndex applies fn to the set of stored keys and saves the result
+// The caller must hold k.mu
+func (k *KeyringStorage) updateIndex(fn func(keys map[string]bool)) error {
+	keys, err := k.readIndex()
+	if err != nil {
+		return err
+	}
+	fn(keys)
+
+	list := make([]string, 0, len(keys))
+	for key := range keys {
+		list = append(list, key)
+	}
+	sort.Strings(list)
+
+	data, err := json.Marshal(list)
+	if err != nil {
+		return fmt.Errorf("failed to marshal keyring index: %w", err)
+	}
+	if err := keyring.Set(k.service, keyringIndexKey, string(data)); err != nil {
+		return fmt.Errorf("%w: %v", ErrStorageUnavailable, err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+//go:build keyring
+
+package token
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zalando/go-keyring"
+)
+
+func TestKeyringStorage(t *testing.T) {
+	keyring.MockInit()
+
+	storage := NewKeyringStorage("go-gittools-test")
+	ctx := context.Background()
+	defer storage.Close(ctx)
+
+	t.Run("Store and Retrieve", func(t *testing.T) {
+		token, err := NewToken("keyring-token", time.Now().Add(time.Hour), "repo")
+		if err != nil {
+			t.Fatalf("Failed to create token: %v", err)
+		}
+
+		if err := storage.Store(ctx, "GITHUB", *token); err != nil {
+			t.Fatalf("Failed to store token: %v", err)
+		}
+
+		retrieved, err := storage.Retrieve(ctx, "GITHUB")
+		if err != nil {
+			t.Fatalf("Failed to retrieve token: %v", err)
+		}
+		if retrieved.Value != token.Value {
+			t.Errorf("Retrieved token value mismatch: got %s, want %s", retrieved.Value, token.Value)
+		}
+
+		keys, err := storage.List(ctx)
+		if err != nil {
+			t.Fatalf("Failed to list tokens: %v", err)
+		}
+		if len(keys) != 1 || keys[0] != "GITHUB" {
+			t.Errorf("List() = %v, want [GITHUB]", keys)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		if err := storage.Delete(ctx, "GITHUB"); err != nil {
+			t.Fatalf("Failed to delete token: %v", err)
+		}
+
+		if _, err := storage.Retrieve(ctx, "GITHUB"); !errors.Is(err, ErrTokenNotFound) {
+			t.Errorf("Retrieve() after delete error = %v, want ErrTokenNotFound", err)
+		}
+
+		keys, err := storage.List(ctx)
+		if err != nil {
+			t.Fatalf("Failed to list tokens: %v", err)
+		}
+		if len(keys) != 0 {
+			t.Errorf("List() after delete = %v, want empty", keys)
+		}
+	})
+
+	t.Run("Keyring unavailable", func(t *testing.T) {
+		keyring.MockInitWithError(errors.New("no keyring daemon"))
+		defer keyring.MockInit()
+
+		err := storage.Store(ctx, "GITHUB", Token{Value: "keyring-token"})
+		if !errors.Is(err, ErrStorageUnavailable) {
+			t.Errorf("Store() error = %v, want ErrStorageUnavailable", err)
+		}
+
+		if _, err := storage.Retrieve(ctx, "GITHUB"); !errors.Is(err, ErrStorageUnavailable) {
+			t.Errorf("Retrieve() error = %v, want ErrStorageUnavailable", err)
+		}
+	})
+}
+
+func TestCompositeStorageEnvThenKeyring(t *testing.T) {
+	keyring.MockInit()
+	ctx := context.Background()
+	env := NewEnvStorage()
+	t.Setenv(env.FormatEnvKey("github"), "")
+	kr := NewKeyringStorage("")
+
+	c := NewCompositeStorage(env, kr)
+	if err := c.Store(ctx, "github", Token{Value: "ghp_stored"}); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+	if _, err := env.Retrieve(ctx, "github"); !errors.Is(err, ErrTokenNotFound) {
+		t.Errorf("Store() wrote to the process environment, Retrieve() error = %v", err)
+	}
+	if got, err := kr.Retrieve(ctx, "github"); err != nil || got.Value != "ghp_stored" {
+		t.Errorf("keyring has %q, %v; want stored token", got.Value, err)
+	}
+	if got, err := c.Retrieve(ctx, "github"); err != nil || got.Value != "ghp_stored" {
+		t.Errorf("Retrieve() = %q, %v; want token from keyring", got.Value, err)
+	}
+}
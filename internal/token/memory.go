@@ -52,6 +52,27 @@ func (m *MemoryStorage) Retrieve(_ context.Context, key string) (Token, error) {
 	return token, nil
 }
 
+// CompareAndSwap implements CompareAndSwapper
+func (m *MemoryStorage) CompareAndSwap(_ context.Context, key string, old, new Token) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if new.Value == "" {
+		return false, ErrTokenInvalid
+	}
+
+	current, exists := m.tokens[key]
+	if !exists {
+		return false, ErrTokenNotFound
+	}
+	if !Equal(current, old) {
+		return false, nil
+	}
+
+	m.tokens[key] = new
+	return true, nil
+}
+
 // Delete implements Storage.Delete
 func (m *MemoryStorage) Delete(_ context.Context, key string) error {
 	m.mu.Lock()
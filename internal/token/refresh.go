@@ -2,7 +2,11 @@ package token
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math/rand"
+	"os"
 	"sync"
 	"time"
 )
@@ -17,6 +21,11 @@ type RefreshConfig struct {
 	// RefreshInterval is how often to check token health
 	RefreshInterval time.Duration
 
+	// RefreshJitter is the maximum random offset applied to each monitor's
+	// first check, so monitors started together don't fire in lockstep
+	// Zero defaults to 10% of RefreshInterval; a negative value disables jitter
+	RefreshJitter time.Duration
+
 	// RetryAttempts is the number of times to retry refresh on failure
 	RetryAttempts int
 
@@ -28,6 +37,25 @@ type RefreshConfig struct {
 
 	// ProgressCallback is called to report progress during refresh attempts
 	ProgressCallback func(message string)
+
+	// StatePath is the file used to persist last-refresh timestamps
+	// Empty disables persistence
+	StatePath string
+
+	// Logger receives health-check and refresh failures from monitors
+	// Nil defaults to printing to stdout
+	Logger func(level, msg string, fields map[string]any)
+}
+
+// Log levels passed to RefreshConfig.Logger
+const (
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+// defaultLogger prints monitor events to stdout
+func defaultLogger(level, msg string, fields map[string]any) {
+	fmt.Printf("%s for %v: %v\n", msg, fields["key"], fields["error"])
 }
 
 // DefaultRefreshConfig provides sensible defaults for token refresh
@@ -40,6 +68,7 @@ var DefaultRefreshConfig = RefreshConfig{
 	ProgressCallback: func(message string) {
 		fmt.Print("\r" + message)
 	},
+	Logger: defaultLogger,
 }
 
 // RefreshHandler defines the interface for token refresh operations
@@ -51,20 +80,29 @@ type RefreshHandler interface {
 
 // TokenManager handles token health monitoring and automatic refresh
 type TokenManager struct {
-	storage   Storage
-	handler   RefreshHandler
-	config    RefreshConfig
-	mu        sync.RWMutex
-	monitors  map[string]context.CancelFunc
+	storage     Storage
+	handler     RefreshHandler
+	config      RefreshConfig
+	mu          sync.RWMutex
+	monitors    map[string]context.CancelFunc
+	lastRefresh map[string]time.Time
+	randInt63n  func(n int64) int64 // Randomness source for jitter, replaceable in tests
+}
+
+// refreshState is the on-disk representation of TokenManager refresh history
+type refreshState struct {
+	LastRefresh map[string]time.Time `json:"last_refresh"`
 }
 
 // NewTokenManager creates a new TokenManager with the given configuration
 func NewTokenManager(storage Storage, handler RefreshHandler, config RefreshConfig) *TokenManager {
 	return &TokenManager{
-		storage:  storage,
-		handler:  handler,
-		config:   config,
-		monitors: make(map[string]context.CancelFunc),
+		storage:     storage,
+		handler:     handler,
+		config:      config,
+		monitors:    make(map[string]context.CancelFunc),
+		lastRefresh: make(map[string]time.Time),
+		randInt63n:  rand.Int63n,
 	}
 }
 
@@ -84,11 +122,8 @@ func (tm *TokenManager) CheckHealth(ctx context.Context, key string) error {
 	}
 
 	// Check remaining validity time
-	if !token.ExpiresAt.IsZero() {
-		remainingTime := time.Until(token.ExpiresAt)
-		if remainingTime < tm.config.MinValidTime {
-			return ErrTokenExpired
-		}
+	if !token.IsHealthy(tm.config.MinValidTime) {
+		return ErrTokenExpired
 	}
 
 	return nil
@@ -148,14 +183,114 @@ func (tm *TokenManager) RefreshToken(ctx context.Context, key string) error {
 		return fmt.Errorf("refreshed token is invalid: %w", ErrTokenInvalid)
 	}
 
-	// Store the new token with timeout context
-	if err := tm.storage.Store(refreshCtx, key, newToken); err != nil {
+	// Store the new token only if no concurrent refresh replaced it first.
+	// Losing the race is not an error since the winner's token is already
+	// stored, and the winner records the refresh.
+	swapped, err := CompareAndSwap(refreshCtx, tm.storage, key, currentToken, newToken)
+	if err != nil {
 		return fmt.Errorf("failed to store refreshed token: %w", err)
 	}
+	if !swapped {
+		return nil
+	}
+
+	// The refresh has succeeded once the token is stored; failing to
+	// persist its timestamp only loses history across restarts
+	if err := tm.recordRefresh(key, time.Now()); err != nil {
+		tm.log(LogLevelWarn, "Failed to persist refresh state", map[string]any{"key": key, "error": err})
+	}
 
 	return nil
 }
 
+// LastRefresh returns when a token was last successfully refreshed
+// The second return value is false if no refresh has been recorded
+func (tm *TokenManager) LastRefresh(key string) (time.Time, bool) {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	t, ok := tm.lastRefresh[key]
+	return t, ok
+}
+
+// StartMonitoringFromStorage resumes monitoring for every token in storage.
+// If StatePath is configured, previously persisted refresh timestamps are
+// loaded first so that a restarted process keeps its refresh history.
+func (tm *TokenManager) StartMonitoringFromStorage(ctx context.Context) error {
+	if err := tm.loadState(); err != nil {
+		return fmt.Errorf("failed to load refresh state: %w", err)
+	}
+
+	keys, err := tm.storage.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list tokens: %w", err)
+	}
+
+	for _, key := range keys {
+		if err := tm.StartMonitoring(ctx, key); err != nil {
+			return fmt.Errorf("failed to start monitoring %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// recordRefresh stores the refresh time for a key and persists the state
+func (tm *TokenManager) recordRefresh(key string, at time.Time) error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	tm.lastRefresh[key] = at
+	return tm.saveStateLocked()
+}
+
+// loadState reads persisted refresh timestamps from StatePath
+// A missing state file is not an error
+func (tm *TokenManager) loadState() error {
+	if tm.config.StatePath == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(tm.config.StatePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	var state refreshState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to parse state file: %w", err)
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	for key, at := range state.LastRefresh {
+		// Keep newer in-memory timestamps over persisted ones
+		if current, ok := tm.lastRefresh[key]; !ok || at.After(current) {
+			tm.lastRefresh[key] = at
+		}
+	}
+
+	return nil
+}
+
+// saveStateLocked writes refresh timestamps to StatePath
+// The caller must hold tm.mu
+func (tm *TokenManager) saveStateLocked() error {
+	if tm.config.StatePath == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(refreshState{LastRefresh: tm.lastRefresh}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	return os.WriteFile(tm.config.StatePath, data, 0600)
+}
+
 // StartMonitoring begins monitoring a token's health
 func (tm *TokenManager) StartMonitoring(ctx context.Context, key string) error {
 	tm.mu.Lock()
@@ -171,7 +306,7 @@ func (tm *TokenManager) StartMonitoring(ctx context.Context, key string) error {
 	tm.monitors[key] = cancel
 
 	// Start monitoring in a goroutine
-	go tm.monitor(monitorCtx, key)
+	go tm.monitor(monitorCtx, key, tm.jitter())
 
 	return nil
 }
@@ -187,8 +322,38 @@ func (tm *TokenManager) StopMonitoring(key string) {
 	}
 }
 
+// log sends an event to the configured logger
+func (tm *TokenManager) log(level, msg string, fields map[string]any) {
+	logger := tm.config.Logger
+	if logger == nil {
+		logger = defaultLogger
+	}
+	logger(level, msg, fields)
+}
+
+// jitter returns a random offset in [0, RefreshJitter)
+func (tm *TokenManager) jitter() time.Duration {
+	maxJitter := tm.config.RefreshJitter
+	if maxJitter == 0 {
+		maxJitter = tm.config.RefreshInterval / 10
+	}
+	if maxJitter <= 0 {
+		return 0
+	}
+	return time.Duration(tm.randInt63n(int64(maxJitter)))
+}
+
 // monitor is the internal monitoring loop for a token
-func (tm *TokenManager) monitor(ctx context.Context, key string) {
+// The loop waits for offset before starting its ticker to spread out checks
+func (tm *TokenManager) monitor(ctx context.Context, key string, offset time.Duration) {
+	if offset > 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(offset):
+		}
+	}
+
 	ticker := time.NewTicker(tm.config.RefreshInterval)
 	defer ticker.Stop()
 
@@ -204,12 +369,12 @@ func (tm *TokenManager) monitor(ctx context.Context, key string) {
 			if err := tm.CheckHealth(checkCtx, key); err != nil {
 				// Log the health check error but continue to refresh attempt
 				if err != ErrTokenExpired {
-					fmt.Printf("Token health check failed for %s: %v\n", key, err)
+					tm.log(LogLevelWarn, "Token health check failed", map[string]any{"key": key, "error": err})
 				}
 				
 				// Attempt to refresh the token if it's unhealthy
 				if err := tm.RefreshToken(checkCtx, key); err != nil {
-					fmt.Printf("Token refresh failed for %s: %v\n", key, err)
+					tm.log(LogLevelError, "Token refresh failed", map[string]any{"key": key, "error": err})
 				}
 			}
 			
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -319,3 +321,446 @@ func TestTokenManager_ConcurrentMonitoring(t *testing.T) {
 		t.Error("No tokens were refreshed during monitoring")
 	}
 }
+
+// swapCountingStorage counts successful compare-and-swap operations
+type swapCountingStorage struct {
+	*MemoryStorage
+	swaps int32
+}
+
+func (s *swapCountingStorage) CompareAndSwap(ctx context.Context, key string, old, new Token) (bool, error) {
+	swapped, err := s.MemoryStorage.CompareAndSwap(ctx, key, old, new)
+	if swapped {
+		atomic.AddInt32(&s.swaps, 1)
+	}
+	return swapped, err
+}
+
+func TestTokenManager_ConcurrentRefresh(t *testing.T) {
+	storage := &swapCountingStorage{MemoryStorage: NewMemoryStorage()}
+	ctx := context.Background()
+	defer storage.Close(ctx)
+
+	const numRefreshers = 2
+	var started sync.WaitGroup
+	started.Add(numRefreshers)
+	var calls int32
+
+	// Block every refresher until all of them have read the current token
+	handler := &mockRefreshHandler{
+		refreshFunc: func(ctx context.Context, current Token) (Token, error) {
+			id := atomic.AddInt32(&calls, 1)
+			started.Done()
+			started.Wait()
+			token, err := NewToken(fmt.Sprintf("refreshed-token-%d", id), time.Now().Add(48*time.Hour), "repo")
+			if err != nil {
+				return Token{}, err
+			}
+			return *token, nil
+		},
+	}
+
+	config := RefreshConfig{
+		MinValidTime:   24 * time.Hour,
+		RetryAttempts:  0,
+		RetryDelay:     time.Millisecond,
+		RefreshTimeout: time.Second,
+	}
+	manager := NewTokenManager(storage, handler, config)
+
+	key := "raced-token"
+	original, err := NewToken("original-token", time.Now().Add(1*time.Hour), "repo")
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+	if err := storage.Store(ctx, key, *original); err != nil {
+		t.Fatalf("Failed to store token: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errChan := make(chan error, numRefreshers)
+	for i := 0; i < numRefreshers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := manager.RefreshToken(ctx, key); err != nil {
+				errChan <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errChan)
+
+	for err := range errChan {
+		t.Errorf("RefreshToken() unexpected error: %v", err)
+	}
+
+	if swaps := atomic.LoadInt32(&storage.swaps); swaps != 1 {
+		t.Errorf("expected exactly 1 successful store, got %d", swaps)
+	}
+
+	final, err := storage.Retrieve(ctx, key)
+	if err != nil {
+		t.Fatalf("Failed to retrieve final token: %v", err)
+	}
+	if final.Value == original.Value {
+		t.Error("Token was not updated by either refresher")
+	}
+}
+
+func TestCompareAndSwap_Emulated(t *testing.T) {
+	storage := NewEnvStorage()
+	ctx := context.Background()
+	key := "CAS_TEST"
+	defer storage.Delete(ctx, key)
+
+	old := Token{Value: "old-token"}
+	if err := storage.Store(ctx, key, old); err != nil {
+		t.Fatalf("Failed to store token: %v", err)
+	}
+
+	// A stale expected value must not overwrite the stored token
+	swapped, err := CompareAndSwap(ctx, storage, key, Token{Value: "stale-token"}, Token{Value: "new-token"})
+	if err != nil || swapped {
+		t.Fatalf("CompareAndSwap() with stale token = %v, %v; want false, nil", swapped, err)
+	}
+
+	swapped, err = CompareAndSwap(ctx, storage, key, old, Token{Value: "new-token"})
+	if err != nil || !swapped {
+		t.Fatalf("CompareAndSwap() with current token = %v, %v; want true, nil", swapped, err)
+	}
+
+	current, err := storage.Retrieve(ctx, key)
+	if err != nil {
+		t.Fatalf("Failed to retrieve token: %v", err)
+	}
+	if current.Value != "new-token" {
+		t.Errorf("stored token = %q, want %q", current.Value, "new-token")
+	}
+}
+
+func TestTokenManager_StartMonitoringFromStorage(t *testing.T) {
+	storage := NewMemoryStorage()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer storage.Close(ctx)
+
+	keys := []string{"github", "gitlab", "github-work"}
+	for _, key := range keys {
+		if err := storage.Store(ctx, key, Token{Value: "token-" + key}); err != nil {
+			t.Fatalf("Failed to store token: %v", err)
+		}
+	}
+
+	config := RefreshConfig{
+		MinValidTime:    24 * time.Hour,
+		RefreshInterval: time.Hour,
+		RefreshTimeout:  time.Second,
+	}
+	manager := NewTokenManager(storage, &mockRefreshHandler{}, config)
+
+	if err := manager.StartMonitoringFromStorage(ctx); err != nil {
+		t.Fatalf("StartMonitoringFromStorage() unexpected error: %v", err)
+	}
+
+	manager.mu.RLock()
+	defer manager.mu.RUnlock()
+	if len(manager.monitors) != len(keys) {
+		t.Errorf("expected %d monitors, got %d", len(keys), len(manager.monitors))
+	}
+	for _, key := range keys {
+		if _, ok := manager.monitors[key]; !ok {
+			t.Errorf("expected monitor for key %q", key)
+		}
+	}
+}
+
+func TestTokenManager_PersistedRefreshState(t *testing.T) {
+	storage := NewMemoryStorage()
+	ctx := context.Background()
+	defer storage.Close(ctx)
+
+	handler := &mockRefreshHandler{
+		refreshFunc: func(ctx context.Context, current Token) (Token, error) {
+			return Token{Value: "refreshed-token"}, nil
+		},
+	}
+
+	config := RefreshConfig{
+		MinValidTime:    24 * time.Hour,
+		RefreshInterval: time.Hour,
+		RetryDelay:      time.Millisecond,
+		RefreshTimeout:  time.Second,
+		StatePath:       filepath.Join(t.TempDir(), "refresh-state.json"),
+	}
+
+	key := "persisted-token"
+	if err := storage.Store(ctx, key, Token{Value: "original-token"}); err != nil {
+		t.Fatalf("Failed to store token: %v", err)
+	}
+
+	manager := NewTokenManager(storage, handler, config)
+	if err := manager.RefreshToken(ctx, key); err != nil {
+		t.Fatalf("RefreshToken() unexpected error: %v", err)
+	}
+	refreshedAt, ok := manager.LastRefresh(key)
+	if !ok {
+		t.Fatal("LastRefresh() returned no timestamp after refresh")
+	}
+
+	// A new manager simulates a restarted process
+	restarted := NewTokenManager(storage, handler, config)
+	monitorCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if err := restarted.StartMonitoringFromStorage(monitorCtx); err != nil {
+		t.Fatalf("StartMonitoringFromStorage() unexpected error: %v", err)
+	}
+
+	restored, ok := restarted.LastRefresh(key)
+	if !ok {
+		t.Fatal("LastRefresh() returned no timestamp after restart")
+	}
+	if !restored.Equal(refreshedAt) {
+		t.Errorf("restored timestamp = %v, want %v", restored, refreshedAt)
+	}
+}
+
+func TestTokenManager_RefreshSucceedsWhenStatePersistFails(t *testing.T) {
+	storage := NewMemoryStorage()
+	ctx := context.Background()
+	defer storage.Close(ctx)
+
+	var warnings []string
+	handler := &mockRefreshHandler{
+		refreshFunc: func(ctx context.Context, current Token) (Token, error) {
+			return Token{Value: "refreshed-token"}, nil
+		},
+	}
+	config := RefreshConfig{
+		MinValidTime:   24 * time.Hour,
+		RetryDelay:     time.Millisecond,
+		RefreshTimeout: time.Second,
+		// The state directory does not exist, so writing the state fails
+		StatePath: filepath.Join(t.TempDir(), "missing", "refresh-state.json"),
+		Logger: func(level, msg string, fields map[string]any) {
+			warnings = append(warnings, level+": "+msg)
+		},
+	}
+
+	key := "stored-token"
+	if err := storage.Store(ctx, key, Token{Value: "original-token"}); err != nil {
+		t.Fatalf("Failed to store token: %v", err)
+	}
+
+	manager := NewTokenManager(storage, handler, config)
+	if err := manager.RefreshToken(ctx, key); err != nil {
+		t.Fatalf("RefreshToken() error = %v, want nil once the token is stored", err)
+	}
+	if stored, _ := storage.Retrieve(ctx, key); stored.Value != "refreshed-token" {
+		t.Errorf("stored token = %q, want %q", stored.Value, "refreshed-token")
+	}
+	if _, ok := manager.LastRefresh(key); !ok {
+		t.Error("LastRefresh() returned no timestamp after refresh")
+	}
+	if len(warnings) != 1 || warnings[0] != LogLevelWarn+": Failed to persist refresh state" {
+		t.Errorf("logged %q, want one persistence warning", warnings)
+	}
+}
+
+// racingStorage replaces the stored token just before each compare-and-swap,
+// as a concurrent refresher would
+type racingStorage struct {
+	*MemoryStorage
+}
+
+func (s *racingStorage) CompareAndSwap(ctx context.Context, key string, old, new Token) (bool, error) {
+	if err := s.MemoryStorage.Store(ctx, key, Token{Value: "winner-token"}); err != nil {
+		return false, err
+	}
+	return s.MemoryStorage.CompareAndSwap(ctx, key, old, new)
+}
+
+func TestTokenManager_LostRaceIsNotRecorded(t *testing.T) {
+	storage := &racingStorage{MemoryStorage: NewMemoryStorage()}
+	ctx := context.Background()
+	defer storage.Close(ctx)
+
+	handler := &mockRefreshHandler{
+		refreshFunc: func(ctx context.Context, current Token) (Token, error) {
+			return Token{Value: "loser-token"}, nil
+		},
+	}
+	statePath := filepath.Join(t.TempDir(), "refresh-state.json")
+	config := RefreshConfig{
+		MinValidTime:   24 * time.Hour,
+		RetryDelay:     time.Millisecond,
+		RefreshTimeout: time.Second,
+		StatePath:      statePath,
+	}
+
+	key := "raced-token"
+	if err := storage.Store(ctx, key, Token{Value: "original-token"}); err != nil {
+		t.Fatalf("Failed to store token: %v", err)
+	}
+
+	manager := NewTokenManager(storage, handler, config)
+	if err := manager.RefreshToken(ctx, key); err != nil {
+		t.Fatalf("RefreshToken() unexpected error: %v", err)
+	}
+	if stored, _ := storage.Retrieve(ctx, key); stored.Value != "winner-token" {
+		t.Errorf("stored token = %q, want the winner's token", stored.Value)
+	}
+	if _, ok := manager.LastRefresh(key); ok {
+		t.Error("LastRefresh() recorded a refresh that lost the race")
+	}
+	if _, err := os.Stat(statePath); !os.IsNotExist(err) {
+		t.Errorf("state file written after losing the race (stat error %v)", err)
+	}
+}
+
+// checkRecordingStorage records when each key is first retrieved
+type checkRecordingStorage struct {
+	*MemoryStorage
+	mu     sync.Mutex
+	checks map[string]time.Time
+}
+
+func (s *checkRecordingStorage) Retrieve(ctx context.Context, key string) (Token, error) {
+	s.mu.Lock()
+	if _, ok := s.checks[key]; !ok {
+		s.checks[key] = time.Now()
+	}
+	s.mu.Unlock()
+	return s.MemoryStorage.Retrieve(ctx, key)
+}
+
+func TestTokenManager_RefreshJitter(t *testing.T) {
+	storage := &checkRecordingStorage{
+		MemoryStorage: NewMemoryStorage(),
+		checks:        make(map[string]time.Time),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	defer storage.Close(ctx)
+
+	config := RefreshConfig{
+		MinValidTime:    time.Millisecond,
+		RefreshInterval: 20 * time.Millisecond,
+		RefreshJitter:   60 * time.Millisecond,
+		RefreshTimeout:  time.Second,
+	}
+	manager := NewTokenManager(storage, &mockRefreshHandler{}, config)
+
+	// Injected randomness: the first monitor gets no offset, the second 40ms
+	offsets := []int64{0, int64(40 * time.Millisecond)}
+	var calls int
+	manager.randInt63n = func(n int64) int64 {
+		if n != int64(config.RefreshJitter) {
+			t.Errorf("jitter bound = %v, want %v", time.Duration(n), config.RefreshJitter)
+		}
+		offset := offsets[calls%len(offsets)]
+		calls++
+		return offset
+	}
+
+	keys := []string{"first", "second"}
+	for _, key := range keys {
+		if err := storage.Store(ctx, key, Token{Value: "token-" + key}); err != nil {
+			t.Fatalf("Failed to store token: %v", err)
+		}
+		if err := manager.StartMonitoring(ctx, key); err != nil {
+			t.Fatalf("StartMonitoring() unexpected error: %v", err)
+		}
+	}
+
+	<-ctx.Done()
+	for _, key := range keys {
+		manager.StopMonitoring(key)
+	}
+
+	storage.mu.Lock()
+	defer storage.mu.Unlock()
+	first, ok1 := storage.checks["first"]
+	second, ok2 := storage.checks["second"]
+	if !ok1 || !ok2 {
+		t.Fatalf("expected both monitors to fire, got %v", storage.checks)
+	}
+	if gap := second.Sub(first); gap < 20*time.Millisecond {
+		t.Errorf("monitors fired %v apart, expected jitter to spread them by ~40ms", gap)
+	}
+}
+
+func TestTokenManager_DefaultJitter(t *testing.T) {
+	manager := NewTokenManager(NewMemoryStorage(), &mockRefreshHandler{}, RefreshConfig{
+		RefreshInterval: time.Hour,
+	})
+
+	var bound int64
+	manager.randInt63n = func(n int64) int64 {
+		bound = n
+		return 0
+	}
+	manager.jitter()
+
+	if want := int64(6 * time.Minute); bound != want {
+		t.Errorf("default jitter bound = %v, want %v", time.Duration(bound), time.Duration(want))
+	}
+}
+
+func TestTokenManager_MonitorLogger(t *testing.T) {
+	storage := NewMemoryStorage()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer storage.Close(ctx)
+
+	type logEvent struct {
+		level  string
+		msg    string
+		fields map[string]any
+	}
+	events := make(chan logEvent, 10)
+
+	handler := &mockRefreshHandler{
+		refreshFunc: func(ctx context.Context, current Token) (Token, error) {
+			return Token{}, errors.New("refresh failed")
+		},
+	}
+	config := RefreshConfig{
+		MinValidTime:    24 * time.Hour,
+		RefreshInterval: 10 * time.Millisecond,
+		RefreshJitter:   -1,
+		RetryDelay:      time.Millisecond,
+		RefreshTimeout:  time.Second,
+		Logger: func(level, msg string, fields map[string]any) {
+			events <- logEvent{level: level, msg: msg, fields: fields}
+		},
+	}
+	manager := NewTokenManager(storage, handler, config)
+
+	key := "failing-token"
+	if err := storage.Store(ctx, key, Token{Value: "expiring", ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatalf("Failed to store token: %v", err)
+	}
+	if err := manager.StartMonitoring(ctx, key); err != nil {
+		t.Fatalf("StartMonitoring() unexpected error: %v", err)
+	}
+	defer manager.StopMonitoring(key)
+
+	select {
+	case ev := <-events:
+		if ev.level != LogLevelError {
+			t.Errorf("log level = %q, want %q", ev.level, LogLevelError)
+		}
+		if ev.msg != "Token refresh failed" {
+			t.Errorf("log message = %q, want %q", ev.msg, "Token refresh failed")
+		}
+		if ev.fields["key"] != key {
+			t.Errorf("log key field = %v, want %q", ev.fields["key"], key)
+		}
+		if err, ok := ev.fields["error"].(error); !ok || !strings.Contains(err.Error(), "refresh failed") {
+			t.Errorf("log error field = %v, want refresh failure", ev.fields["error"])
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a refresh failure to be logged")
+	}
+}
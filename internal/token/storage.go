@@ -2,7 +2,7 @@
 //
 // Storage Strategy
 //
-// The package implements two primary token storage mechanisms:
+// The package implements two primary token storage mechanisms, plus an optional keyring backend:
 //
 // 1. Environment Variables (Primary Production Storage):
 //   - Recommended for production, headless, and Docker environments
@@ -20,14 +20,19 @@
 //   export GIT_TOKEN_GITHUB="your-token-here"  // For GitHub operations
 //   export GIT_TOKEN_GITLAB="your-token-here"  // For GitLab operations
 //
-// Note: System keychain integration was considered but intentionally not implemented
-// to optimize for headless and containerized environments where user interaction
-// and system integration would be problematic.
+// 3. OS Keyring (Optional Desktop Storage):
+//   - Built only with the "keyring" build tag (go build -tags keyring)
+//   - Stores tokens in the macOS Keychain, Windows Credential Manager, or Secret Service
+//   - Excluded from default builds to keep headless and containerized
+//     environments free of system integration and user interaction
 package token
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"math"
 	"time"
 )
 
@@ -39,6 +44,9 @@ var (
 	ErrTokenExpired       = errors.New("token has expired")
 	ErrStorageUnavailable = errors.New("token storage is unavailable")
 	ErrTokenRefreshFailed = errors.New("token refresh failed")
+	ErrUnknownProvider    = errors.New("no validator registered for provider")
+	ErrReadOnly           = errors.New("token storage is read-only")
+	ErrScopeMissing       = errors.New("token is missing required scopes")
 )
 
 // Token represents an authentication token with metadata
@@ -100,6 +108,45 @@ type Storage interface {
 	Close(ctx context.Context) error
 }
 
+// CompareAndSwapper is an optional interface for Storage implementations that
+// can atomically replace a token only if it still matches an expected value
+type CompareAndSwapper interface {
+	// CompareAndSwap stores new under key only if the currently stored token equals old
+	// Returns true if the swap happened, false if the stored token had changed
+	CompareAndSwap(ctx context.Context, key string, old, new Token) (bool, error)
+}
+
+// CompareAndSwap replaces the token stored under key with new only if the
+// currently stored token equals old. Storages implementing CompareAndSwapper
+// swap atomically; others are emulated with Retrieve followed by Store, which
+// narrows but does not eliminate the race window.
+func CompareAndSwap(ctx context.Context, s Storage, key string, old, new Token) (bool, error) {
+	if cas, ok := s.(CompareAndSwapper); ok {
+		return cas.CompareAndSwap(ctx, key, old, new)
+	}
+
+	current, err := s.Retrieve(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	if !Equal(current, old) {
+		return false, nil
+	}
+
+	if err := s.Store(ctx, key, new); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Equal reports whether two tokens have the same value and metadata
+func Equal(a, b Token) bool {
+	return a.Value == b.Value &&
+		a.Scope == b.Scope &&
+		a.ExpiresAt.Equal(b.ExpiresAt) &&
+		a.CreatedAt.Equal(b.CreatedAt)
+}
+
 // Validator provides methods to validate tokens
 type Validator interface {
 	// Validate checks if a token is valid
@@ -108,6 +155,38 @@ type Validator interface {
 	Validate(ctx context.Context, token *Token) error
 }
 
+// NeverExpires is the remaining validity reported for tokens without an expiration
+const NeverExpires = time.Duration(math.MaxInt64)
+
+// RemainingValidity returns how long the token remains valid
+// Returns NeverExpires for tokens without an expiration and a
+// negative duration for tokens that have already expired
+func (t Token) RemainingValidity() time.Duration {
+	if t.ExpiresAt.IsZero() {
+		return NeverExpires
+	}
+	return time.Until(t.ExpiresAt)
+}
+
+// IsHealthy reports whether the token is valid and remains valid for at least min
+func (t Token) IsHealthy(min time.Duration) bool {
+	return IsValid(t) && t.RemainingValidity() >= min
+}
+
+// fingerprintLength is the number of hex characters kept from the hash
+const fingerprintLength = 12
+
+// Fingerprint returns a short, stable identifier for the token value: the
+// first 12 hex characters of its SHA-256 hash. Use it in logs and as a cache
+// key wherever the token itself must not appear. Empty tokens return "".
+func (t Token) Fingerprint() string {
+	if t.Value == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(t.Value))
+	return hex.EncodeToString(sum[:])[:fingerprintLength]
+}
+
 // IsExpired checks if a token has expired
 func IsExpired(token Token) bool {
 	if token.ExpiresAt.IsZero() {
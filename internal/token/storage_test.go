@@ -1,6 +1,7 @@
 package token
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -92,3 +93,71 @@ func TestIsValid(t *testing.T) {
 		})
 	}
 }
+
+func TestToken_RemainingValidity(t *testing.T) {
+	tests := []struct {
+		name        string
+		token       Token
+		wantHealthy bool
+		check       func(time.Duration) bool
+	}{
+		{
+			name:        "never-expiring token",
+			token:       Token{Value: "test-token"},
+			wantHealthy: true,
+			check:       func(d time.Duration) bool { return d == NeverExpires },
+		},
+		{
+			name: "expiring-soon token",
+			token: Token{
+				Value:     "test-token",
+				ExpiresAt: time.Now().Add(1 * time.Hour),
+			},
+			wantHealthy: false,
+			check:       func(d time.Duration) bool { return d > 0 && d <= time.Hour },
+		},
+		{
+			name: "expired token",
+			token: Token{
+				Value:     "test-token",
+				ExpiresAt: time.Now().Add(-1 * time.Hour),
+			},
+			wantHealthy: false,
+			check:       func(d time.Duration) bool { return d < 0 },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.token.RemainingValidity()
+			if !tt.check(got) {
+				t.Errorf("RemainingValidity() = %v, unexpected for %s", got, tt.name)
+			}
+			if healthy := tt.token.IsHealthy(24 * time.Hour); healthy != tt.wantHealthy {
+				t.Errorf("IsHealthy(24h) = %v, want %v", healthy, tt.wantHealthy)
+			}
+		})
+	}
+}
+
+func TestToken_Fingerprint(t *testing.T) {
+	a := Token{Value: "ghp_first_token_value"}
+	b := Token{Value: "ghp_second_token_value"}
+
+	fp := a.Fingerprint()
+	if len(fp) != fingerprintLength {
+		t.Errorf("Fingerprint() length = %d, want %d", len(fp), fingerprintLength)
+	}
+	if fp != (Token{Value: a.Value, Scope: "repo"}).Fingerprint() {
+		t.Error("Fingerprint() is not stable for the same value")
+	}
+	if fp == b.Fingerprint() {
+		t.Error("Fingerprint() is the same for different values")
+	}
+	if strings.Contains(a.Value, fp) {
+		t.Error("Fingerprint() exposes the token value")
+	}
+	if got := (Token{}).Fingerprint(); got != "" {
+		t.Errorf("Fingerprint() of empty token = %q, want empty", got)
+	}
+}
@@ -0,0 +1,39 @@
+package token
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ValidatorFactory creates a Validator for a specific provider
+type ValidatorFactory func() Validator
+
+var (
+	validatorsMu sync.RWMutex
+	validators   = make(map[Provider]ValidatorFactory)
+)
+
+// RegisterValidator registers a validator factory for a provider.
+// Provider packages call this from init so that importing them is enough
+// to make their validator available through ValidatorFor.
+// Registering a provider twice replaces the previous factory.
+func RegisterValidator(provider Provider, factory ValidatorFactory) {
+	validatorsMu.Lock()
+	defer validatorsMu.Unlock()
+
+	validators[provider] = factory
+}
+
+// ValidatorFor returns a new Validator for the given provider
+// Returns ErrUnknownProvider if no validator has been registered for it
+func ValidatorFor(provider Provider) (Validator, error) {
+	validatorsMu.RLock()
+	factory, ok := validators[provider]
+	validatorsMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownProvider, provider)
+	}
+
+	return factory(), nil
+}
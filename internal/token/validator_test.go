@@ -0,0 +1,39 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// stubValidator implements Validator for registry tests
+type stubValidator struct{}
+
+func (stubValidator) Validate(_ context.Context, _ *Token) error {
+	return nil
+}
+
+func TestValidatorFor(t *testing.T) {
+	const provider Provider = "STUB"
+	RegisterValidator(provider, func() Validator { return stubValidator{} })
+
+	t.Run("registered provider", func(t *testing.T) {
+		v, err := ValidatorFor(provider)
+		if err != nil {
+			t.Fatalf("ValidatorFor() unexpected error: %v", err)
+		}
+		if _, ok := v.(stubValidator); !ok {
+			t.Errorf("ValidatorFor() returned %T, want stubValidator", v)
+		}
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		v, err := ValidatorFor("UNKNOWN")
+		if !errors.Is(err, ErrUnknownProvider) {
+			t.Errorf("ValidatorFor() error = %v, want ErrUnknownProvider", err)
+		}
+		if v != nil {
+			t.Errorf("ValidatorFor() returned %T, want nil", v)
+		}
+	})
+}
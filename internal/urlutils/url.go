@@ -1,6 +1,6 @@
 // Package urlutils provides utilities for handling GitHub repository URLs.
 // It supports parsing and validation of HTTPS URLs for both public GitHub
-// and GitHub Enterprise instances.
+// and GitHub Enterprise instances, as well as GitLab project URLs.
 package urlutils
 
 import (
@@ -9,6 +9,8 @@ import (
 	"net/url"
 	"regexp"
 	"strings"
+
+	"github.com/NicabarNimble/go-gittools/internal/token"
 )
 
 var (
@@ -31,6 +33,9 @@ var (
 	ownerRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9-]{0,35}$`)
 	repoRegex  = regexp.MustCompile(`^[a-zA-Z0-9_.-]{1,100}$`)
 
+	// GitLab group and project path segments
+	gitlabSegmentRegex = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_.-]{0,254}$`)
+
 	// Allowed GitHub Enterprise Server domains
 	// This should be configured by the organization
 	allowedGHEDomains = map[string]bool{
@@ -83,8 +88,69 @@ func ParseHTTPSURL(rawURL string) (*url.URL, error) {
 	return parsedURL, nil
 }
 
-// FormatTokenURL formats a GitHub URL with the provided token.
+// ParseGitLabURL parses and validates a GitLab HTTPS project URL.
+// It accepts URLs in the following formats:
+//   - https://gitlab.com/group/project
+//   - https://gitlab.com/group/subgroup/project.git
+//   - https://gitlab.example.com/group/project
+func ParseGitLabURL(rawURL string) (*url.URL, error) {
+	if strings.HasPrefix(rawURL, "git@") {
+		return nil, ErrNotHTTPS
+	}
+	if !strings.HasPrefix(rawURL, "https://") {
+		return nil, ErrInvalidURL
+	}
+
+	rawURL = sanitizeURL(strings.TrimSuffix(rawURL, ".git"))
+
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+
+	if !isGitLabHost(parsedURL.Host) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidHost, parsedURL.Host)
+	}
+
+	pathParts := strings.Split(strings.Trim(parsedURL.Path, "/"), "/")
+	if len(pathParts) < 2 {
+		return nil, fmt.Errorf("%w: URL must include group and project", ErrInvalidPath)
+	}
+	for _, part := range pathParts {
+		if !gitlabSegmentRegex.MatchString(part) {
+			return nil, fmt.Errorf("%w: invalid group or project name format", ErrInvalidPath)
+		}
+	}
+
+	return parsedURL, nil
+}
+
+// DetectProvider determines the hosting provider of a repository URL from
+// its host. It returns ErrInvalidHost for hosts that are neither GitHub nor
+// GitLab.
+func DetectProvider(rawURL string) (token.Provider, error) {
+	if strings.HasPrefix(rawURL, "git@") {
+		return "", ErrNotHTTPS
+	}
+
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+
+	switch {
+	case isValidGitHubHost(parsedURL.Host):
+		return token.ProviderGitHub, nil
+	case isGitLabHost(parsedURL.Host):
+		return token.ProviderGitLab, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrInvalidHost, parsedURL.Host)
+	}
+}
+
+// FormatTokenURL formats a repository URL with the provided token.
 // It creates a new URL with the token embedded as the user info component.
+// GitLab hosts expect the token as the password of the "oauth2" user.
 // The original URL is not modified.
 func FormatTokenURL(parsedURL *url.URL, token string) (*url.URL, error) {
 	if parsedURL == nil {
@@ -98,40 +164,61 @@ func FormatTokenURL(parsedURL *url.URL, token string) (*url.URL, error) {
 	// Create a copy and sanitize
 	tokenURL := *parsedURL
 	tokenURL.User = nil // Clear any existing credentials
-	tokenURL.User = url.User(token)
+	if isGitLabHost(tokenURL.Host) {
+		tokenURL.User = url.UserPassword("oauth2", token)
+	} else {
+		tokenURL.User = url.User(token)
+	}
 
 	return &tokenURL, nil
 }
 
-// ValidateURL checks if the provided URL is a valid GitHub repository URL.
-// It performs comprehensive validation including:
+// ValidateURL checks if the provided URL is a valid GitHub repository or
+// GitLab project URL. It performs comprehensive validation including:
 //   - URL format and protocol
-//   - GitHub host validation
+//   - GitHub or GitLab host validation
 //   - Owner and repository name format
 //   - Path structure
 func ValidateURL(rawURL string) error {
+	if provider, err := DetectProvider(rawURL); err == nil && provider == token.ProviderGitLab {
+		_, err := ParseGitLabURL(rawURL)
+		return err
+	}
 	_, err := ParseHTTPSURL(rawURL)
 	return err
 }
 
+// IsPublicGitHubHost reports whether host is served by the github.com API,
+// which covers github.com itself and GitHub Enterprise Cloud subdomains
+func IsPublicGitHubHost(host string) bool {
+	return host == "github.com" || strings.HasSuffix(host, ".github.com")
+}
+
+// IsGitHubEnterpriseHost reports whether host is an allowed GitHub
+// Enterprise Server domain with its own API
+func IsGitHubEnterpriseHost(host string) bool {
+	return allowedGHEDomains[host]
+}
+
 // isValidGitHubHost checks if the host is github.com or an allowed GitHub Enterprise host.
 // It supports the following formats:
 //   - github.com (Public GitHub)
 //   - *.github.com (GitHub Enterprise Cloud)
 //   - Explicitly allowed GitHub Enterprise Server domains
 func isValidGitHubHost(host string) bool {
-	// Public GitHub
-	if host == "github.com" {
-		return true
-	}
-
-	// GitHub Enterprise Cloud
-	if strings.HasSuffix(host, ".github.com") {
+	// Public GitHub and GitHub Enterprise Cloud
+	if IsPublicGitHubHost(host) {
 		return true
 	}
 
 	// GitHub Enterprise Server - only allow explicitly configured domains
-	return allowedGHEDomains[host]
+	return IsGitHubEnterpriseHost(host)
+}
+
+// isGitLabHost checks if the host is gitlab.com or a self-managed GitLab
+// instance following the common gitlab.<domain> naming
+func isGitLabHost(host string) bool {
+	return host == "gitlab.com" || strings.HasPrefix(host, "gitlab.")
 }
 
 // sanitizeURL removes any sensitive information from the URL
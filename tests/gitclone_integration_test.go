@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NicabarNimble/go-gittools/internal/github"
+	"github.com/NicabarNimble/go-gittools/internal/github/githubtest"
+	"github.com/NicabarNimble/go-gittools/internal/gitutils"
+)
+
+func TestGitCloneWorkflow(t *testing.T) {
+	// Skip in CI environment
+	if os.Getenv("CI") != "" {
+		t.Skip("Skipping integration test in CI environment")
+	}
+
+	baseDir := t.TempDir()
+
+	// Source repository with a workflow that must not reach the target
+	sourceDir := SetupTestRepo(t, "source")
+	if err := os.MkdirAll(filepath.Join(sourceDir, ".github", "workflows"), 0755); err != nil {
+		t.Fatalf("Failed to create workflows directory: %v", err)
+	}
+	AddCommit(t, sourceDir, ".github/workflows/ci.yml", "name: CI\n", "Add CI workflow")
+
+	sourceBare := filepath.Join(baseDir, "source.git")
+	if err := runCommand(baseDir, "git", "clone", "--bare", sourceDir, sourceBare); err != nil {
+		t.Fatalf("Failed to create bare source repository: %v", err)
+	}
+	targetBare := filepath.Join(baseDir, "target.git")
+	if err := runCommand(baseDir, "git", "init", "--bare", targetBare); err != nil {
+		t.Fatalf("Failed to create bare target repository: %v", err)
+	}
+
+	// The GitHub API calls go to an in-memory fake
+	fake := githubtest.NewFakeServer()
+	defer fake.Close()
+
+	err := gitutils.CloneRepository(gitutils.CloneOptions{
+		SourceURL:     "file://" + sourceBare,
+		TargetURL:     "file://" + targetBare,
+		Token:         "ghp_integration-test-token",
+		CustomName:    "mirror",
+		GitHubOptions: []github.ClientOption{github.WithBaseURL(fake.URL())},
+	})
+	if err != nil {
+		t.Fatalf("CloneRepository failed: %v", err)
+	}
+
+	if _, ok := fake.Repository(githubtest.DefaultLogin + "/mirror"); !ok {
+		t.Errorf("Expected target repository to be created, got %v", fake.Repositories())
+	}
+
+	// Verify the content arrived with workflows stripped
+	checkout := filepath.Join(baseDir, "checkout")
+	if err := runCommand(baseDir, "git", "clone", "--branch", "main", targetBare, checkout); err != nil {
+		t.Fatalf("Failed to clone target repository: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(checkout, "test.txt"))
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+	if string(content) != "test content" {
+		t.Errorf("Unexpected file content: %s", content)
+	}
+	if _, err := os.Stat(filepath.Join(checkout, ".github", "workflows")); !os.IsNotExist(err) {
+		t.Errorf("Expected workflows to be removed from the target, stat error: %v", err)
+	}
+
+	// No commit of the target may contain them either
+	out, err := exec.Command("git", "-C", checkout, "log", "--all", "--format=%H", "--", ".github/workflows").Output()
+	if err != nil {
+		t.Fatalf("Failed to read target log: %v", err)
+	}
+	if leaked := strings.TrimSpace(string(out)); leaked != "" {
+		t.Errorf("Expected workflows to be removed from the target history, found them in %s", leaked)
+	}
+}
@@ -106,17 +106,13 @@ func testPublishWorkflow(t *testing.T) {
 		t.Fatalf("Failed to clone private repository: %v", err)
 	}
 
-	// Add public as remote and push
-	if err := runCommand(tempDir, "git", "remote", "add", "public", "file://" + publicDir); err != nil {
-		t.Fatalf("Failed to add public remote: %v", err)
-	}
-
-	// Push to public remote with force to handle any conflicts
-	cmd := exec.Command("git", "push", "-f", "public", cfg.Branch)
-	cmd.Dir = tempDir
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		t.Fatalf("Failed to push to public fork: %v\nOutput: %s", err, output)
+	// Push to the public fork with force to handle any conflicts
+	if err := git.PushRepository(tempDir, git.CloneOptions{
+		TargetURL: "file://" + publicDir,
+		Refspec:   cfg.Branch,
+		Force:     true,
+	}); err != nil {
+		t.Fatalf("Failed to push to public fork: %v", err)
 	}
 
 	// Verify publish operation
@@ -131,9 +127,9 @@ func testPublishWorkflow(t *testing.T) {
 	}
 
 	// Debug: List branches and current branch
-	cmd = exec.Command("git", "branch", "-a")
+	cmd := exec.Command("git", "branch", "-a")
 	cmd.Dir = publicCloneDir
-	output, err = cmd.CombinedOutput()
+	output, err := cmd.CombinedOutput()
 	if err != nil {
 		t.Fatalf("Failed to list branches: %v\nOutput: %s", err, output)
 	}
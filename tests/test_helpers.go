@@ -64,8 +64,10 @@ func SetupGitConfig(t *testing.T, dir string) {
 	}
 }
 
-// SetupCredentials configures HTTPS authentication credentials for testing
-func SetupCredentials(t *testing.T) {
+// SetupCredentials configures HTTPS authentication credentials for the
+// repository in dir. The helper is set in the repository's own config since
+// runCommand points the global config at /dev/null.
+func SetupCredentials(t *testing.T, dir string) {
 	t.Helper()
 
 	credentialsDir := filepath.Join(t.TempDir(), ".git-credentials")
@@ -78,7 +80,7 @@ func SetupCredentials(t *testing.T) {
 		t.Fatalf("Failed to write credentials file: %v", err)
 	}
 
-	if err := runCommand("", "git", "config", "--global", "credential.helper", "store --file="+credentialsDir); err != nil {
+	if err := runCommand(dir, "git", "config", "credential.helper", "store --file="+credentialsDir); err != nil {
 		t.Fatalf("Failed to configure credential helper: %v", err)
 	}
 }
@@ -106,7 +108,7 @@ func SetupTestRepo(t *testing.T, name string) string {
 
 	// Configure git settings
 	SetupGitConfig(t, dir)
-	SetupCredentials(t)
+	SetupCredentials(t, dir)
 
 	// Add and commit file
 	if err := runCommand(dir, "git", "add", "test.txt"); err != nil {